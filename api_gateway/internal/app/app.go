@@ -9,6 +9,7 @@ import (
 	"OLO-backend/api_gateway/internal/entity"
 	pauth "OLO-backend/auth_service/generated"
 	polo "OLO-backend/olo_service/generated"
+	"OLO-backend/pkg/utils/logger/sl"
 	"context"
 	"fmt"
 	"log/slog"
@@ -76,6 +77,17 @@ func (app *App) Start() {
 		if err != nil {
 			logger.Error("Failed to register service: %v", err)
 		}
+
+		// Выгрузка данных отдаётся файлом, а не потоком JSON, поэтому у неё свой обработчик
+		conn, err := grpc.NewClient(formattedAddr, opts...)
+		if err != nil {
+			logger.Error("failed to connect to olo service", sl.Err(err))
+			return
+		}
+		err = mux.HandlePath(http.MethodGet, "/api/olo/export", app.exportHandler(mux, polo.NewOLOClient(conn)))
+		if err != nil {
+			logger.Error("failed to register export handler", sl.Err(err))
+		}
 	})
 
 	withCors := cors.New(cors.Options{
@@ -0,0 +1,56 @@
+package app
+
+import (
+	polo "OLO-backend/olo_service/generated"
+	"OLO-backend/pkg/utils/logger/sl"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+// exportHandler returns the handler of GET /api/olo/export, it downloads the data export
+// the OLO service streams as a ZIP file.
+func (app *App) exportHandler(mux *runtime.ServeMux, client polo.OLOClient) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+		ctx := r.Context()
+		if token := r.Header.Get("Authorization"); token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+		}
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		stream, err := client.ExportMyData(ctx, &polo.ExportMyDataRequest{})
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		// Заголовки отправляются после первой части архива, чтобы ошибка до начала выгрузки вернулась со своим статусом
+		chunk, err := stream.Recv()
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="olo-export-%s.zip"`, time.Now().Format(time.DateOnly)))
+		for {
+			if _, err := w.Write(chunk.GetData()); err != nil {
+				return
+			}
+			chunk, err = stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				// Статус уже отправлен, обрыв соединения не даёт клиенту принять неполный архив за целый
+				app.log.Error("export stream failed", sl.Err(err))
+				panic(http.ErrAbortHandler)
+			}
+		}
+	}
+}
@@ -4,10 +4,15 @@ package app
 
 import (
 	"OLO-backend/auth_service/internal/config"
+	"OLO-backend/auth_service/internal/grpc/authgrpc"
+	"OLO-backend/auth_service/internal/mail"
+	"OLO-backend/auth_service/internal/olo"
 	"OLO-backend/auth_service/internal/service/auth"
 	"OLO-backend/auth_service/internal/service/grpc"
 	"OLO-backend/auth_service/internal/storage"
 	"OLO-backend/pkg/utils/jwt"
+	"OLO-backend/pkg/utils/policy"
+	"fmt"
 	"log/slog"
 )
 
@@ -20,12 +25,12 @@ type App struct {
 // New creates a new instance of the application.
 // New создает новый экземпляр приложения.
 func New(log *slog.Logger, cfg *config.Config) *App {
-	var uStorage storage.UserStorage
+	var mysqlStorage *storage.InMysqlStorage
 
 	// Initialize user storage based on data provider
 	// Инициализация хранилища пользователей на основе провайдера данных
 	if cfg.DataProvider == "mysql" {
-		uStorage = storage.NewInAuthMysqlStorage(log,
+		mysqlStorage = storage.NewInAuthMysqlStorage(log,
 			cfg.MySQLSettings.Address,
 			cfg.MySQLSettings.Username,
 			cfg.MySQLSettings.Password,
@@ -48,13 +53,60 @@ func New(log *slog.Logger, cfg *config.Config) *App {
 		panic(err)
 	}
 
+	// Share revoked tokens with other services through the database
+	// Общий с другими сервисами список отозванных токенов в базе данных
+	switch cfg.RevocationStore {
+	case "mysql":
+		revocations, err := jwt.NewMySQLRevocationStore(mysqlStorage.DB())
+		if err != nil {
+			panic(err)
+		}
+		validator.SetRevocationStore(revocations)
+	case "memory":
+	default:
+		panic("unknown revocation store " + cfg.RevocationStore)
+	}
+
+	// Initialize access policy
+	// Инициализация политики доступа
+	enforcer, err := policy.NewEnforcer(cfg.Policy)
+	if err != nil {
+		panic(err)
+	}
+
+	// Initialize mail sender
+	// Инициализация отправителя писем
+	mailer, err := mail.New(cfg.Mail)
+	if err != nil {
+		panic(err)
+	}
+
 	// Initialize authentication service
 	// Инициализация сервиса аутентификации
-	authService := auth.New(log, uStorage, issuer, validator, cfg.TokenTTL)
+	authService := auth.New(log, mysqlStorage, mysqlStorage, mysqlStorage, mysqlStorage, mysqlStorage, mailer, issuer, validator, enforcer,
+		cfg.TokenTTL, cfg.RefreshTokenTTL, cfg.ResetTokenTTL)
+	authService.SetEmailVerification(cfg.VerificationTokenTTL, cfg.RequireVerifiedEmail)
+	authService.SetTwoFactor(cfg.TwoFactor.Issuer, cfg.TwoFactor.ChallengeTTL)
+	authService.SetLoginLimits(cfg.LoginLimits.Account, cfg.LoginLimits.IP)
+
+	// Deleted accounts are purged in the olo service
+	// Удалённые аккаунты удаляются и в сервисе olo
+	oloClient, err := olo.NewClient(fmt.Sprintf("%s:%d", cfg.OloService.Host, cfg.OloService.Port), issuer)
+	if err != nil {
+		panic(err)
+	}
+	authService.AddDeletionHook(oloClient)
+
+	// Client addresses are only taken from x-forwarded-for of the api gateway
+	// Адреса клиентов берутся из x-forwarded-for только от api gateway
+	trustedProxies, err := authgrpc.ParseProxies(cfg.GRPC.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
 
 	// Initialize gRPC application
 	// Инициализация gRPC приложения
-	grpcApp := grpc.New(log, cfg.GRPC.Port, authService)
+	grpcApp := grpc.New(log, cfg.GRPC.Port, authService, validator, enforcer, trustedProxies)
 
 	// Return the application instance
 	// Возвращение экземпляра приложения
@@ -3,6 +3,9 @@
 package config
 
 import (
+	"OLO-backend/auth_service/internal/limiter"
+	"OLO-backend/auth_service/internal/mail"
+	"OLO-backend/pkg/utils/policy"
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
@@ -11,16 +14,46 @@ import (
 
 // Config represents the application configuration.
 type Config struct {
-	Env           string        `yaml:"env" env-default:"local"`
-	DataProvider  string        `yaml:"data_provider" env-required:"true"`
-	GRPC          GRPCConfig    `yaml:"grpc"`
-	MySQLSettings MySQLConfig   `yaml:"mysql_settings"`
-	TokenTTL      time.Duration `yaml:"token_ttl"`
+	Env             string        `yaml:"env" env-default:"local"`
+	DataProvider    string        `yaml:"data_provider" env-required:"true"`
+	GRPC            GRPCConfig    `yaml:"grpc"`
+	MySQLSettings   MySQLConfig   `yaml:"mysql_settings"`
+	TokenTTL        time.Duration `yaml:"token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	RevocationStore string        `yaml:"revocation_store" env-default:"memory"` // mysql or memory
+	Policy          policy.Config `yaml:"policy"`
+	ResetTokenTTL   time.Duration `yaml:"reset_token_ttl" env-default:"1h"`
+	Mail            mail.Config   `yaml:"mail"`
+
+	VerificationTokenTTL time.Duration     `yaml:"verification_token_ttl" env-default:"24h"`
+	RequireVerifiedEmail bool              `yaml:"require_verified_email" env-default:"false"` // Login rejects unverified emails
+	OloService           OloServiceConfig  `yaml:"olo_service"`
+	TwoFactor            TwoFactorConfig   `yaml:"two_factor"`
+	LoginLimits          LoginLimitsConfig `yaml:"login_limits"`
+}
+
+// LoginLimitsConfig represents the limits of the failed logins, the defaults are used if they are omitted.
+type LoginLimitsConfig struct {
+	Account limiter.Config `yaml:"account"` // failed logins of an email
+	IP      limiter.Config `yaml:"ip"`      // failed logins from a client address
+}
+
+// TwoFactorConfig represents the settings of the TOTP two-factor authentication.
+type TwoFactorConfig struct {
+	Issuer       string        `yaml:"issuer" env-default:"OLO"`       // name of the account in authenticator apps
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"5m"` // time to enter the code after the password
+}
+
+// OloServiceConfig represents the address of the olo service the data of deleted accounts is purged in.
+type OloServiceConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
 }
 
 // GRPCConfig represents gRPC configuration.
 type GRPCConfig struct {
-	Port int `yaml:"port"`
+	Port           int      `yaml:"port"`
+	TrustedProxies []string `yaml:"trusted_proxies"` // IPs or CIDR ranges of the api gateway
 }
 
 // MySQLConfig represents MySQL database configuration.
@@ -0,0 +1,12 @@
+package models
+
+// EmailVerification represents a stored email verification token.
+// Only the hash of the token is kept, the token itself is sent to the user by email.
+type EmailVerification struct {
+	ID        int64  `db:"id"`
+	TokenHash string `db:"token_hash"`
+	UserID    int64  `db:"user_id"`
+	ExpiresAt int64  `db:"expires_at"`
+	Used      bool   `db:"used"`
+	CreatedAt int64  `db:"created_at"`
+}
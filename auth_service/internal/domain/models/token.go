@@ -0,0 +1,23 @@
+package models
+
+// RefreshToken represents a stored refresh token.
+// Only the hash of the token is kept, the token itself is known to the client only.
+// Tokens issued one after another through rotation share the same FamilyID.
+type RefreshToken struct {
+	ID        int64  `db:"id"`
+	TokenHash string `db:"token_hash"`
+	FamilyID  string `db:"family_id"`
+	UserID    int64  `db:"user_id"`
+	AppID     int    `db:"app_id"`
+	ExpiresAt int64  `db:"expires_at"`
+	Used      bool   `db:"used"`
+	Revoked   bool   `db:"revoked"`
+}
+
+// TokenPair represents a pair of access and refresh tokens returned to the client.
+// A login that needs the second factor returns only a ChallengeToken instead.
+type TokenPair struct {
+	AccessToken    string
+	RefreshToken   string
+	ChallengeToken string
+}
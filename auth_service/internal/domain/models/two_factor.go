@@ -0,0 +1,24 @@
+package models
+
+// TwoFactor represents the TOTP second factor of a user.
+// It is enabled once the user confirms a code of the secret.
+type TwoFactor struct {
+	UserID    int64  `db:"user_id"`
+	Secret    string `db:"secret"`
+	Enabled   bool   `db:"enabled"`
+	LastStep  int64  `db:"last_step"` // time step of the last accepted code, codes can't be reused
+	CreatedAt int64  `db:"created_at"`
+}
+
+// LoginChallenge represents a stored challenge of a login waiting for the second factor.
+// Only the hash of the token is kept, the token itself is returned by Login.
+type LoginChallenge struct {
+	ID        int64  `db:"id"`
+	TokenHash string `db:"token_hash"`
+	UserID    int64  `db:"user_id"`
+	AppID     int    `db:"app_id"`
+	ExpiresAt int64  `db:"expires_at"`
+	Attempts  int    `db:"attempts"` // wrong codes entered for the challenge
+	Used      bool   `db:"used"`
+	CreatedAt int64  `db:"created_at"`
+}
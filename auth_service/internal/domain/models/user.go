@@ -7,4 +7,5 @@ type User struct {
 	Role         string `db:"role"`
 	PassHash     []byte `db:"password_hash"`
 	DateRegister string `db:"date_register"`
+	Verified     bool   `db:"verified"` // the user confirmed the email with a verification token
 }
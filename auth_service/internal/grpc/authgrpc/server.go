@@ -4,10 +4,19 @@ package authgrpc
 import (
 	"OLO-backend/auth_service/generated"
 	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/auth_service/internal/service/auth"
+	"OLO-backend/auth_service/internal/storage"
+	"OLO-backend/pkg/model"
 	"context"
+	"errors"
+	"fmt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"net/netip"
+	"strings"
 )
 
 // emptyValue represents an empty value for comparison.
@@ -15,23 +24,57 @@ const emptyValue = 0
 
 // Auth defines methods for authentication.
 type Auth interface {
-	Login(email string, password string, appID int) (token string, err error)
-	RegisterNewUser(email string, password string) (int64, error)
+	Login(email string, password string, appID int, clientIP string) (models.TokenPair, error)
+	Refresh(refreshToken string) (models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAllSessions(ctx context.Context) error
+	RegisterNewUser(ctx context.Context, email string, password string) (int64, error)
+	VerifyEmail(token string) error
+	ChangePassword(ctx context.Context, password string, newPassword string) error
+	ChangeEmail(ctx context.Context, password string, newEmail string) error
+	DeleteAccount(ctx context.Context, password string) error
+	Login2FA(challengeToken string, code string, clientIP string) (models.TokenPair, error)
+	Enable2FA(ctx context.Context, password string) (string, string, error)
+	Confirm2FA(ctx context.Context, code string) ([]string, error)
+	Disable2FA(ctx context.Context, password string, code string) error
 	GetUserInfo(ctx context.Context) (*models.User, error)
+	GrantRole(ctx context.Context, userID int64, role string) error
+	RevokeRole(ctx context.Context, userID int64, role string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ConfirmPasswordReset(token string, password string) error
 }
 
 // serverAPI implements the generated.AuthServer interface.
 type serverAPI struct {
 	generated.UnimplementedAuthServer
-	auth Auth
+	auth           Auth
+	trustedProxies []netip.Prefix
 }
 
 // Register registers the authentication service with the gRPC server.
-func Register(gRPC *grpc.Server, auth Auth) {
-	generated.RegisterAuthServer(gRPC, &serverAPI{auth: auth})
+// Client addresses forwarded by the trusted proxies are used for the login limits.
+func Register(gRPC *grpc.Server, auth Auth, trustedProxies []netip.Prefix) {
+	generated.RegisterAuthServer(gRPC, &serverAPI{auth: auth, trustedProxies: trustedProxies})
 }
 
-// Login authenticates a user and returns a token.
+// ParseProxies parses the trusted proxy addresses, each one is an IP or a CIDR range.
+func ParseProxies(proxies []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Login authenticates a user and returns a pair of access and refresh tokens.
 func (s *serverAPI) Login(ctx context.Context, req *generated.LoginRequest) (*generated.LoginResponse, error) {
 	if req.GetEmail() == "" {
 		return nil, status.Error(codes.InvalidArgument, "email is required")
@@ -43,14 +86,65 @@ func (s *serverAPI) Login(ctx context.Context, req *generated.LoginRequest) (*ge
 		return nil, status.Error(codes.InvalidArgument, "app id is required")
 	}
 
-	token, err := s.auth.Login(req.GetEmail(), req.GetPassword(), int(req.GetAppId()))
+	tokens, err := s.auth.Login(req.GetEmail(), req.GetPassword(), int(req.GetAppId()), s.clientIP(ctx))
+
+	if err != nil {
+		return nil, loginError(err)
+	}
+
+	return &generated.LoginResponse{
+		Token:          tokens.AccessToken,
+		RefreshToken:   tokens.RefreshToken,
+		ChallengeToken: tokens.ChallengeToken,
+	}, nil
+}
+
+// Login2FA redeems a login challenge with a two-factor code and returns a pair of tokens.
+func (s *serverAPI) Login2FA(ctx context.Context, req *generated.Login2FARequest) (*generated.LoginResponse, error) {
+	if req.GetChallengeToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge token is required")
+	}
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
 
+	tokens, err := s.auth.Login2FA(req.GetChallengeToken(), req.GetCode(), s.clientIP(ctx))
 	if err != nil {
+		var tooMany *auth.TooManyAttemptsError
+		switch {
+		case errors.As(err, &tooMany):
+			return nil, status.Error(codes.ResourceExhausted, tooMany.Error())
+		case errors.Is(err, auth.ErrInvalidChallenge):
+			return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidChallenge.Error())
+		case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+			return nil, status.Error(codes.Unauthenticated, auth.ErrInvalidTwoFactorCode.Error())
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
 	return &generated.LoginResponse{
-		Token: token,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// Refresh rotates a refresh token and returns a new pair of tokens.
+func (s *serverAPI) Refresh(ctx context.Context, req *generated.RefreshRequest) (*generated.LoginResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh token is required")
+	}
+
+	tokens, err := s.auth.Refresh(req.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+
+	return &generated.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 	}, nil
 }
 
@@ -60,9 +154,15 @@ func (s *serverAPI) Register(ctx context.Context, req *generated.RegisterRequest
 		return nil, err
 	}
 
-	userID, err := s.auth.RegisterNewUser(req.GetEmail(), req.GetPassword())
+	userID, err := s.auth.RegisterNewUser(ctx, req.GetEmail(), req.GetPassword())
 
 	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidEmail):
+			return nil, status.Error(codes.InvalidArgument, auth.ErrInvalidEmail.Error())
+		case errors.Is(err, storage.ErrUserExist):
+			return nil, status.Error(codes.AlreadyExists, "user already exists")
+		}
 		return nil, status.Error(codes.Internal, "internal error")
 	}
 
@@ -71,20 +171,304 @@ func (s *serverAPI) Register(ctx context.Context, req *generated.RegisterRequest
 	}, nil
 }
 
+// Logout ends the current session of the user.
+func (s *serverAPI) Logout(ctx context.Context, req *generated.LogoutRequest) (*generated.LogoutResponse, error) {
+	if err := s.auth.Logout(ctx, req.GetRefreshToken()); err != nil {
+		return nil, authError(err)
+	}
+	return &generated.LogoutResponse{}, nil
+}
+
+// LogoutAllSessions ends all sessions of the user.
+func (s *serverAPI) LogoutAllSessions(ctx context.Context, _ *generated.LogoutAllSessionsRequest) (*generated.LogoutResponse, error) {
+	if err := s.auth.LogoutAllSessions(ctx); err != nil {
+		return nil, authError(err)
+	}
+	return &generated.LogoutResponse{}, nil
+}
+
 // GetUserInfo return information about user
 func (s *serverAPI) GetUserInfo(ctx context.Context, _ *generated.GetUserInfoRequest) (*generated.GetUserInfoResponse, error) {
 	user, err := s.auth.GetUserInfo(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, authError(err)
 	}
 	return &generated.GetUserInfoResponse{
 		UserId:       user.ID,
 		Email:        user.Email,
 		Role:         user.Role,
 		DateRegister: user.DateRegister,
+		Verified:     user.Verified,
 	}, nil
 }
 
+// VerifyEmail confirms the email of a user by a verification token.
+func (s *serverAPI) VerifyEmail(ctx context.Context, req *generated.VerifyEmailRequest) (*generated.VerifyEmailResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.auth.VerifyEmail(req.GetToken()); err != nil {
+		if errors.Is(err, auth.ErrInvalidVerification) {
+			return nil, status.Error(codes.InvalidArgument, auth.ErrInvalidVerification.Error())
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &generated.VerifyEmailResponse{}, nil
+}
+
+// GrantRole assigns a role to a user.
+func (s *serverAPI) GrantRole(ctx context.Context, req *generated.RoleRequest) (*generated.RoleResponse, error) {
+	if err := validateRole(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.GrantRole(ctx, req.GetUserId(), req.GetRole()); err != nil {
+		return nil, roleError(err)
+	}
+	return &generated.RoleResponse{}, nil
+}
+
+// RevokeRole takes a role away from a user.
+func (s *serverAPI) RevokeRole(ctx context.Context, req *generated.RoleRequest) (*generated.RoleResponse, error) {
+	if err := validateRole(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.auth.RevokeRole(ctx, req.GetUserId(), req.GetRole()); err != nil {
+		return nil, roleError(err)
+	}
+	return &generated.RoleResponse{}, nil
+}
+
+// RequestPasswordReset emails a password reset token to the user.
+func (s *serverAPI) RequestPasswordReset(ctx context.Context, req *generated.RequestPasswordResetRequest) (*generated.RequestPasswordResetResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.auth.RequestPasswordReset(ctx, req.GetEmail()); err != nil {
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &generated.RequestPasswordResetResponse{}, nil
+}
+
+// ConfirmPasswordReset sets a new password by a password reset token.
+func (s *serverAPI) ConfirmPasswordReset(ctx context.Context, req *generated.ConfirmPasswordResetRequest) (*generated.ConfirmPasswordResetResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new password is required")
+	}
+
+	if err := s.auth.ConfirmPasswordReset(req.GetToken(), req.GetNewPassword()); err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			return nil, status.Error(codes.InvalidArgument, auth.ErrInvalidResetToken.Error())
+		}
+		return nil, status.Error(codes.Internal, "internal error")
+	}
+	return &generated.ConfirmPasswordResetResponse{}, nil
+}
+
+// ChangePassword sets a new password of the user.
+func (s *serverAPI) ChangePassword(ctx context.Context, req *generated.ChangePasswordRequest) (*generated.ChangePasswordResponse, error) {
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+	if req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new password is required")
+	}
+
+	if err := s.auth.ChangePassword(ctx, req.GetPassword(), req.GetNewPassword()); err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.ChangePasswordResponse{}, nil
+}
+
+// ChangeEmail sets a new email of the user.
+func (s *serverAPI) ChangeEmail(ctx context.Context, req *generated.ChangeEmailRequest) (*generated.ChangeEmailResponse, error) {
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+	if req.GetNewEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new email is required")
+	}
+
+	if err := s.auth.ChangeEmail(ctx, req.GetPassword(), req.GetNewEmail()); err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.ChangeEmailResponse{}, nil
+}
+
+// DeleteAccount deletes the user with their data in other services.
+func (s *serverAPI) DeleteAccount(ctx context.Context, req *generated.DeleteAccountRequest) (*generated.DeleteAccountResponse, error) {
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	if err := s.auth.DeleteAccount(ctx, req.GetPassword()); err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.DeleteAccountResponse{}, nil
+}
+
+// Enable2FA generates a TOTP secret of the user.
+func (s *serverAPI) Enable2FA(ctx context.Context, req *generated.Enable2FARequest) (*generated.Enable2FAResponse, error) {
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	secret, uri, err := s.auth.Enable2FA(ctx, req.GetPassword())
+	if err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.Enable2FAResponse{
+		Secret: secret,
+		Uri:    uri,
+	}, nil
+}
+
+// Confirm2FA enables two-factor authentication of the user.
+func (s *serverAPI) Confirm2FA(ctx context.Context, req *generated.Confirm2FARequest) (*generated.Confirm2FAResponse, error) {
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	recoveryCodes, err := s.auth.Confirm2FA(ctx, req.GetCode())
+	if err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.Confirm2FAResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// Disable2FA turns two-factor authentication of the user off.
+func (s *serverAPI) Disable2FA(ctx context.Context, req *generated.Disable2FARequest) (*generated.Disable2FAResponse, error) {
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if err := s.auth.Disable2FA(ctx, req.GetPassword(), req.GetCode()); err != nil {
+		return nil, accountError(err)
+	}
+	return &generated.Disable2FAResponse{}, nil
+}
+
+// accountError converts an error of an account management call to a gRPC status error.
+// A wrong current password is PermissionDenied, so clients don't take it for an expired session.
+func accountError(err error) error {
+	var tooMany *auth.TooManyAttemptsError
+	switch {
+	case errors.As(err, &tooMany):
+		return status.Error(codes.ResourceExhausted, tooMany.Error())
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return status.Error(codes.PermissionDenied, "invalid password")
+	case errors.Is(err, auth.ErrInvalidEmail):
+		return status.Error(codes.InvalidArgument, auth.ErrInvalidEmail.Error())
+	case errors.Is(err, auth.ErrSameEmail):
+		return status.Error(codes.InvalidArgument, auth.ErrSameEmail.Error())
+	case errors.Is(err, storage.ErrUserExist):
+		return status.Error(codes.AlreadyExists, "email is taken")
+	case errors.Is(err, auth.ErrInvalidTwoFactorCode):
+		return status.Error(codes.InvalidArgument, auth.ErrInvalidTwoFactorCode.Error())
+	case errors.Is(err, auth.ErrTwoFactorEnabled):
+		return status.Error(codes.FailedPrecondition, auth.ErrTwoFactorEnabled.Error())
+	case errors.Is(err, auth.ErrTwoFactorDisabled):
+		return status.Error(codes.FailedPrecondition, auth.ErrTwoFactorDisabled.Error())
+	}
+	return authError(err)
+}
+
+// loginError converts an error of a login call to a gRPC status error.
+// An unverified email has its own code, so clients can offer to verify it.
+func loginError(err error) error {
+	var tooMany *auth.TooManyAttemptsError
+	switch {
+	case errors.As(err, &tooMany):
+		return status.Error(codes.ResourceExhausted, tooMany.Error())
+	case errors.Is(err, auth.ErrEmailNotVerified):
+		return status.Error(codes.FailedPrecondition, auth.ErrEmailNotVerified.Error())
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		return status.Error(codes.Unauthenticated, auth.ErrInvalidCredentials.Error())
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// roleError converts an error of a role management call to a gRPC status error.
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, auth.ErrUnknownRole):
+		return status.Error(codes.InvalidArgument, "unknown role")
+	case errors.Is(err, auth.ErrRoleNotAssigned):
+		return status.Error(codes.FailedPrecondition, "role is not assigned to the user")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// authError converts an error of an authenticated call to a gRPC status error.
+func authError(err error) error {
+	if errors.Is(err, auth.ErrUnauthenticated) || errors.Is(err, model.ErrInvalidClaims) {
+		return status.Error(codes.Unauthenticated, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// clientIP returns the address of the client of the request, which is its peer address.
+// The api gateway appends the address it sees to x-forwarded-for, so for requests of a trusted
+// proxy its last entry is used. The metadata of any other peer could be forged and is ignored.
+func (s *serverAPI) clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	addr := addrPort.Addr().Unmap()
+	if !s.trustedProxy(addr) {
+		return addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			entries := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	return addr.String()
+}
+
+// trustedProxy reports whether the address belongs to one of the trusted proxies.
+func (s *serverAPI) trustedProxy(addr netip.Addr) bool {
+	for _, prefix := range s.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRole validates the role management request.
+func validateRole(req *generated.RoleRequest) error {
+	if req.GetUserId() == emptyValue {
+		return status.Error(codes.InvalidArgument, "user id is required")
+	}
+	if req.GetRole() == "" {
+		return status.Error(codes.InvalidArgument, "role is required")
+	}
+	return nil
+}
+
 // validateRegister validates the registration request.
 func validateRegister(req *generated.RegisterRequest) error {
 	if req.GetEmail() == "" {
@@ -0,0 +1,54 @@
+package authgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.2", "172.16.0.0/12"})
+	require.NoError(t, err)
+	s := &serverAPI{trustedProxies: proxies}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		want      string
+	}{
+		{name: "direct client", peer: "203.0.113.7:5000", want: "203.0.113.7"},
+		{name: "forged metadata", peer: "203.0.113.7:5000", forwarded: []string{"198.51.100.1"}, want: "203.0.113.7"},
+		{name: "trusted proxy", peer: "10.0.0.2:5000", forwarded: []string{"198.51.100.1"}, want: "198.51.100.1"},
+		{name: "trusted range", peer: "172.18.0.5:5000", forwarded: []string{"198.51.100.1"}, want: "198.51.100.1"},
+		{name: "last appended entry", peer: "10.0.0.2:5000", forwarded: []string{"192.0.2.1, 198.51.100.1"}, want: "198.51.100.1"},
+		{name: "proxy without metadata", peer: "10.0.0.2:5000", want: "10.0.0.2"},
+		{name: "ipv4 mapped peer", peer: "[::ffff:10.0.0.2]:5000", forwarded: []string{"198.51.100.1"}, want: "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tt.peer)
+			require.NoError(t, err)
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			if tt.forwarded != nil {
+				ctx = metadata.NewIncomingContext(ctx, metadata.MD{"x-forwarded-for": tt.forwarded})
+			}
+			assert.Equal(t, tt.want, s.clientIP(ctx))
+		})
+	}
+}
+
+func TestParseProxies(t *testing.T) {
+	_, err := ParseProxies([]string{"gateway"})
+	assert.Error(t, err)
+
+	proxies, err := ParseProxies(nil)
+	require.NoError(t, err)
+	assert.Empty(t, proxies)
+}
@@ -0,0 +1,137 @@
+// Package limiter tracks failed attempts by key and slows down repeated failures.
+//
+// After the free attempts every failure blocks the key for a delay that doubles
+// with each failure, after the lockout attempts the key is locked out for longer.
+// The failures of a key are forgotten after a quiet period or on Reset.
+// The state is kept in memory, every instance of the service limits on its own.
+package limiter
+
+import (
+	"sync"
+	"time"
+)
+
+// Config represents the limits of the failed attempts of a key.
+// Zero delays and attempts turn the backoff or the lockout off.
+type Config struct {
+	FreeAttempts    int           `yaml:"free_attempts"`    // failures not delayed
+	BaseDelay       time.Duration `yaml:"base_delay"`       // delay after the first failure past the free ones
+	MaxDelay        time.Duration `yaml:"max_delay"`        // cap of the doubled delays
+	LockoutAttempts int           `yaml:"lockout_attempts"` // failures that lock the key out
+	Lockout         time.Duration `yaml:"lockout"`          // duration of the lockout
+	ResetAfter      time.Duration `yaml:"reset_after"`      // quiet period after which the failures are forgotten
+}
+
+// entry represents the failures of a key.
+type entry struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// Limiter tracks the failed attempts of keys, it is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+// New creates a new instance of Limiter.
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:     cfg,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Allow reports whether an attempt of the key is allowed now.
+// If it isn't, it returns how long the key stays blocked.
+func (l *Limiter) Allow(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := l.entry(key)
+	if e == nil {
+		return 0, true
+	}
+	if wait := e.blockedUntil.Sub(l.now()); wait > 0 {
+		return wait, false
+	}
+	return 0, true
+}
+
+// Fail records a failed attempt of the key and blocks it if the limits are exceeded.
+func (l *Limiter) Fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	e := l.entry(key)
+	if e == nil {
+		e = &entry{}
+		l.entries[key] = e
+	}
+	e.failures++
+	e.lastFailure = now
+
+	var block time.Duration
+	if l.cfg.LockoutAttempts > 0 && e.failures >= l.cfg.LockoutAttempts {
+		block = l.cfg.Lockout
+	} else if excess := e.failures - l.cfg.FreeAttempts; excess > 0 && l.cfg.BaseDelay > 0 {
+		block = l.cfg.BaseDelay
+		for i := 1; i < excess && (l.cfg.MaxDelay <= 0 || block < l.cfg.MaxDelay); i++ {
+			block *= 2
+		}
+		if l.cfg.MaxDelay > 0 {
+			block = min(block, l.cfg.MaxDelay)
+		}
+	}
+	if until := now.Add(block); until.After(e.blockedUntil) {
+		e.blockedUntil = until
+	}
+}
+
+// Reset forgets the failures of the key.
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+}
+
+// entry returns the failures of the key, or nil if there are none to remember.
+func (l *Limiter) entry(key string) *entry {
+	e, ok := l.entries[key]
+	if !ok {
+		return nil
+	}
+	if l.expired(e, l.now()) {
+		delete(l.entries, key)
+		return nil
+	}
+	return e
+}
+
+// expired reports whether the failures of the entry are forgotten at the moment.
+func (l *Limiter) expired(e *entry, now time.Time) bool {
+	return l.cfg.ResetAfter > 0 && now.Sub(e.lastFailure) >= l.cfg.ResetAfter && !now.Before(e.blockedUntil)
+}
+
+// sweep deletes the forgotten entries at most once per quiet period, so keys tried once don't pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if l.cfg.ResetAfter <= 0 || now.Sub(l.lastSweep) < l.cfg.ResetAfter {
+		return
+	}
+	l.lastSweep = now
+	for key, e := range l.entries {
+		if l.expired(e, now) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clock is a manual clock of the tests.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newLimiter(cfg Config) (*Limiter, *clock) {
+	c := &clock{now: time.Unix(1_700_000_000, 0)}
+	l := New(cfg)
+	l.now = func() time.Time { return c.now }
+	return l, c
+}
+
+var testConfig = Config{
+	FreeAttempts:    3,
+	BaseDelay:       time.Second,
+	MaxDelay:        10 * time.Second,
+	LockoutAttempts: 10,
+	Lockout:         15 * time.Minute,
+	ResetAfter:      time.Hour,
+}
+
+func TestBackoff(t *testing.T) {
+	l, c := newLimiter(testConfig)
+
+	for i := 0; i < 3; i++ {
+		_, ok := l.Allow("user")
+		assert.True(t, ok)
+		l.Fail("user")
+	}
+
+	// The delays double after the free attempts and are capped
+	for _, expected := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		_, ok := l.Allow("user")
+		assert.True(t, ok)
+		l.Fail("user")
+
+		wait, ok := l.Allow("user")
+		assert.False(t, ok)
+		assert.Equal(t, expected*time.Second, wait)
+		c.advance(wait)
+	}
+
+	// Other keys aren't affected
+	_, ok := l.Allow("other")
+	assert.True(t, ok)
+}
+
+func TestLockout(t *testing.T) {
+	l, c := newLimiter(testConfig)
+
+	for i := 0; i < 10; i++ {
+		l.Fail("user")
+	}
+	wait, ok := l.Allow("user")
+	assert.False(t, ok)
+	assert.Equal(t, 15*time.Minute, wait)
+
+	c.advance(15 * time.Minute)
+	_, ok = l.Allow("user")
+	assert.True(t, ok)
+
+	// The failures are remembered until the quiet period passes
+	l.Fail("user")
+	_, ok = l.Allow("user")
+	assert.False(t, ok)
+}
+
+func TestReset(t *testing.T) {
+	l, c := newLimiter(testConfig)
+
+	for i := 0; i < 5; i++ {
+		l.Fail("user")
+	}
+	l.Reset("user")
+	_, ok := l.Allow("user")
+	assert.True(t, ok)
+
+	for i := 0; i < 5; i++ {
+		l.Fail("user")
+	}
+	c.advance(time.Hour)
+	_, ok = l.Allow("user")
+	assert.True(t, ok)
+	l.Fail("user")
+	_, ok = l.Allow("user")
+	assert.True(t, ok)
+
+	// Forgotten keys are swept
+	l.Fail("once")
+	c.advance(2 * time.Hour)
+	l.Fail("user")
+	assert.NotContains(t, l.entries, "once")
+}
+
+func TestZeroConfig(t *testing.T) {
+	l, _ := newLimiter(Config{})
+	for i := 0; i < 100; i++ {
+		l.Fail("user")
+	}
+	_, ok := l.Allow("user")
+	assert.True(t, ok)
+}
@@ -0,0 +1,166 @@
+// Package mail provides the senders of the emails of the auth service.
+//
+// Emails are sent through the Mailer interface. SMTPMailer delivers them to a mail server,
+// FileMailer drops them into a directory for local development and MemoryMailer keeps them
+// in memory for tests.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Senders of emails selectable in the config.
+const (
+	SenderSMTP   = "smtp"
+	SenderFile   = "file"
+	SenderMemory = "memory"
+)
+
+// Message represents a plain text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config represents the settings of the email sender.
+type Config struct {
+	Sender string     `yaml:"sender" env-default:"file"` // smtp, file or memory
+	From   string     `yaml:"from" env-default:"no-reply@olo.local"`
+	Dir    string     `yaml:"dir" env-default:"mail"` // directory of the file sender
+	SMTP   SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig represents the address and credentials of an SMTP server.
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// New creates the sender selected in the config.
+func New(cfg Config) (Mailer, error) {
+	switch cfg.Sender {
+	case SenderSMTP:
+		return NewSMTPMailer(cfg.SMTP, cfg.From), nil
+	case SenderFile:
+		return NewFileMailer(cfg.Dir, cfg.From)
+	case SenderMemory:
+		return NewMemoryMailer(), nil
+	}
+	return nil, fmt.Errorf("unknown mail sender %q", cfg.Sender)
+}
+
+// format encodes the message with its headers, the subject is encoded for non-ASCII text.
+func format(from string, msg Message, date time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return b.Bytes()
+}
+
+// SMTPMailer sends emails through an SMTP server, authenticating if a username is set.
+type SMTPMailer struct {
+	cfg  SMTPConfig
+	from string
+}
+
+// NewSMTPMailer creates a new instance of SMTPMailer.
+func NewSMTPMailer(cfg SMTPConfig, from string) *SMTPMailer {
+	return &SMTPMailer{cfg: cfg, from: from}
+}
+
+// Send sends the message, the context is only checked before sending.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := m.cfg.Host + ":" + strconv.Itoa(m.cfg.Port)
+	return smtp.SendMail(addr, auth, m.from, []string{msg.To}, format(m.from, msg, time.Now()))
+}
+
+// FileMailer writes every email into a separate .eml file of a directory.
+type FileMailer struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+// NewFileMailer creates a new instance of FileMailer, the directory is created if it doesn't exist.
+func NewFileMailer(dir, from string) (*FileMailer, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &FileMailer{dir: dir, from: from}, nil
+}
+
+// Send writes the message into a new file named by the time it was sent.
+func (m *FileMailer) Send(_ context.Context, msg Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%d.eml", now.UTC().Format("20060102T150405"), m.seq.Add(1))
+	return os.WriteFile(filepath.Join(m.dir, name), format(m.from, msg, now), 0o640)
+}
+
+// MemoryMailer keeps the sent emails in memory.
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryMailer creates a new instance of MemoryMailer.
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send saves the message.
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+// Messages returns the sent messages, oldest first.
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
+
+// Last returns the latest message sent to the address.
+func (m *MemoryMailer) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == to {
+			return m.messages[i], true
+		}
+	}
+	return Message{}, false
+}
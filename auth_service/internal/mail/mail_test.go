@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"context"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	data := format("no-reply@olo.local", Message{To: "user@gmail.com", Subject: "Сброс пароля", Body: "code"}, date)
+
+	header, body, ok := strings.Cut(string(data), "\r\n\r\n")
+	require.True(t, ok)
+	assert.Equal(t, "code", body)
+
+	lines := strings.Split(header, "\r\n")
+	assert.Equal(t, []string{"From: no-reply@olo.local", "To: user@gmail.com"}, lines[:2])
+	subject, err := new(mime.WordDecoder).DecodeHeader(strings.TrimPrefix(lines[2], "Subject: "))
+	require.NoError(t, err)
+	assert.Equal(t, "Сброс пароля", subject)
+	assert.Equal(t, "Date: Fri, 01 Mar 2024 12:00:00 +0000", lines[3])
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	mailer, err := NewFileMailer(dir, "no-reply@olo.local")
+	require.NoError(t, err)
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "a@gmail.com", Body: "first"}))
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "b@gmail.com", Body: "second"}))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	data, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "To: a@gmail.com\r\n")
+}
+
+func TestMemoryMailer(t *testing.T) {
+	mailer, err := New(Config{Sender: SenderMemory})
+	require.NoError(t, err)
+	memory := mailer.(*MemoryMailer)
+
+	_, ok := memory.Last("a@gmail.com")
+	assert.False(t, ok)
+
+	require.NoError(t, memory.Send(context.Background(), Message{To: "a@gmail.com", Body: "first"}))
+	require.NoError(t, memory.Send(context.Background(), Message{To: "b@gmail.com", Body: "other"}))
+	require.NoError(t, memory.Send(context.Background(), Message{To: "a@gmail.com", Body: "second"}))
+
+	last, ok := memory.Last("a@gmail.com")
+	assert.True(t, ok)
+	assert.Equal(t, "second", last.Body)
+	assert.Len(t, memory.Messages(), 3)
+
+	_, err = New(Config{Sender: "pigeon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+// Package olo provides the client of the olo service the auth service notifies about deleted accounts.
+package olo
+
+import (
+	polo "OLO-backend/olo_service/generated"
+	"OLO-backend/pkg/model"
+	"OLO-backend/pkg/utils/jwt"
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// serviceTokenTTL is the lifetime of the tokens the client calls the olo service with.
+const serviceTokenTTL = time.Minute
+
+// Client calls the olo service with short-lived SERVICE tokens.
+type Client struct {
+	conn   *grpc.ClientConn
+	olo    polo.OLOClient
+	issuer *jwt.Issuer
+}
+
+// NewClient creates a client of the olo service at the address, it connects on the first request.
+func NewClient(addr string, issuer *jwt.Issuer) (*Client, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:   conn,
+		olo:    polo.NewOLOClient(conn),
+		issuer: issuer,
+	}, nil
+}
+
+// AccountDeleted deletes the data of the user in the olo service.
+// The token is issued for the user, so it can't be used to purge the data of anyone else.
+func (c *Client) AccountDeleted(ctx context.Context, userID int64, email string) error {
+	token, err := c.issuer.NewToken(model.TokenUser{ID: userID, Email: email, Role: model.RoleService}, 0, serviceTokenTTL)
+	if err != nil {
+		return err
+	}
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(jwt.AuthorizationHeader, token))
+
+	_, err = c.olo.PurgeUserData(ctx, &polo.PurgeUserDataRequest{})
+	return err
+}
+
+// Close closes the connection to the olo service.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -3,86 +3,308 @@ package auth
 
 import (
 	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/auth_service/internal/limiter"
+	"OLO-backend/auth_service/internal/mail"
 	"OLO-backend/auth_service/internal/storage"
 	"OLO-backend/pkg/model"
 	"OLO-backend/pkg/utils/jwt"
 	"OLO-backend/pkg/utils/logger/sl"
+	"OLO-backend/pkg/utils/policy"
 	"context"
 	"errors"
 	"fmt"
-	jwtgo "github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"log/slog"
+	netmail "net/mail"
 	"time"
 )
 
-// ErrInvalidCredentials indicates invalid user credentials.
-var ErrInvalidCredentials = errors.New("invalid credentials")
+// defaultVerificationTTL is the lifetime of email verification tokens if it isn't configured.
+const defaultVerificationTTL = 24 * time.Hour
+
+// maxEmailLength is the longest email address that can be delivered, see RFC 5321.
+const maxEmailLength = 254
+
+// Custom errors of the authentication service.
+var (
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrInvalidRefreshToken  = errors.New("invalid refresh token")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+	ErrUnauthenticated      = errors.New("request is not authenticated")
+	ErrUnknownRole          = errors.New("unknown role")
+	ErrRoleNotAssigned      = errors.New("role is not assigned to the user")
+	ErrInvalidResetToken    = errors.New("invalid or expired password reset token")
+	ErrInvalidEmail         = errors.New("invalid email")
+	ErrEmailNotVerified     = errors.New("email is not verified")
+	ErrInvalidVerification  = errors.New("invalid or expired email verification token")
+	ErrSameEmail            = errors.New("new email is the current email")
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorDisabled    = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+	ErrInvalidChallenge     = errors.New("invalid or expired login challenge")
+	ErrTooManyAttempts      = errors.New("too many login attempts")
+)
+
+// DeletionHook removes the data another service keeps about a user when the account is deleted.
+type DeletionHook interface {
+	AccountDeleted(ctx context.Context, userID int64, email string) error
+}
 
 // Auth represents an authentication service.
 type Auth struct {
-	log         *slog.Logger
-	userStorage storage.UserStorage
-	tokenTTL    time.Duration
+	log              *slog.Logger
+	userStorage      storage.UserStorage
+	tokenStorage     storage.TokenStorage
+	resetStorage     storage.PasswordResetStorage
+	verification     storage.EmailVerificationStorage
+	twoFactorStorage storage.TwoFactorStorage
+	mailer           mail.Mailer
+	tokenTTL         time.Duration
+	refreshTTL       time.Duration
+	resetTTL         time.Duration
+
+	verificationTTL time.Duration
+	requireVerified bool
+
+	deletionHooks []DeletionHook
+
+	totpIssuer   string
+	challengeTTL time.Duration
+
+	accountLimiter *limiter.Limiter
+	ipLimiter      *limiter.Limiter
 
 	issuer    *jwt.Issuer
 	validator *jwt.Validator
+	policy    *policy.Enforcer
 }
 
 // New creates a new instance of the authentication service.
-func New(log *slog.Logger, userStorage storage.UserStorage, jwtIssuer *jwt.Issuer, jwtValidator *jwt.Validator, tokenTTL time.Duration) *Auth {
+func New(log *slog.Logger, userStorage storage.UserStorage, tokenStorage storage.TokenStorage, resetStorage storage.PasswordResetStorage, verificationStorage storage.EmailVerificationStorage, twoFactorStorage storage.TwoFactorStorage, mailer mail.Mailer, jwtIssuer *jwt.Issuer, jwtValidator *jwt.Validator, enforcer *policy.Enforcer, tokenTTL, refreshTTL, resetTTL time.Duration) *Auth {
 	return &Auth{
-		userStorage: userStorage,
-		log:         log,
-		tokenTTL:    tokenTTL,
-		issuer:      jwtIssuer,
-		validator:   jwtValidator,
+		userStorage:      userStorage,
+		tokenStorage:     tokenStorage,
+		resetStorage:     resetStorage,
+		verification:     verificationStorage,
+		twoFactorStorage: twoFactorStorage,
+		mailer:           mailer,
+		log:              log,
+		tokenTTL:         tokenTTL,
+		refreshTTL:       refreshTTL,
+		resetTTL:         resetTTL,
+		issuer:           jwtIssuer,
+		validator:        jwtValidator,
+		policy:           enforcer,
+
+		verificationTTL: defaultVerificationTTL,
+		totpIssuer:      defaultTOTPIssuer,
+		challengeTTL:    defaultChallengeTTL,
+		accountLimiter:  limiter.New(defaultAccountLimits),
+		ipLimiter:       limiter.New(defaultIPLimits),
 	}
 }
 
-// Login performs user login and returns a JWT token.
-func (a *Auth) Login(email string, password string, appID int) (string, error) {
+// SetEmailVerification sets the lifetime of email verification tokens and
+// whether Login rejects users that haven't verified their email.
+func (a *Auth) SetEmailVerification(ttl time.Duration, required bool) {
+	if ttl > 0 {
+		a.verificationTTL = ttl
+	}
+	a.requireVerified = required
+}
+
+// AddDeletionHook adds a hook called by DeleteAccount before the user is deleted.
+func (a *Auth) AddDeletionHook(hook DeletionHook) {
+	a.deletionHooks = append(a.deletionHooks, hook)
+}
+
+// Login performs user login and returns a pair of access and refresh tokens.
+// If the user has two-factor authentication on, only a challenge token is returned,
+// it is redeemed for the tokens with a code by Login2FA.
+//
+// Failed logins are counted per account and per client address, repeated failures are
+// delayed and then locked out with a TooManyAttemptsError. An unknown email takes as long
+// and is limited the same way as a wrong password, so logins don't tell which emails are registered.
+func (a *Auth) Login(email string, password string, appID int, clientIP string) (models.TokenPair, error) {
 	const op = "auth.Login"
 
 	log := a.log.With(
 		slog.String("op", op),
-		slog.String("username", email))
+		slog.String("username", email),
+		slog.String("ip", clientIP))
+
+	if err := a.allowLogin(email, clientIP); err != nil {
+		log.Warn("login blocked", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
 
 	user, err := a.userStorage.GetUserByEmail(email)
-	if err != nil {
-		if errors.Is(err, storage.ErrUserNotFound) {
-			a.log.Warn("user not found", sl.Err(err))
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		log.Error("failed to get user", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	passHash := dummyPassHash()
+	if user != nil {
+		passHash = user.PassHash
+	}
+	if err := bcrypt.CompareHashAndPassword(passHash, []byte(password)); err != nil || user == nil {
+		log.Info("invalid credentials")
+		a.failLogin(email, clientIP)
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidCredentials)
+	}
+
+	if a.requireVerified && !user.Verified {
+		log.Info("email is not verified")
+		return models.TokenPair{}, sl.Wrap(op, ErrEmailNotVerified)
+	}
 
-			return "", sl.Wrap(op, ErrInvalidCredentials)
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(user.ID)
+	if err != nil && !errors.Is(err, storage.ErrTwoFactorNotFound) {
+		log.Error("failed to get two factor", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		challenge, err := a.newLoginChallenge(user.ID, appID)
+		if err != nil {
+			log.Error("failed to save login challenge", sl.Err(err))
+			return models.TokenPair{}, sl.Wrap(op, err)
 		}
-		a.log.Error("failed to get user", sl.Err(err))
-		return "", sl.Wrap(op, err)
+		log.Info("two factor code requested")
+		return models.TokenPair{ChallengeToken: challenge}, nil
 	}
+	// The failures are forgotten only once the whole login succeeds,
+	// a login with two-factor authentication resets them in Login2FA.
+	a.accountLimiter.Reset(accountKey(email))
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		a.log.Info("invalid credentials", sl.Err(err))
-		return "", sl.Wrap(op, ErrInvalidCredentials)
+	familyID, err := newFamilyID()
+	if err != nil {
+		return models.TokenPair{}, sl.Wrap(op, err)
 	}
 
+	tokens, err := a.issueTokens(user, appID, familyID)
 	if err != nil {
-		return "", sl.Wrap(op, err)
+		log.Error("failed to issue tokens", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
 	}
 	log.Info("user logged successfully")
 
-	token, err := a.issuer.NewToken(model.TokenUser{
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new pair of tokens.
+//
+// Every refresh token can be used only once: it is rotated on every call and
+// the new token joins the family of the old one. If a token that was already
+// rotated is presented again, the whole family is revoked, since either the
+// client or an attacker holds a stolen copy.
+func (a *Auth) Refresh(refreshToken string) (models.TokenPair, error) {
+	const op = "auth.Refresh"
+
+	log := a.log.With(
+		slog.String("op", op))
+
+	stored, err := a.tokenStorage.GetRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("refresh token not found")
+			return models.TokenPair{}, sl.Wrap(op, ErrInvalidRefreshToken)
+		}
+		log.Error("failed to get refresh token", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	log = log.With(
+		slog.Int64("user_id", stored.UserID),
+		slog.String("family_id", stored.FamilyID))
+
+	if stored.Revoked {
+		log.Warn("refresh token revoked")
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidRefreshToken)
+	}
+
+	if stored.Used {
+		return models.TokenPair{}, sl.Wrap(op, a.revokeReusedFamily(log, stored.FamilyID))
+	}
+
+	if time.Now().Unix() >= stored.ExpiresAt {
+		log.Info("refresh token expired")
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidRefreshToken)
+	}
+
+	marked, err := a.tokenStorage.MarkRefreshTokenUsed(stored.ID)
+	if err != nil {
+		log.Error("failed to mark refresh token used", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	if !marked {
+		// The token was rotated concurrently by another request.
+		return models.TokenPair{}, sl.Wrap(op, a.revokeReusedFamily(log, stored.FamilyID))
+	}
+
+	user, err := a.userStorage.GetUserById(stored.UserID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidRefreshToken)
+	}
+
+	tokens, err := a.issueTokens(user, stored.AppID, stored.FamilyID)
+	if err != nil {
+		log.Error("failed to issue tokens", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	log.Info("tokens refreshed")
+
+	return tokens, nil
+}
+
+// revokeReusedFamily revokes the family of a refresh token that was presented twice.
+func (a *Auth) revokeReusedFamily(log *slog.Logger, familyID string) error {
+	log.Warn("refresh token reuse detected, revoking token family")
+
+	if err := a.tokenStorage.RevokeTokenFamily(familyID); err != nil {
+		log.Error("failed to revoke token family", sl.Err(err))
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// issueTokens issues a new access token and a new refresh token of the given family.
+func (a *Auth) issueTokens(user *models.User, appID int, familyID string) (models.TokenPair, error) {
+	accessToken, err := a.issuer.NewToken(model.TokenUser{
 		ID:    user.ID,
 		Email: user.Email,
 		Role:  user.Role,
-	}, a.tokenTTL)
+	}, appID, a.tokenTTL)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshToken, hash, err := newOpaqueToken()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
 
+	err = a.tokenStorage.SaveRefreshToken(&models.RefreshToken{
+		TokenHash: hash,
+		FamilyID:  familyID,
+		UserID:    user.ID,
+		AppID:     appID,
+		ExpiresAt: time.Now().Add(a.refreshTTL).Unix(),
+	})
 	if err != nil {
-		return "", sl.Wrap(op, err)
+		return models.TokenPair{}, err
 	}
-	return token, nil
+
+	return models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
 }
 
-// RegisterNewUser registers a new user.
-func (a *Auth) RegisterNewUser(email string, pass string) (int64, error) {
+// RegisterNewUser registers a new user and emails them a token to verify the email.
+// A failure to send the email doesn't fail the registration.
+func (a *Auth) RegisterNewUser(ctx context.Context, email string, pass string) (int64, error) {
 	const op = "auth.RegisterNewUser"
 
 	log := a.log.With(
@@ -91,6 +313,11 @@ func (a *Auth) RegisterNewUser(email string, pass string) (int64, error) {
 
 	log.Info("register new user")
 
+	if err := validateEmail(email); err != nil {
+		log.Info("invalid email", sl.Err(err))
+		return 0, sl.Wrap(op, err)
+	}
+
 	passHash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 
 	if err != nil {
@@ -110,22 +337,507 @@ func (a *Auth) RegisterNewUser(email string, pass string) (int64, error) {
 			log.Error("user already exists", sl.Err(err))
 			return 0, sl.Wrap(op, storage.ErrUserExist)
 		}
+		log.Error("failed to save user", sl.Err(err))
+		return 0, sl.Wrap(op, err)
 	}
 	log.Info("user registered")
+
+	if err := a.sendVerification(ctx, id, email); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+	}
 	return id, nil
 }
 
+// validateEmail checks that the email is a single bare address, like user@example.com.
+func validateEmail(email string) error {
+	if len(email) > maxEmailLength {
+		return fmt.Errorf("%w: longer than %d characters", ErrInvalidEmail, maxEmailLength)
+	}
+	addr, err := netmail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// sendVerification saves a new email verification token of the user and emails it to them.
+func (a *Auth) sendVerification(ctx context.Context, userID int64, email string) error {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	err = a.verification.SaveEmailVerification(&models.EmailVerification{
+		TokenHash: hash,
+		UserID:    userID,
+		ExpiresAt: now.Add(a.verificationTTL).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: "Подтверждение почты OLO",
+		Body: fmt.Sprintf("Здравствуйте!\n\n"+
+			"Спасибо за регистрацию в OLO. Чтобы подтвердить почту, введите этот код в приложении, он действует %d ч.:\n\n"+
+			"%s\n\n"+
+			"Если вы не регистрировались, просто проигнорируйте это письмо.\n", int(a.verificationTTL.Hours()), token),
+	})
+}
+
+// VerifyEmail marks the email of the user the verification token was sent to as verified.
+// The token can be used once.
+func (a *Auth) VerifyEmail(token string) error {
+	const op = "auth.VerifyEmail"
+
+	log := a.log.With(
+		slog.String("op", op))
+
+	verification, err := a.verification.GetEmailVerification(hashToken(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("email verification token not found")
+			return sl.Wrap(op, ErrInvalidVerification)
+		}
+		log.Error("failed to get email verification", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	log = log.With(
+		slog.Int64("user_id", verification.UserID))
+
+	if verification.Used || time.Now().Unix() >= verification.ExpiresAt {
+		log.Info("email verification token used or expired")
+		return sl.Wrap(op, ErrInvalidVerification)
+	}
+
+	used, err := a.verification.UseEmailVerification(verification.ID)
+	if err != nil {
+		log.Error("failed to mark email verification used", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if !used {
+		// The token was used concurrently by another request.
+		return sl.Wrap(op, ErrInvalidVerification)
+	}
+
+	if err := a.userStorage.SetUserVerified(verification.UserID); err != nil {
+		log.Error("failed to set user verified", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("email verified")
+
+	return nil
+}
+
+// Logout revokes the access token of the current session.
+// If a refresh token of the session is given, its whole family is revoked too.
+func (a *Auth) Logout(ctx context.Context, refreshToken string) error {
+	const op = "auth.Logout"
+
+	claims, err := a.getClaims(ctx)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", claims.UID))
+
+	if err := a.validator.Revoke(claims); err != nil {
+		log.Error("failed to revoke access token", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if refreshToken != "" {
+		stored, err := a.tokenStorage.GetRefreshToken(hashToken(refreshToken))
+		if err != nil && !errors.Is(err, storage.ErrTokenNotFound) {
+			log.Error("failed to get refresh token", sl.Err(err))
+			return sl.Wrap(op, err)
+		}
+		// A refresh token of another user must not let the caller log them out.
+		if stored != nil && stored.UserID == claims.UID {
+			if err := a.tokenStorage.RevokeTokenFamily(stored.FamilyID); err != nil {
+				log.Error("failed to revoke token family", sl.Err(err))
+				return sl.Wrap(op, err)
+			}
+		}
+	}
+	log.Info("user logged out")
+
+	return nil
+}
+
+// LogoutAllSessions revokes all access and refresh tokens of the current user.
+func (a *Auth) LogoutAllSessions(ctx context.Context) error {
+	const op = "auth.LogoutAllSessions"
+
+	payloadUser, err := a.getPayloadUser(ctx)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", payloadUser.ID))
+
+	if err := a.revokeSessions(payloadUser.ID); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("user logged out of all sessions")
+
+	return nil
+}
+
+// revokeSessions revokes all access and refresh tokens of the user.
+func (a *Auth) revokeSessions(userID int64) error {
+	if err := a.tokenStorage.RevokeUserTokens(userID); err != nil {
+		return err
+	}
+	return a.validator.RevokeUser(userID)
+}
+
+// RequestPasswordReset emails a single-use password reset token to the user with the email.
+// An unknown email isn't reported, so the call can't tell which emails are registered.
+// A new token replaces the unused tokens requested before.
+func (a *Auth) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "auth.RequestPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email))
+
+	user, err := a.userStorage.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("password reset requested for unknown email")
+			return nil
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.resetStorage.InvalidatePasswordResets(user.ID); err != nil {
+		log.Error("failed to invalidate password resets", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+	now := time.Now()
+	err = a.resetStorage.SavePasswordReset(&models.PasswordReset{
+		TokenHash: hash,
+		UserID:    user.ID,
+		ExpiresAt: now.Add(a.resetTTL).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		log.Error("failed to save password reset", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	err = a.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Сброс пароля OLO",
+		Body: fmt.Sprintf("Здравствуйте!\n\n"+
+			"Для вашего аккаунта OLO запрошен сброс пароля. Введите этот код в приложении, он действует %d мин.:\n\n"+
+			"%s\n\n"+
+			"Если вы не запрашивали сброс, просто проигнорируйте это письмо.\n", int(a.resetTTL.Minutes()), token),
+	})
+	if err != nil {
+		log.Error("failed to send password reset email", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("password reset requested")
+
+	return nil
+}
+
+// ConfirmPasswordReset sets a new password of the user the reset token was sent to.
+// The token can be used once, all sessions of the user are revoked after the reset.
+func (a *Auth) ConfirmPasswordReset(token string, password string) error {
+	const op = "auth.ConfirmPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op))
+
+	reset, err := a.resetStorage.GetPasswordReset(hashToken(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("password reset token not found")
+			return sl.Wrap(op, ErrInvalidResetToken)
+		}
+		log.Error("failed to get password reset", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	log = log.With(
+		slog.Int64("user_id", reset.UserID))
+
+	if reset.Used || time.Now().Unix() >= reset.ExpiresAt {
+		log.Info("password reset token used or expired")
+		return sl.Wrap(op, ErrInvalidResetToken)
+	}
+
+	used, err := a.resetStorage.UsePasswordReset(reset.ID)
+	if err != nil {
+		log.Error("failed to mark password reset used", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if !used {
+		// The token was used concurrently by another request.
+		return sl.Wrap(op, ErrInvalidResetToken)
+	}
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if err := a.userStorage.SetUserPassword(reset.UserID, passHash); err != nil {
+		log.Error("failed to set password", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.revokeSessions(reset.UserID); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("password reset")
+
+	return nil
+}
+
+// ChangePassword sets a new password of the current user, the current password is required.
+// All sessions of the user are revoked after the change.
+func (a *Auth) ChangePassword(ctx context.Context, password string, newPassword string) error {
+	const op = "auth.ChangePassword"
+
+	user, err := a.currentUser(ctx, password)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID))
+
+	passHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if err := a.userStorage.SetUserPassword(user.ID, passHash); err != nil {
+		log.Error("failed to set password", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.revokeSessions(user.ID); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("password changed")
+
+	return nil
+}
+
+// ChangeEmail sets a new email of the current user, the current password is required.
+// The new email is unverified until the token emailed to it is confirmed,
+// all sessions of the user are revoked, since their tokens carry the old email.
+func (a *Auth) ChangeEmail(ctx context.Context, password string, newEmail string) error {
+	const op = "auth.ChangeEmail"
+
+	user, err := a.currentUser(ctx, password)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID),
+		slog.String("email", newEmail))
+
+	if err := validateEmail(newEmail); err != nil {
+		log.Info("invalid email", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if newEmail == user.Email {
+		return sl.Wrap(op, ErrSameEmail)
+	}
+
+	if err := a.userStorage.SetUserEmail(user.ID, newEmail); err != nil {
+		if errors.Is(err, storage.ErrUserExist) {
+			log.Info("email is taken")
+			return sl.Wrap(op, storage.ErrUserExist)
+		}
+		log.Error("failed to set email", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	// The tokens sent to the old email must not verify the new one.
+	if err := a.verification.InvalidateEmailVerifications(user.ID); err != nil {
+		log.Error("failed to invalidate email verifications", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.revokeSessions(user.ID); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("email changed")
+
+	if err := a.sendVerification(ctx, user.ID, newEmail); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+	}
+	return nil
+}
+
+// DeleteAccount deletes the current user, the current password is required.
+// The deletion hooks remove the data of the user in other services first,
+// if one of them fails the account is kept, so the deletion can be retried.
+func (a *Auth) DeleteAccount(ctx context.Context, password string) error {
+	const op = "auth.DeleteAccount"
+
+	user, err := a.currentUser(ctx, password)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID))
+
+	// The hooks run before the sessions are revoked, the tokens they issue would be revoked too.
+	for _, hook := range a.deletionHooks {
+		if err := hook.AccountDeleted(ctx, user.ID, user.Email); err != nil {
+			log.Error("failed to delete user data", sl.Err(err))
+			return sl.Wrap(op, err)
+		}
+	}
+
+	if err := a.revokeSessions(user.ID); err != nil {
+		log.Error("failed to revoke sessions", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if err := a.userStorage.DeleteUser(user.ID); err != nil {
+		log.Error("failed to delete user", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("account deleted")
+
+	return nil
+}
+
+// currentUser returns the user of the request if the password is theirs.
+// Wrong passwords count towards the login limits of the account, so a stolen
+// access token can't be used to guess the password.
+func (a *Auth) currentUser(ctx context.Context, password string) (*models.User, error) {
+	payloadUser, err := a.getPayloadUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.userStorage.GetUserById(payloadUser.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, ErrUnauthenticated
+		}
+		a.log.Error("failed to get user", sl.Err(err))
+		return nil, err
+	}
+
+	if err := a.allowLogin(user.Email, ""); err != nil {
+		a.log.Warn("password check blocked", slog.Int64("user_id", user.ID), sl.Err(err))
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+		a.log.Info("invalid credentials", slog.Int64("user_id", user.ID))
+		a.failLogin(user.Email, "")
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// GrantRole assigns the role to the user.
+// The user's access tokens are revoked, so the next refresh issues a token with the new role.
+func (a *Auth) GrantRole(ctx context.Context, userID int64, role string) error {
+	const op = "auth.GrantRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("role", role))
+
+	if !a.policy.HasRole(role) {
+		log.Warn("unknown role")
+		return sl.Wrap(op, ErrUnknownRole)
+	}
+
+	if _, err := a.userStorage.GetUserById(userID); err != nil {
+		log.Warn("failed to get user", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.setRole(userID, role); err != nil {
+		log.Error("failed to grant role", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("role granted")
+
+	return nil
+}
+
+// RevokeRole takes the role away from the user, the user gets the default role back.
+func (a *Auth) RevokeRole(ctx context.Context, userID int64, role string) error {
+	const op = "auth.RevokeRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("role", role))
+
+	user, err := a.userStorage.GetUserById(userID)
+	if err != nil {
+		log.Warn("failed to get user", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if user.Role != role || role == model.RoleUser {
+		log.Warn("role is not assigned")
+		return sl.Wrap(op, ErrRoleNotAssigned)
+	}
+
+	if err := a.setRole(userID, model.RoleUser); err != nil {
+		log.Error("failed to revoke role", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("role revoked")
+
+	return nil
+}
+
+// setRole stores the role of the user and revokes the access tokens carrying the old one.
+func (a *Auth) setRole(userID int64, role string) error {
+	if err := a.userStorage.SetUserRole(userID, role); err != nil {
+		return err
+	}
+	return a.validator.RevokeUser(userID)
+}
+
 // GetUserInfo get information user.
 func (a *Auth) GetUserInfo(ctx context.Context) (*models.User, error) {
 	const op = "auth.GetUserInfo"
 
-	token, err := a.getToken(ctx)
+	payloadUser, err := a.getPayloadUser(ctx)
 	if err != nil {
 		return nil, sl.Wrap(op, err)
 	}
 
-	payloadUser := a.getPayloadUser(token)
-
 	log := a.log.With(
 		slog.String("op", op),
 		slog.Int64("user_id", payloadUser.ID),
@@ -146,24 +858,20 @@ func (a *Auth) GetUserInfo(ctx context.Context) (*models.User, error) {
 	return user, nil
 }
 
-// getPayloadUser extracts user information from the JWT token.
-func (h *Auth) getPayloadUser(token *jwtgo.Token) model.TokenUser {
-	id := int64(token.Claims.(jwtgo.MapClaims)["uid"].(float64))
-	email := token.Claims.(jwtgo.MapClaims)["email"].(string)
-	role := token.Claims.(jwtgo.MapClaims)["role"].(string)
-
-	return model.TokenUser{
-		ID:    id,
-		Email: email,
-		Role:  role,
+// getPayloadUser extracts user information from the JWT token in the context.
+func (a *Auth) getPayloadUser(ctx context.Context) (model.TokenUser, error) {
+	claims, err := a.getClaims(ctx)
+	if err != nil {
+		return model.TokenUser{}, err
 	}
+	return claims.TokenUser()
 }
 
-// getToken retrieves and validates the JWT token from the context.
-func (a *Auth) getToken(ctx context.Context) (*jwtgo.Token, error) {
-	token, err := a.validator.TokenFromContextMetadata(ctx, "Authorization")
-	if err != nil {
-		return nil, fmt.Errorf("can't get token")
+// getClaims returns the claims of the token the request was authenticated with.
+func (a *Auth) getClaims(ctx context.Context) (*model.Claims, error) {
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
 	}
-	return token, nil
+	return claims, nil
 }
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"OLO-backend/auth_service/internal/limiter"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Limits of the failed logins if they aren't configured.
+var (
+	defaultAccountLimits = limiter.Config{
+		FreeAttempts:    5,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutAttempts: 10,
+		Lockout:         15 * time.Minute,
+		ResetAfter:      time.Hour,
+	}
+	defaultIPLimits = limiter.Config{
+		FreeAttempts:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutAttempts: 100,
+		Lockout:         time.Hour,
+		ResetAfter:      time.Hour,
+	}
+)
+
+// TooManyAttemptsError is returned by Login while the account or the address is blocked
+// after failed logins. It matches ErrTooManyAttempts.
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return fmt.Sprintf("%s, retry in %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *TooManyAttemptsError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// dummyPassHash is compared with the password of a login of an unknown email,
+// so it takes as long as a login of a registered one.
+var dummyPassHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// SetLoginLimits sets the limits of the failed logins of an account and of a client address.
+// A zero config keeps the default limits.
+func (a *Auth) SetLoginLimits(account, ip limiter.Config) {
+	if account != (limiter.Config{}) {
+		a.accountLimiter = limiter.New(account)
+	}
+	if ip != (limiter.Config{}) {
+		a.ipLimiter = limiter.New(ip)
+	}
+}
+
+// allowLogin checks that neither the account nor the client address is blocked.
+// Unknown emails are limited the same way, so the limits don't tell which emails are registered.
+func (a *Auth) allowLogin(email, clientIP string) error {
+	wait, ok := a.accountLimiter.Allow(accountKey(email))
+	if clientIP != "" {
+		if ipWait, ipOk := a.ipLimiter.Allow(clientIP); !ipOk {
+			wait, ok = max(wait, ipWait), false
+		}
+	}
+	if !ok {
+		return &TooManyAttemptsError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// failLogin records a failed login of the account from the client address.
+func (a *Auth) failLogin(email, clientIP string) {
+	a.accountLimiter.Fail(accountKey(email))
+	if clientIP != "" {
+		a.ipLimiter.Fail(clientIP)
+	}
+}
+
+// accountKey returns the limiter key of the account with the email.
+func accountKey(email string) string {
+	return strings.ToLower(email)
+}
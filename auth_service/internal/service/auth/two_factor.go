@@ -0,0 +1,317 @@
+package auth
+
+import (
+	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/auth_service/internal/storage"
+	"OLO-backend/auth_service/internal/totp"
+	"OLO-backend/pkg/utils/logger/sl"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Defaults of the two-factor authentication if they aren't configured.
+const (
+	defaultTOTPIssuer   = "OLO"
+	defaultChallengeTTL = 5 * time.Minute
+)
+
+const (
+	recoveryCodeCount    = 10 // recovery codes issued when two-factor authentication is enabled
+	maxChallengeAttempts = 5  // wrong codes a login challenge is redeemed with before it is invalidated
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// SetTwoFactor sets the issuer shown in authenticator apps and the lifetime of login challenges.
+func (a *Auth) SetTwoFactor(issuer string, challengeTTL time.Duration) {
+	if issuer != "" {
+		a.totpIssuer = issuer
+	}
+	if challengeTTL > 0 {
+		a.challengeTTL = challengeTTL
+	}
+}
+
+// Enable2FA generates a new TOTP secret of the current user, the current password is required.
+// It returns the secret and its otpauth:// URI, the secret is enabled by Confirm2FA.
+func (a *Auth) Enable2FA(ctx context.Context, password string) (secret string, uri string, err error) {
+	const op = "auth.Enable2FA"
+
+	user, err := a.currentUser(ctx, password)
+	if err != nil {
+		return "", "", sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID))
+
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(user.ID)
+	if err != nil && !errors.Is(err, storage.ErrTwoFactorNotFound) {
+		log.Error("failed to get two factor", sl.Err(err))
+		return "", "", sl.Wrap(op, err)
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		return "", "", sl.Wrap(op, ErrTwoFactorEnabled)
+	}
+
+	secret, err = totp.NewSecret()
+	if err != nil {
+		return "", "", sl.Wrap(op, err)
+	}
+	err = a.twoFactorStorage.SaveTwoFactor(&models.TwoFactor{
+		UserID:    user.ID,
+		Secret:    secret,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		log.Error("failed to save two factor", sl.Err(err))
+		return "", "", sl.Wrap(op, err)
+	}
+	log.Info("two factor secret generated")
+
+	return secret, totp.URI(a.totpIssuer, user.Email, secret), nil
+}
+
+// Confirm2FA enables the TOTP secret of the current user with a code of it.
+// It returns the recovery codes of the user, they are shown once and stored hashed.
+func (a *Auth) Confirm2FA(ctx context.Context, code string) ([]string, error) {
+	const op = "auth.Confirm2FA"
+
+	payloadUser, err := a.getPayloadUser(ctx)
+	if err != nil {
+		return nil, sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", payloadUser.ID))
+
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(payloadUser.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTwoFactorNotFound) {
+			return nil, sl.Wrap(op, ErrTwoFactorDisabled)
+		}
+		log.Error("failed to get two factor", sl.Err(err))
+		return nil, sl.Wrap(op, err)
+	}
+	if twoFactor.Enabled {
+		return nil, sl.Wrap(op, ErrTwoFactorEnabled)
+	}
+
+	if err := a.checkCode(twoFactor, code, false); err != nil {
+		log.Info("invalid two factor code", sl.Err(err))
+		return nil, sl.Wrap(op, err)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = newRecoveryCode()
+		if err != nil {
+			return nil, sl.Wrap(op, err)
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	if err := a.twoFactorStorage.EnableTwoFactor(payloadUser.ID, hashes); err != nil {
+		log.Error("failed to enable two factor", sl.Err(err))
+		return nil, sl.Wrap(op, err)
+	}
+	log.Info("two factor enabled")
+
+	return codes, nil
+}
+
+// Disable2FA turns two-factor authentication of the current user off.
+// The current password and a TOTP or recovery code are required.
+func (a *Auth) Disable2FA(ctx context.Context, password string, code string) error {
+	const op = "auth.Disable2FA"
+
+	user, err := a.currentUser(ctx, password)
+	if err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", user.ID))
+
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(user.ID)
+	if err != nil && !errors.Is(err, storage.ErrTwoFactorNotFound) {
+		log.Error("failed to get two factor", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return sl.Wrap(op, ErrTwoFactorDisabled)
+	}
+
+	if err := a.checkCode(twoFactor, code, true); err != nil {
+		log.Info("invalid two factor code", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+
+	if err := a.twoFactorStorage.DeleteTwoFactor(user.ID); err != nil {
+		log.Error("failed to delete two factor", sl.Err(err))
+		return sl.Wrap(op, err)
+	}
+	log.Info("two factor disabled")
+
+	return nil
+}
+
+// Login2FA redeems the challenge token returned by Login with a TOTP or recovery code
+// and returns a pair of access and refresh tokens. Wrong codes count as failed logins
+// of the account and the client address, like wrong passwords do in Login.
+func (a *Auth) Login2FA(challengeToken string, code string, clientIP string) (models.TokenPair, error) {
+	const op = "auth.Login2FA"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("ip", clientIP))
+
+	challenge, err := a.twoFactorStorage.GetLoginChallenge(hashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("login challenge not found")
+			return models.TokenPair{}, sl.Wrap(op, ErrInvalidChallenge)
+		}
+		log.Error("failed to get login challenge", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	log = log.With(
+		slog.Int64("user_id", challenge.UserID))
+
+	if challenge.Used || challenge.Attempts >= maxChallengeAttempts || time.Now().Unix() >= challenge.ExpiresAt {
+		log.Info("login challenge used or expired")
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidChallenge)
+	}
+
+	twoFactor, err := a.twoFactorStorage.GetTwoFactor(challenge.UserID)
+	if err != nil && !errors.Is(err, storage.ErrTwoFactorNotFound) {
+		log.Error("failed to get two factor", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		// Two-factor authentication was turned off after the challenge was issued.
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidChallenge)
+	}
+
+	user, err := a.userStorage.GetUserById(challenge.UserID)
+	if err != nil {
+		log.Error("failed to get user", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	if err := a.allowLogin(user.Email, clientIP); err != nil {
+		log.Warn("login blocked", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	if err := a.checkCode(twoFactor, code, true); err != nil {
+		if !errors.Is(err, ErrInvalidTwoFactorCode) {
+			log.Error("failed to check two factor code", sl.Err(err))
+			return models.TokenPair{}, sl.Wrap(op, err)
+		}
+		log.Info("invalid two factor code")
+		a.failLogin(user.Email, clientIP)
+		if err := a.twoFactorStorage.FailLoginChallenge(challenge.ID); err != nil {
+			log.Error("failed to count login challenge attempt", sl.Err(err))
+		}
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+
+	used, err := a.twoFactorStorage.UseLoginChallenge(challenge.ID)
+	if err != nil {
+		log.Error("failed to mark login challenge used", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	if !used {
+		// The challenge was redeemed concurrently by another request.
+		return models.TokenPair{}, sl.Wrap(op, ErrInvalidChallenge)
+	}
+	a.accountLimiter.Reset(accountKey(user.Email))
+
+	familyID, err := newFamilyID()
+	if err != nil {
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	tokens, err := a.issueTokens(user, challenge.AppID, familyID)
+	if err != nil {
+		log.Error("failed to issue tokens", sl.Err(err))
+		return models.TokenPair{}, sl.Wrap(op, err)
+	}
+	log.Info("user logged successfully with two factor")
+
+	return tokens, nil
+}
+
+// newLoginChallenge saves a login challenge of the user and returns its token.
+func (a *Auth) newLoginChallenge(userID int64, appID int) (string, error) {
+	token, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	err = a.twoFactorStorage.SaveLoginChallenge(&models.LoginChallenge{
+		TokenHash: hash,
+		UserID:    userID,
+		AppID:     appID,
+		ExpiresAt: now.Add(a.challengeTTL).Unix(),
+		CreatedAt: now.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// checkCode checks a TOTP code of the user, a code is accepted once.
+// If recovery is allowed, the code can also be an unused recovery code, it is used up.
+func (a *Auth) checkCode(twoFactor *models.TwoFactor, code string, recovery bool) error {
+	code = strings.TrimSpace(code)
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now()); ok {
+		used, err := a.twoFactorStorage.UseTwoFactorStep(twoFactor.UserID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	if !recovery {
+		return ErrInvalidTwoFactorCode
+	}
+	used, err := a.twoFactorStorage.UseRecoveryCode(twoFactor.UserID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// newRecoveryCode generates a random recovery code like abcde-fghij.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b))
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode returns the hash of the recovery code, ignoring its case and separators.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	return hashToken(code)
+}
@@ -2,11 +2,15 @@
 package grpc
 
 import (
+	"OLO-backend/auth_service/generated"
 	"OLO-backend/auth_service/internal/grpc/authgrpc"
+	"OLO-backend/pkg/utils/jwt"
+	"OLO-backend/pkg/utils/policy"
 	"fmt"
 	"google.golang.org/grpc"
 	"log/slog"
 	"net"
+	"net/netip"
 )
 
 // Grpc represents the gRPC server.
@@ -16,10 +20,30 @@ type Grpc struct {
 	port       int
 }
 
+// publicMethods are the methods available without an access token.
+var publicMethods = []string{
+	generated.Auth_Register_FullMethodName,
+	generated.Auth_Login_FullMethodName,
+	generated.Auth_Refresh_FullMethodName,
+	generated.Auth_RequestPasswordReset_FullMethodName,
+	generated.Auth_ConfirmPasswordReset_FullMethodName,
+	generated.Auth_VerifyEmail_FullMethodName,
+	generated.Auth_Login2FA_FullMethodName,
+}
+
 // New creates a new instance of the gRPC server.
-func New(log *slog.Logger, port int, authService authgrpc.Auth) *Grpc {
-	gRPCServer := grpc.NewServer()
-	authgrpc.Register(gRPCServer, authService)
+// The x-forwarded-for metadata is only trusted in requests of the trusted proxies.
+func New(log *slog.Logger, port int, authService authgrpc.Auth, validator *jwt.Validator, enforcer *policy.Enforcer,
+	trustedProxies []netip.Prefix) *Grpc {
+	gRPCServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			validator.UnaryServerInterceptor(publicMethods...),
+			enforcer.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(
+			validator.StreamServerInterceptor(publicMethods...),
+			enforcer.StreamServerInterceptor()),
+	)
+	authgrpc.Register(gRPCServer, authService, trustedProxies)
 
 	return &Grpc{
 		log:        log,
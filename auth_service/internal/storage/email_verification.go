@@ -0,0 +1,98 @@
+// Package storage provides storage implementations for various data entities.
+package storage
+
+import (
+	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/pkg/utils/logger/sl"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// EmailVerificationStorage defines methods for interacting with email verification token data.
+type EmailVerificationStorage interface {
+	SaveEmailVerification(verification *models.EmailVerification) error
+	GetEmailVerification(tokenHash string) (*models.EmailVerification, error)
+	UseEmailVerification(id int64) (bool, error)
+	InvalidateEmailVerifications(userID int64) error
+}
+
+// initTableEmailVerifications initializes the email verification tokens table in MySQL storage.
+func (s *InMysqlStorage) initTableEmailVerifications() {
+	db := s.mysqlProvider.DB
+	// Create the email verification tokens table if it doesn't exist
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameVerification + " (" +
+		"id BIGINT NOT NULL AUTO_INCREMENT, " +
+		"token_hash CHAR(64) NOT NULL UNIQUE, " +
+		"user_id BIGINT NOT NULL, " +
+		"expires_at BIGINT NOT NULL, " +
+		"used BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"created_at BIGINT NOT NULL, " +
+		"PRIMARY KEY (id), " +
+		"INDEX (user_id), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNameVerification+" table: ", sl.Err(err))
+	}
+}
+
+// SaveEmailVerification saves an email verification token to MySQL storage.
+func (s *InMysqlStorage) SaveEmailVerification(verification *models.EmailVerification) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.NamedExec("INSERT INTO "+TableNameVerification+" (`token_hash`, `user_id`, `expires_at`, `created_at`) "+
+		"VALUES (:token_hash, :user_id, :expires_at, :created_at)", verification)
+	if err != nil {
+		return fmt.Errorf("error save email verification: %w", err)
+	}
+	return nil
+}
+
+// GetEmailVerification retrieves an email verification token by its hash from MySQL storage.
+func (s *InMysqlStorage) GetEmailVerification(tokenHash string) (*models.EmailVerification, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &models.EmailVerification{}
+	err = driver.Get(verification, "SELECT * FROM "+TableNameVerification+" WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return verification, nil
+}
+
+// UseEmailVerification marks an email verification token as used.
+// It reports false if the token has already been used.
+func (s *InMysqlStorage) UseEmailVerification(id int64) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNameVerification+" SET used = TRUE WHERE id = ? AND used = FALSE", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// InvalidateEmailVerifications marks all the unused email verification tokens of a user as used.
+func (s *InMysqlStorage) InvalidateEmailVerifications(userID int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameVerification+" SET used = TRUE WHERE user_id = ? AND used = FALSE", userID)
+	return err
+}
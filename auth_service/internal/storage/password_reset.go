@@ -0,0 +1,98 @@
+// Package storage provides storage implementations for various data entities.
+package storage
+
+import (
+	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/pkg/utils/logger/sl"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// PasswordResetStorage defines methods for interacting with password reset token data.
+type PasswordResetStorage interface {
+	SavePasswordReset(reset *models.PasswordReset) error
+	GetPasswordReset(tokenHash string) (*models.PasswordReset, error)
+	UsePasswordReset(id int64) (bool, error)
+	InvalidatePasswordResets(userID int64) error
+}
+
+// initTablePasswordResets initializes the password reset tokens table in MySQL storage.
+func (s *InMysqlStorage) initTablePasswordResets() {
+	db := s.mysqlProvider.DB
+	// Create the password reset tokens table if it doesn't exist
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + TableNamePasswordReset + " (" +
+		"id BIGINT NOT NULL AUTO_INCREMENT, " +
+		"token_hash CHAR(64) NOT NULL UNIQUE, " +
+		"user_id BIGINT NOT NULL, " +
+		"expires_at BIGINT NOT NULL, " +
+		"used BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"created_at BIGINT NOT NULL, " +
+		"PRIMARY KEY (id), " +
+		"INDEX (user_id), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNamePasswordReset+" table: ", sl.Err(err))
+	}
+}
+
+// SavePasswordReset saves a password reset token to MySQL storage.
+func (s *InMysqlStorage) SavePasswordReset(reset *models.PasswordReset) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.NamedExec("INSERT INTO "+TableNamePasswordReset+" (`token_hash`, `user_id`, `expires_at`, `created_at`) "+
+		"VALUES (:token_hash, :user_id, :expires_at, :created_at)", reset)
+	if err != nil {
+		return fmt.Errorf("error save password reset: %w", err)
+	}
+	return nil
+}
+
+// GetPasswordReset retrieves a password reset token by its hash from MySQL storage.
+func (s *InMysqlStorage) GetPasswordReset(tokenHash string) (*models.PasswordReset, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	reset := &models.PasswordReset{}
+	err = driver.Get(reset, "SELECT * FROM "+TableNamePasswordReset+" WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return reset, nil
+}
+
+// UsePasswordReset marks a password reset token as used.
+// It reports false if the token has already been used, so a token can't reset the password twice.
+func (s *InMysqlStorage) UsePasswordReset(id int64) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNamePasswordReset+" SET used = TRUE WHERE id = ? AND used = FALSE", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// InvalidatePasswordResets marks all the unused password reset tokens of a user as used.
+func (s *InMysqlStorage) InvalidatePasswordResets(userID int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNamePasswordReset+" SET used = TRUE WHERE user_id = ? AND used = FALSE", userID)
+	return err
+}
@@ -4,18 +4,27 @@ package storage
 import (
 	"OLO-backend/auth_service/internal/storage/provider"
 	"errors"
+	"github.com/jmoiron/sqlx"
 	"log/slog"
 )
 
 // Custom errors for user and app operations.
 var (
-	ErrUserExist    = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
-	ErrAppNotFound  = errors.New("app not found")
+	ErrUserExist         = errors.New("user already exists")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrAppNotFound       = errors.New("app not found")
+	ErrTokenNotFound     = errors.New("token not found")
+	ErrTwoFactorNotFound = errors.New("two-factor authentication not found")
 
 	// Table names in the database.
-	TableNameUser = "users"
-	TableNameApp  = "app_table"
+	TableNameUser           = "users"
+	TableNameApp            = "app_table"
+	TableNameRefreshToken   = "refresh_tokens"
+	TableNamePasswordReset  = "password_resets"
+	TableNameVerification   = "email_verifications"
+	TableNameTwoFactor      = "two_factor"
+	TableNameRecoveryCode   = "recovery_codes"
+	TableNameLoginChallenge = "login_challenges"
 )
 
 // InMysqlStorage represents the MySQL storage implementation.
@@ -39,10 +48,28 @@ func NewInAuthMysqlStorage(log *slog.Logger, address, username, password, databa
 	return result
 }
 
+// DB returns the underlying database connection, so it can be shared with other stores.
+func (s *InMysqlStorage) DB() *sqlx.DB {
+	return s.mysqlProvider.DB
+}
+
 // init initializes the MySQL storage.
 func (s *InMysqlStorage) init() {
 	s.initTableUser()
 
 	s.initTableApps()
 	s.initTestDataForApps()
+
+	s.initTableRefreshTokens()
+	s.initTablePasswordResets()
+	s.initTableEmailVerifications()
+	s.initTablesTwoFactor()
+}
+
+// columnExists reports whether the table of the database has the column.
+func (s *InMysqlStorage) columnExists(table, column string) (bool, error) {
+	var count int
+	err := s.mysqlProvider.DB.Get(&count, "SELECT COUNT(*) FROM information_schema.COLUMNS "+
+		"WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND COLUMN_NAME = ?", table, column)
+	return count > 0, err
 }
@@ -0,0 +1,112 @@
+// Package storage provides storage implementations for various data entities.
+package storage
+
+import (
+	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/pkg/utils/logger/sl"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TokenStorage defines methods for interacting with refresh token data.
+type TokenStorage interface {
+	SaveRefreshToken(token *models.RefreshToken) error
+	GetRefreshToken(tokenHash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(id int64) (bool, error)
+	RevokeTokenFamily(familyID string) error
+	RevokeUserTokens(userID int64) error
+}
+
+// initTableRefreshTokens initializes the refresh tokens table in MySQL storage.
+func (s *InMysqlStorage) initTableRefreshTokens() {
+	db := s.mysqlProvider.DB
+	// Create the refresh tokens table if it doesn't exist
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameRefreshToken + " (" +
+		"id BIGINT NOT NULL AUTO_INCREMENT, " +
+		"token_hash CHAR(64) NOT NULL UNIQUE, " +
+		"family_id CHAR(32) NOT NULL, " +
+		"user_id BIGINT NOT NULL, " +
+		"app_id INT NOT NULL, " +
+		"expires_at BIGINT NOT NULL, " +
+		"used BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"revoked BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"PRIMARY KEY (id), " +
+		"INDEX (family_id), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNameRefreshToken+" table: ", sl.Err(err))
+	}
+}
+
+// SaveRefreshToken saves a refresh token to MySQL storage.
+func (s *InMysqlStorage) SaveRefreshToken(token *models.RefreshToken) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.NamedExec("INSERT INTO "+TableNameRefreshToken+" (`token_hash`, `family_id`, `user_id`, `app_id`, `expires_at`) "+
+		"VALUES (:token_hash, :family_id, :user_id, :app_id, :expires_at)", token)
+	if err != nil {
+		return fmt.Errorf("error save refresh token: %w", err)
+	}
+	return nil
+}
+
+// GetRefreshToken retrieves a refresh token by its hash from MySQL storage.
+func (s *InMysqlStorage) GetRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &models.RefreshToken{}
+	err = driver.Get(token, "SELECT * FROM "+TableNameRefreshToken+" WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// MarkRefreshTokenUsed marks a refresh token as used.
+// It reports false if the token has already been used or revoked,
+// so concurrent rotations of the same token can't both succeed.
+func (s *InMysqlStorage) MarkRefreshTokenUsed(id int64) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNameRefreshToken+" SET used = TRUE WHERE id = ? AND used = FALSE AND revoked = FALSE", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// RevokeTokenFamily revokes all refresh tokens of the family.
+func (s *InMysqlStorage) RevokeTokenFamily(familyID string) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameRefreshToken+" SET revoked = TRUE WHERE family_id = ?", familyID)
+	return err
+}
+
+// RevokeUserTokens revokes all refresh tokens of the user.
+func (s *InMysqlStorage) RevokeUserTokens(userID int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameRefreshToken+" SET revoked = TRUE WHERE user_id = ?", userID)
+	return err
+}
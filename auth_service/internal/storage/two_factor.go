@@ -0,0 +1,261 @@
+// Package storage provides storage implementations for various data entities.
+package storage
+
+import (
+	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/pkg/utils/logger/sl"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TwoFactorStorage defines methods for interacting with two-factor authentication data.
+type TwoFactorStorage interface {
+	SaveTwoFactor(twoFactor *models.TwoFactor) error
+	GetTwoFactor(userID int64) (*models.TwoFactor, error)
+	EnableTwoFactor(userID int64, recoveryCodeHashes []string) error
+	DeleteTwoFactor(userID int64) error
+	UseTwoFactorStep(userID int64, step int64) (bool, error)
+	UseRecoveryCode(userID int64, codeHash string) (bool, error)
+
+	SaveLoginChallenge(challenge *models.LoginChallenge) error
+	GetLoginChallenge(tokenHash string) (*models.LoginChallenge, error)
+	FailLoginChallenge(id int64) error
+	UseLoginChallenge(id int64) (bool, error)
+}
+
+// initTablesTwoFactor initializes the two-factor authentication tables in MySQL storage.
+func (s *InMysqlStorage) initTablesTwoFactor() {
+	db := s.mysqlProvider.DB
+	// Create the TOTP secrets table if it doesn't exist
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameTwoFactor + " (" +
+		"user_id BIGINT NOT NULL, " +
+		"secret VARCHAR(64) NOT NULL, " +
+		"enabled BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"last_step BIGINT NOT NULL DEFAULT 0, " +
+		"created_at BIGINT NOT NULL, " +
+		"PRIMARY KEY (user_id), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNameTwoFactor+" table: ", sl.Err(err))
+	}
+
+	// Create the recovery codes table if it doesn't exist
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameRecoveryCode + " (" +
+		"id BIGINT NOT NULL AUTO_INCREMENT, " +
+		"user_id BIGINT NOT NULL, " +
+		"code_hash CHAR(64) NOT NULL, " +
+		"used BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"PRIMARY KEY (id), " +
+		"UNIQUE (user_id, code_hash), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNameRecoveryCode+" table: ", sl.Err(err))
+	}
+
+	// Create the login challenges table if it doesn't exist
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameLoginChallenge + " (" +
+		"id BIGINT NOT NULL AUTO_INCREMENT, " +
+		"token_hash CHAR(64) NOT NULL UNIQUE, " +
+		"user_id BIGINT NOT NULL, " +
+		"app_id INT NOT NULL, " +
+		"expires_at BIGINT NOT NULL, " +
+		"attempts INT NOT NULL DEFAULT 0, " +
+		"used BOOLEAN NOT NULL DEFAULT FALSE, " +
+		"created_at BIGINT NOT NULL, " +
+		"PRIMARY KEY (id), " +
+		"INDEX (user_id), " +
+		"FOREIGN KEY (user_id) REFERENCES " + TableNameUser + " (id) ON DELETE CASCADE" +
+		")")
+	if err != nil {
+		s.log.Error("Error creating "+TableNameLoginChallenge+" table: ", sl.Err(err))
+	}
+}
+
+// SaveTwoFactor saves a new disabled TOTP secret of a user to MySQL storage.
+// It replaces the secret the user set up before, with its recovery codes.
+func (s *InMysqlStorage) SaveTwoFactor(twoFactor *models.TwoFactor) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM "+TableNameRecoveryCode+" WHERE user_id = ?", twoFactor.UserID); err != nil {
+		return fmt.Errorf("error delete recovery codes: %w", err)
+	}
+	_, err = tx.NamedExec("REPLACE INTO "+TableNameTwoFactor+" (`user_id`, `secret`, `enabled`, `last_step`, `created_at`) "+
+		"VALUES (:user_id, :secret, FALSE, 0, :created_at)", twoFactor)
+	if err != nil {
+		return fmt.Errorf("error save two factor: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetTwoFactor retrieves the TOTP secret of a user from MySQL storage.
+func (s *InMysqlStorage) GetTwoFactor(userID int64) (*models.TwoFactor, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor := &models.TwoFactor{}
+	err = driver.Get(twoFactor, "SELECT * FROM "+TableNameTwoFactor+" WHERE user_id = ?", userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorNotFound
+		}
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+// EnableTwoFactor enables the TOTP secret of a user and saves the hashes of their recovery codes.
+func (s *InMysqlStorage) EnableTwoFactor(userID int64, recoveryCodeHashes []string) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE "+TableNameTwoFactor+" SET enabled = TRUE WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error enable two factor: %w", err)
+	}
+	for _, hash := range recoveryCodeHashes {
+		_, err := tx.Exec("INSERT INTO "+TableNameRecoveryCode+" (`user_id`, `code_hash`) VALUES (?, ?)", userID, hash)
+		if err != nil {
+			return fmt.Errorf("error save recovery code: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteTwoFactor deletes the TOTP secret and the recovery codes of a user from MySQL storage.
+func (s *InMysqlStorage) DeleteTwoFactor(userID int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM "+TableNameRecoveryCode+" WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error delete recovery codes: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM "+TableNameTwoFactor+" WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("error delete two factor: %w", err)
+	}
+	return tx.Commit()
+}
+
+// UseTwoFactorStep records the time step of an accepted code of a user.
+// It reports false if a code of the step or a later one has already been accepted.
+func (s *InMysqlStorage) UseTwoFactorStep(userID int64, step int64) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNameTwoFactor+" SET last_step = ? WHERE user_id = ? AND last_step < ?", step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// UseRecoveryCode marks a recovery code of a user as used.
+// It reports false if the user has no such unused code.
+func (s *InMysqlStorage) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNameRecoveryCode+" SET used = TRUE WHERE user_id = ? AND code_hash = ? AND used = FALSE", userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// SaveLoginChallenge saves a login challenge to MySQL storage.
+func (s *InMysqlStorage) SaveLoginChallenge(challenge *models.LoginChallenge) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.NamedExec("INSERT INTO "+TableNameLoginChallenge+" (`token_hash`, `user_id`, `app_id`, `expires_at`, `created_at`) "+
+		"VALUES (:token_hash, :user_id, :app_id, :expires_at, :created_at)", challenge)
+	if err != nil {
+		return fmt.Errorf("error save login challenge: %w", err)
+	}
+	return nil
+}
+
+// GetLoginChallenge retrieves a login challenge by its hash from MySQL storage.
+func (s *InMysqlStorage) GetLoginChallenge(tokenHash string) (*models.LoginChallenge, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := &models.LoginChallenge{}
+	err = driver.Get(challenge, "SELECT * FROM "+TableNameLoginChallenge+" WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTokenNotFound
+		}
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// FailLoginChallenge counts a wrong code entered for a login challenge.
+func (s *InMysqlStorage) FailLoginChallenge(id int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameLoginChallenge+" SET attempts = attempts + 1 WHERE id = ?", id)
+	return err
+}
+
+// UseLoginChallenge marks a login challenge as used.
+// It reports false if the challenge has already been used.
+func (s *InMysqlStorage) UseLoginChallenge(id int64) (bool, error) {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+	res, err := driver.Exec("UPDATE "+TableNameLoginChallenge+" SET used = TRUE WHERE id = ? AND used = FALSE", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
@@ -3,14 +3,27 @@ package storage
 
 import (
 	"OLO-backend/auth_service/internal/domain/models"
+	"OLO-backend/pkg/utils/logger/sl"
+	"database/sql"
+	"errors"
 	"fmt"
+
+	"github.com/go-sql-driver/mysql"
 )
 
+// mysqlDuplicateEntry is the MySQL error number of a unique key violation.
+const mysqlDuplicateEntry = 1062
+
 // UserStorage defines methods for interacting with user data.
 type UserStorage interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserById(id int64) (*models.User, error)
 	SaveUser(email string, passhash []byte) (int64, error)
+	SetUserRole(id int64, role string) error
+	SetUserPassword(id int64, passHash []byte) error
+	SetUserVerified(id int64) error
+	SetUserEmail(id int64, email string) error
+	DeleteUser(id int64) error
 }
 
 // initTableUser initializes the users table in MySQL storage.
@@ -19,14 +32,43 @@ func (s *InMysqlStorage) initTableUser() {
 	// Create the users table if it doesn't exist
 	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + TableNameUser + " (" +
 		"id BIGINT NOT NULL AUTO_INCREMENT, " +
-		"email VARCHAR(35) NOT NULL UNIQUE, " +
-		"role VARCHAR(5) NOT NULL DEFAULT \"USER\", " +
+		"email VARCHAR(254) NOT NULL UNIQUE, " +
+		"role VARCHAR(32) NOT NULL DEFAULT \"USER\", " +
 		"password_hash VARCHAR(64) NOT NULL, " +
 		"date_register TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, " +
+		"verified BOOLEAN NOT NULL DEFAULT FALSE, " +
 		"PRIMARY KEY (id)" +
 		")")
 	if err != nil {
-		s.log.Error("Error creating "+TableNameUser+" table: ", err)
+		s.log.Error("Error creating "+TableNameUser+" table: ", sl.Err(err))
+	}
+
+	// Tables created before roles were managed had a role column too short for custom roles
+	_, err = db.Exec("ALTER TABLE " + TableNameUser + " MODIFY role VARCHAR(32) NOT NULL DEFAULT \"USER\"")
+	if err != nil {
+		s.log.Error("Error altering "+TableNameUser+" table: ", sl.Err(err))
+	}
+
+	// Tables created before emails were validated had an email column too short for valid addresses
+	_, err = db.Exec("ALTER TABLE " + TableNameUser + " MODIFY email VARCHAR(254) NOT NULL")
+	if err != nil {
+		s.log.Error("Error altering "+TableNameUser+" table: ", sl.Err(err))
+	}
+
+	// Accounts created before emails were verified are treated as verified
+	exists, err := s.columnExists(TableNameUser, "verified")
+	if err != nil {
+		s.log.Error("Error checking "+TableNameUser+" table: ", sl.Err(err))
+		return
+	}
+	if !exists {
+		_, err = db.Exec("ALTER TABLE " + TableNameUser + " ADD verified BOOLEAN NOT NULL DEFAULT FALSE")
+		if err == nil {
+			_, err = db.Exec("UPDATE " + TableNameUser + " SET verified = TRUE")
+		}
+		if err != nil {
+			s.log.Error("Error altering "+TableNameUser+" table: ", sl.Err(err))
+		}
 	}
 }
 
@@ -34,28 +76,26 @@ func (s *InMysqlStorage) initTableUser() {
 func (s *InMysqlStorage) GetUserByEmail(email string) (*models.User, error) {
 	driver, err := s.mysqlProvider.Driver()
 	if err != nil {
-		s.log.Error("Error getting data from database", err)
+		s.log.Error("Error getting data from database", sl.Err(err))
 		return nil, err
 	}
 
 	sub := &models.User{}
-	rows, err := driver.NamedQuery(fmt.Sprintf("SELECT * FROM "+TableNameUser+" WHERE email = '%s'", email), sub)
+	err = driver.Get(sub, "SELECT * FROM "+TableNameUser+" WHERE email = ?", email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-	if !rows.Next() {
-		return nil, fmt.Errorf("rows not found")
-	}
-	err = rows.StructScan(&sub)
-	return sub, err
+	return sub, nil
 }
 
 // GetUserById retrieves a user by ID from MySQL storage.
 func (s *InMysqlStorage) GetUserById(id int64) (*models.User, error) {
 	driver, err := s.mysqlProvider.Driver()
 	if err != nil {
-		s.log.Error("Error getting data from database", err)
+		s.log.Error("Error getting data from database", sl.Err(err))
 		return nil, err
 	}
 
@@ -66,7 +106,7 @@ func (s *InMysqlStorage) GetUserById(id int64) (*models.User, error) {
 	}
 	defer rows.Close()
 	if !rows.Next() {
-		return nil, fmt.Errorf("rows not found")
+		return nil, ErrUserNotFound
 	}
 	err = rows.StructScan(&sub)
 	return sub, err
@@ -76,16 +116,85 @@ func (s *InMysqlStorage) GetUserById(id int64) (*models.User, error) {
 func (s *InMysqlStorage) SaveUser(email string, passhash []byte) (int64, error) {
 	driver, err := s.mysqlProvider.Driver()
 	if err != nil {
-		s.log.Error("Error inserting to database", err)
+		s.log.Error("Error inserting to database", sl.Err(err))
 	}
 	res, err := driver.NamedExec("INSERT INTO "+TableNameUser+" (`email`, `password_hash`) VALUES (:email, :password_hash)", map[string]interface{}{
 		"email":         email,
 		"password_hash": passhash,
 	})
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return 0, ErrUserExist
+	}
 	if err != nil {
-		s.log.Error("Error saving user", err)
+		s.log.Error("Error saving user", sl.Err(err))
 		return 0, err
 	}
 	id, err := res.LastInsertId()
 	return id, err
 }
+
+// SetUserRole sets the role of a user in MySQL storage.
+func (s *InMysqlStorage) SetUserRole(id int64, role string) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameUser+" SET role = ? WHERE id = ?", role, id)
+	return err
+}
+
+// SetUserVerified marks the email of a user as verified in MySQL storage.
+func (s *InMysqlStorage) SetUserVerified(id int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameUser+" SET verified = TRUE WHERE id = ?", id)
+	return err
+}
+
+// SetUserPassword sets the password hash of a user in MySQL storage.
+func (s *InMysqlStorage) SetUserPassword(id int64, passHash []byte) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameUser+" SET password_hash = ? WHERE id = ?", passHash, id)
+	return err
+}
+
+// SetUserEmail sets the email of a user in MySQL storage, the new email is not verified.
+// It returns ErrUserExist if another user has the email.
+func (s *InMysqlStorage) SetUserEmail(id int64, email string) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	_, err = driver.Exec("UPDATE "+TableNameUser+" SET email = ?, verified = FALSE WHERE id = ?", email, id)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return ErrUserExist
+	}
+	return err
+}
+
+// DeleteUser deletes a user from MySQL storage, the tokens of the user are deleted with it.
+func (s *InMysqlStorage) DeleteUser(id int64) error {
+	driver, err := s.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	res, err := driver.Exec("DELETE FROM "+TableNameUser+" WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -3,21 +3,29 @@ package tests
 import (
 	"OLO-backend/auth_service/generated"
 	"OLO-backend/auth_service/internal/config"
+	"OLO-backend/auth_service/internal/limiter"
+	"OLO-backend/auth_service/internal/mail"
 	"OLO-backend/auth_service/internal/service/auth"
 	"OLO-backend/auth_service/internal/service/grpc"
 	"OLO-backend/auth_service/internal/storage"
+	"OLO-backend/auth_service/internal/totp"
 	"OLO-backend/pkg/utils/jwt"
 	"OLO-backend/pkg/utils/logger"
+	"OLO-backend/pkg/utils/policy"
 	"context"
 	"fmt"
 	golangjwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"log/slog"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -26,17 +34,21 @@ type AuthSuite struct {
 	suite.Suite
 
 	log      *slog.Logger
-	storage  storage.UserStorage
+	storage  *storage.InMysqlStorage
+	mailer   *mail.MemoryMailer
 	services *auth.Auth
+	deleted  *deletionRecorder
 
 	srv *grpc.Grpc
 
-	accessToken string
+	accessToken  string
+	refreshToken string
 }
 
 var (
 	targetAddrAuth = "localhost:8080"
 	tokenTTL       = "1h"
+	refreshTTL     = "24h"
 	portSrv        = 8080
 )
 
@@ -69,10 +81,22 @@ func (s *AuthSuite) SetupSuite() {
 		s.T().Fatalf("jwt Validator not found key: %v", err)
 	}
 
-	duration, _ := time.ParseDuration(tokenTTL)
-	s.services = auth.New(s.log, s.storage, issuer, validator, duration)
+	enforcer, err := policy.NewEnforcer(cfg.Policy)
+	if err != nil {
+		s.T().Fatalf("bad access policy: %v", err)
+	}
 
-	s.srv = grpc.New(s.log, portSrv, s.services)
+	duration, _ := time.ParseDuration(tokenTTL)
+	refreshDuration, _ := time.ParseDuration(refreshTTL)
+	s.mailer = mail.NewMemoryMailer()
+	s.services = auth.New(s.log, s.storage, s.storage, s.storage, s.storage, s.storage, s.mailer, issuer, validator, enforcer,
+		duration, refreshDuration, time.Hour)
+	s.deleted = &deletionRecorder{}
+	s.services.AddDeletionHook(s.deleted)
+	// All the tests log in from the same address, the per-IP limits would block them.
+	s.services.SetLoginLimits(limiter.Config{}, limiter.Config{FreeAttempts: 1000, ResetAfter: time.Hour})
+
+	s.srv = grpc.New(s.log, portSrv, s.services, validator, enforcer, nil)
 	go s.srv.MustRun()
 
 	s.log.Info("SSO OLO App Integration Tests Started")
@@ -82,7 +106,7 @@ func (s *AuthSuite) SetupSuite() {
 
 func (s *AuthSuite) initData() {
 	s.register(userRegister)
-	s.accessToken = s.login(userLogin)
+	s.accessToken, s.refreshToken = s.login(userLogin)
 }
 
 func (s *AuthSuite) TestTokenVerify() {
@@ -134,6 +158,501 @@ func (s *AuthSuite) TestGetUserInfo() {
 	}
 }
 
+func (s *AuthSuite) TestRefreshTokenRotation() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, refreshToken := s.login(userLogin)
+
+	resp, err := authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: refreshToken})
+	if err != nil {
+		s.T().Fatalf("refresh failed: %v", err)
+	}
+	assert.NotEmpty(s.T(), resp.GetToken())
+	assert.NotEqual(s.T(), refreshToken, resp.GetRefreshToken())
+
+	// The rotated token can't be used again and its reuse revokes the whole family.
+	_, err = authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: refreshToken})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: resp.GetRefreshToken()})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+}
+
+func (s *AuthSuite) TestLogout() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	accessToken, refreshToken := s.login(userLogin)
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+
+	_, err = authClient.Logout(ctx, &generated.LogoutRequest{RefreshToken: refreshToken})
+	if err != nil {
+		s.T().Fatalf("logout failed: %v", err)
+	}
+
+	_, err = authClient.GetUserInfo(ctx, userInfo)
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: refreshToken})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+}
+
+func (s *AuthSuite) TestGrantRoleRequiresPermission() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": s.accessToken}))
+
+	_, err = authClient.GrantRole(ctx, &generated.RoleRequest{UserId: 1, Role: "ADMIN"})
+	assert.Equal(s.T(), codes.PermissionDenied, status.Code(err))
+}
+
+func (s *AuthSuite) TestPasswordReset() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resetUser := &generated.RegisterRequest{Email: "reset@gmail.com", Password: "password"}
+	s.register(resetUser)
+	_, refreshToken := s.login(&generated.LoginRequest{Email: resetUser.GetEmail(), Password: resetUser.GetPassword(), AppId: 1})
+
+	// An unknown email gets the same response and no email.
+	_, err = authClient.RequestPasswordReset(ctx, &generated.RequestPasswordResetRequest{Email: "nobody@gmail.com"})
+	assert.NoError(s.T(), err)
+	_, sent := s.mailer.Last("nobody@gmail.com")
+	assert.False(s.T(), sent)
+
+	_, err = authClient.RequestPasswordReset(ctx, &generated.RequestPasswordResetRequest{Email: resetUser.GetEmail()})
+	if err != nil {
+		s.T().Fatalf("request password reset failed: %v", err)
+	}
+	token := s.mailedToken(resetUser.GetEmail())
+
+	_, err = authClient.ConfirmPasswordReset(ctx, &generated.ConfirmPasswordResetRequest{Token: "wrong", NewPassword: "new_password"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+
+	_, err = authClient.ConfirmPasswordReset(ctx, &generated.ConfirmPasswordResetRequest{Token: token, NewPassword: "new_password"})
+	if err != nil {
+		s.T().Fatalf("confirm password reset failed: %v", err)
+	}
+
+	// The token is single-use and the sessions from before the reset are revoked.
+	_, err = authClient.ConfirmPasswordReset(ctx, &generated.ConfirmPasswordResetRequest{Token: token, NewPassword: "other_password"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	_, err = authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: refreshToken})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.Login(ctx, &generated.LoginRequest{Email: resetUser.GetEmail(), Password: resetUser.GetPassword(), AppId: 1})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	_, err = authClient.Login(ctx, &generated.LoginRequest{Email: resetUser.GetEmail(), Password: "new_password", AppId: 1})
+	assert.NoError(s.T(), err)
+}
+
+func (s *AuthSuite) TestEmailVerification() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = authClient.Register(ctx, &generated.RegisterRequest{Email: "not an email", Password: "password"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	_, err = authClient.Register(ctx, &generated.RegisterRequest{Email: "Name <name@gmail.com>", Password: "password"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+
+	verifyUser := &generated.RegisterRequest{Email: "verify@gmail.com", Password: "password"}
+	_, err = authClient.Register(ctx, verifyUser)
+	if err != nil {
+		s.T().Fatalf("register failed: %v", err)
+	}
+	_, err = authClient.Register(ctx, verifyUser)
+	assert.Equal(s.T(), codes.AlreadyExists, status.Code(err))
+	token := s.mailedToken(verifyUser.GetEmail())
+
+	s.services.SetEmailVerification(time.Hour, true)
+	defer s.services.SetEmailVerification(time.Hour, false)
+
+	login := &generated.LoginRequest{Email: verifyUser.GetEmail(), Password: verifyUser.GetPassword(), AppId: 1}
+	_, err = authClient.Login(ctx, login)
+	assert.Equal(s.T(), codes.FailedPrecondition, status.Code(err))
+
+	_, err = authClient.VerifyEmail(ctx, &generated.VerifyEmailRequest{Token: "wrong"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	_, err = authClient.VerifyEmail(ctx, &generated.VerifyEmailRequest{Token: token})
+	if err != nil {
+		s.T().Fatalf("verify email failed: %v", err)
+	}
+	_, err = authClient.VerifyEmail(ctx, &generated.VerifyEmailRequest{Token: token})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+
+	accessToken, _ := s.login(login)
+	ctx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+	info, err := authClient.GetUserInfo(ctx, &generated.GetUserInfoRequest{})
+	if err != nil {
+		s.T().Fatalf("get user info failed: %v", err)
+	}
+	assert.True(s.T(), info.GetVerified())
+}
+
+func (s *AuthSuite) TestAccountManagement() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	account := &generated.RegisterRequest{Email: "account@gmail.com", Password: "password"}
+	registered, err := authClient.Register(ctx, account)
+	if err != nil {
+		s.T().Fatalf("register failed: %v", err)
+	}
+	login := &generated.LoginRequest{Email: account.GetEmail(), Password: account.GetPassword(), AppId: 1}
+	accessToken, refreshToken := s.login(login)
+	userCtx := metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+
+	_, err = authClient.ChangePassword(ctx, &generated.ChangePasswordRequest{Password: "password", NewPassword: "new_password"})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	_, err = authClient.ChangePassword(userCtx, &generated.ChangePasswordRequest{Password: "wrong", NewPassword: "new_password"})
+	assert.Equal(s.T(), codes.PermissionDenied, status.Code(err))
+	_, err = authClient.ChangePassword(userCtx, &generated.ChangePasswordRequest{Password: "password", NewPassword: "new_password"})
+	if err != nil {
+		s.T().Fatalf("change password failed: %v", err)
+	}
+
+	// The sessions from before the change are revoked.
+	_, err = authClient.Refresh(ctx, &generated.RefreshRequest{RefreshToken: refreshToken})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	login.Password = "new_password"
+	accessToken, _ = s.login(login)
+	userCtx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+
+	_, err = authClient.ChangeEmail(userCtx, &generated.ChangeEmailRequest{Password: "new_password", NewEmail: userRegister.GetEmail()})
+	assert.Equal(s.T(), codes.AlreadyExists, status.Code(err))
+	_, err = authClient.ChangeEmail(userCtx, &generated.ChangeEmailRequest{Password: "new_password", NewEmail: "not an email"})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	_, err = authClient.ChangeEmail(userCtx, &generated.ChangeEmailRequest{Password: "new_password", NewEmail: "changed@gmail.com"})
+	if err != nil {
+		s.T().Fatalf("change email failed: %v", err)
+	}
+	token := s.mailedToken("changed@gmail.com")
+
+	login.Email = "changed@gmail.com"
+	accessToken, _ = s.login(login)
+	userCtx = metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+	info, err := authClient.GetUserInfo(userCtx, &generated.GetUserInfoRequest{})
+	if err != nil {
+		s.T().Fatalf("get user info failed: %v", err)
+	}
+	assert.Equal(s.T(), "changed@gmail.com", info.GetEmail())
+	assert.False(s.T(), info.GetVerified())
+	_, err = authClient.VerifyEmail(ctx, &generated.VerifyEmailRequest{Token: token})
+	assert.NoError(s.T(), err)
+
+	// A failed hook keeps the account, so the deletion can be retried.
+	s.deleted.fail(true)
+	_, err = authClient.DeleteAccount(userCtx, &generated.DeleteAccountRequest{Password: "new_password"})
+	assert.Error(s.T(), err)
+	s.deleted.fail(false)
+	_, err = authClient.DeleteAccount(userCtx, &generated.DeleteAccountRequest{Password: "wrong"})
+	assert.Equal(s.T(), codes.PermissionDenied, status.Code(err))
+	_, err = authClient.DeleteAccount(userCtx, &generated.DeleteAccountRequest{Password: "new_password"})
+	if err != nil {
+		s.T().Fatalf("delete account failed: %v", err)
+	}
+	assert.Contains(s.T(), s.deleted.users(), registered.GetUserId())
+
+	_, err = authClient.Login(ctx, login)
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	_, err = authClient.GetUserInfo(userCtx, &generated.GetUserInfoRequest{})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+}
+
+func (s *AuthSuite) TestTwoFactor() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tfaUser := &generated.RegisterRequest{Email: "tfa@gmail.com", Password: "password"}
+	s.register(tfaUser)
+	login := &generated.LoginRequest{Email: tfaUser.GetEmail(), Password: tfaUser.GetPassword(), AppId: 1}
+	accessToken, _ := s.login(login)
+	userCtx := metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+
+	_, err = authClient.Enable2FA(userCtx, &generated.Enable2FARequest{Password: "wrong"})
+	assert.Equal(s.T(), codes.PermissionDenied, status.Code(err))
+	enabled, err := authClient.Enable2FA(userCtx, &generated.Enable2FARequest{Password: tfaUser.GetPassword()})
+	if err != nil {
+		s.T().Fatalf("enable 2fa failed: %v", err)
+	}
+	assert.True(s.T(), strings.HasPrefix(enabled.GetUri(), "otpauth://totp/"))
+
+	// Codes are accepted a step around the current one and only once,
+	// so the test uses the current step and the next one.
+	step := totp.Step(time.Now())
+	code := func(step int64) string {
+		code, err := totp.Code(enabled.GetSecret(), step)
+		if err != nil {
+			s.T().Fatalf("totp code failed: %v", err)
+		}
+		return code
+	}
+
+	_, err = authClient.Confirm2FA(userCtx, &generated.Confirm2FARequest{Code: code(step + 5)})
+	assert.Equal(s.T(), codes.InvalidArgument, status.Code(err))
+	confirmed, err := authClient.Confirm2FA(userCtx, &generated.Confirm2FARequest{Code: code(step)})
+	if err != nil {
+		s.T().Fatalf("confirm 2fa failed: %v", err)
+	}
+	recoveryCodes := confirmed.GetRecoveryCodes()
+	assert.Len(s.T(), recoveryCodes, 10)
+
+	challenge, err := authClient.Login(ctx, login)
+	if err != nil {
+		s.T().Fatalf("login failed: %v", err)
+	}
+	assert.Empty(s.T(), challenge.GetToken())
+	assert.NotEmpty(s.T(), challenge.GetChallengeToken())
+
+	// The code used to confirm can't be replayed.
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: code(step)})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	tokens, err := authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: code(step + 1)})
+	if err != nil {
+		s.T().Fatalf("login 2fa failed: %v", err)
+	}
+	assert.NotEmpty(s.T(), tokens.GetToken())
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: recoveryCodes[0]})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	// A recovery code works once instead of a code.
+	challenge, err = authClient.Login(ctx, login)
+	if err != nil {
+		s.T().Fatalf("login failed: %v", err)
+	}
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: strings.ToUpper(recoveryCodes[0])})
+	assert.NoError(s.T(), err)
+	challenge, err = authClient.Login(ctx, login)
+	if err != nil {
+		s.T().Fatalf("login failed: %v", err)
+	}
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: recoveryCodes[0]})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	_, err = authClient.Disable2FA(userCtx, &generated.Disable2FARequest{Password: tfaUser.GetPassword(), Code: recoveryCodes[1]})
+	if err != nil {
+		s.T().Fatalf("disable 2fa failed: %v", err)
+	}
+	resp, err := authClient.Login(ctx, login)
+	if err != nil {
+		s.T().Fatalf("login failed: %v", err)
+	}
+	assert.NotEmpty(s.T(), resp.GetToken())
+	assert.Empty(s.T(), resp.GetChallengeToken())
+}
+
+func (s *AuthSuite) TestTwoFactorLockout() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tfaUser := &generated.RegisterRequest{Email: "tfa-lock@gmail.com", Password: "password"}
+	s.register(tfaUser)
+	login := &generated.LoginRequest{Email: tfaUser.GetEmail(), Password: tfaUser.GetPassword(), AppId: 1}
+	accessToken, _ := s.login(login)
+	userCtx := metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+
+	enabled, err := authClient.Enable2FA(userCtx, &generated.Enable2FARequest{Password: tfaUser.GetPassword()})
+	if err != nil {
+		s.T().Fatalf("enable 2fa failed: %v", err)
+	}
+	step := totp.Step(time.Now())
+	code, err := totp.Code(enabled.GetSecret(), step)
+	if err != nil {
+		s.T().Fatalf("totp code failed: %v", err)
+	}
+	if _, err := authClient.Confirm2FA(userCtx, &generated.Confirm2FARequest{Code: code}); err != nil {
+		s.T().Fatalf("confirm 2fa failed: %v", err)
+	}
+	wrongCode, _ := totp.Code(enabled.GetSecret(), step+5)
+	nextCode, _ := totp.Code(enabled.GetSecret(), step+1)
+
+	// Wrong codes count against the account across fresh challenges.
+	for i := 0; i < 6; i++ {
+		challenge, err := authClient.Login(ctx, login)
+		if err != nil {
+			s.T().Fatalf("login failed: %v", err)
+		}
+		_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: wrongCode})
+		assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	}
+	_, err = authClient.Login(ctx, login)
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(err))
+
+	time.Sleep(time.Second)
+	challenge, err := authClient.Login(ctx, login)
+	if err != nil {
+		s.T().Fatalf("login failed: %v", err)
+	}
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: wrongCode})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	_, err = authClient.Login2FA(ctx, &generated.Login2FARequest{ChallengeToken: challenge.GetChallengeToken(), Code: nextCode})
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(err))
+}
+
+func (s *AuthSuite) TestLoginLockout() {
+	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		s.Fail("Failed to create GRPC request")
+		return
+	}
+	defer conn.Close()
+
+	authClient := generated.NewAuthClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	lockUser := &generated.RegisterRequest{Email: "lock@gmail.com", Password: "password"}
+	s.register(lockUser)
+
+	// An unknown email fails like a wrong password.
+	_, err = authClient.Login(ctx, &generated.LoginRequest{Email: "unknown@gmail.com", Password: "password", AppId: 1})
+	assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+
+	wrong := &generated.LoginRequest{Email: lockUser.GetEmail(), Password: "wrong", AppId: 1}
+	for i := 0; i < 6; i++ {
+		_, err = authClient.Login(ctx, wrong)
+		assert.Equal(s.T(), codes.Unauthenticated, status.Code(err))
+	}
+
+	// The account is blocked after the free attempts, even for the right password.
+	_, err = authClient.Login(ctx, &generated.LoginRequest{Email: lockUser.GetEmail(), Password: lockUser.GetPassword(), AppId: 1})
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(err))
+
+	// Password checks of a logged in user are limited the same way.
+	guessUser := &generated.RegisterRequest{Email: "lock-guess@gmail.com", Password: "password"}
+	s.register(guessUser)
+	accessToken, _ := s.login(&generated.LoginRequest{Email: guessUser.GetEmail(), Password: guessUser.GetPassword(), AppId: 1})
+	userCtx := metadata.NewOutgoingContext(ctx, metadata.New(map[string]string{"Authorization": accessToken}))
+	for i := 0; i < 6; i++ {
+		_, err = authClient.ChangePassword(userCtx, &generated.ChangePasswordRequest{Password: "wrong", NewPassword: "new_password"})
+		assert.Equal(s.T(), codes.PermissionDenied, status.Code(err))
+	}
+	_, err = authClient.ChangePassword(userCtx, &generated.ChangePasswordRequest{Password: guessUser.GetPassword(), NewPassword: "new_password"})
+	assert.Equal(s.T(), codes.ResourceExhausted, status.Code(err))
+}
+
+// deletionRecorder is a deletion hook that records the deleted users.
+type deletionRecorder struct {
+	mu      sync.Mutex
+	deleted []int64
+	failing bool
+}
+
+func (r *deletionRecorder) AccountDeleted(_ context.Context, userID int64, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failing {
+		return fmt.Errorf("olo service is unavailable")
+	}
+	r.deleted = append(r.deleted, userID)
+	return nil
+}
+
+// fail makes the hook fail the deletions.
+func (r *deletionRecorder) fail(failing bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failing = failing
+}
+
+// users returns the ids of the deleted users.
+func (r *deletionRecorder) users() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64(nil), r.deleted...)
+}
+
+// mailedToken returns the token of the latest email sent to the address.
+func (s *AuthSuite) mailedToken(email string) string {
+	msg, ok := s.mailer.Last(email)
+	if !ok {
+		s.T().Fatalf("no email sent to %s", email)
+	}
+	// The token is the only line of the email without spaces.
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if line != "" && !strings.Contains(line, " ") {
+			return line
+		}
+	}
+	s.T().Fatalf("no token in the email")
+	return ""
+}
+
 func extractUnverifiedClaims(tokenString string) (string, error) {
 	var name string
 	token, _, err := new(golangjwt.Parser).ParseUnverified(tokenString, golangjwt.MapClaims{})
@@ -166,11 +685,11 @@ func (s *AuthSuite) register(requestBody *generated.RegisterRequest) {
 	_, err = authClient.Register(ctx, requestBody)
 }
 
-func (s *AuthSuite) login(requestBody *generated.LoginRequest) string {
+func (s *AuthSuite) login(requestBody *generated.LoginRequest) (string, string) {
 	conn, err := googlegrpc.Dial(targetAddrAuth, googlegrpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		s.Fail("Failed to create GRPC request")
-		return ""
+		return "", ""
 	}
 	defer conn.Close()
 
@@ -181,10 +700,10 @@ func (s *AuthSuite) login(requestBody *generated.LoginRequest) string {
 	req, err := authClient.Login(ctx, requestBody)
 	if err != nil {
 		s.Fail("Failed call method GRPC request")
-		return ""
+		return "", ""
 	}
 
-	return req.GetToken()
+	return req.GetToken(), req.GetRefreshToken()
 }
 
 func TestAuthSuite(t *testing.T) {
@@ -0,0 +1,59 @@
+// Package account provides the client of the user accounts kept by the auth service.
+package account
+
+import (
+	pauth "OLO-backend/auth_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/pkg/utils/jwt"
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client requests the accounts of users from the auth service on their behalf.
+type Client struct {
+	conn *grpc.ClientConn
+	auth pauth.AuthClient
+}
+
+// NewClient creates a client of the auth service at the address, it connects on the first request.
+func NewClient(addr string) (*Client, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn: conn,
+		auth: pauth.NewAuthClient(conn),
+	}, nil
+}
+
+// GetAccount returns the account of the user the incoming request was made by.
+// The access token of the request is passed on to the auth service.
+func (c *Client) GetAccount(ctx context.Context) (entity.Account, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(jwt.AuthorizationHeader)
+	if len(tokens) == 0 {
+		return entity.Account{}, errors.New("request has no access token")
+	}
+	ctx = metadata.NewOutgoingContext(ctx, metadata.Pairs(jwt.AuthorizationHeader, tokens[0]))
+
+	info, err := c.auth.GetUserInfo(ctx, &pauth.GetUserInfoRequest{})
+	if err != nil {
+		return entity.Account{}, err
+	}
+	return entity.Account{
+		UserID:       info.GetUserId(),
+		Email:        info.GetEmail(),
+		Role:         info.GetRole(),
+		DateRegister: info.GetDateRegister(),
+	}, nil
+}
+
+// Close closes the connection to the auth service.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
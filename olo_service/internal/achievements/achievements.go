@@ -0,0 +1,143 @@
+// Package achievements provides the declarative rules of achievements.
+//
+// A rule awards an achievement when a counter of the user's activity reaches its
+// threshold. Rules are loaded from YAML, the default ones are embedded from rules.yml.
+// Each counter can only change on some domain events, so an event only needs the
+// rules of its counters to be evaluated.
+package achievements
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"slices"
+)
+
+// Domain events that can change the activity counters.
+const (
+	EventWorkoutLogged  = "workout_logged"
+	EventArticleSaved   = "article_saved"
+	EventMetricRecorded = "metric_recorded"
+	EventMealLogged     = "meal_logged"
+	EventGoalEvaluated  = "goal_evaluated"
+)
+
+// Counters of the user's activity.
+const (
+	CounterWorkouts       = "workouts"        // logged workouts
+	CounterArticles       = "articles"        // articles added by the user
+	CounterMetrics        = "metrics"         // recorded metric readings
+	CounterMeals          = "meals"           // logged meals
+	CounterMealDays       = "meal_days"       // days with logged meals
+	CounterGoalStreak     = "goal_streak"     // longest streak of the daily goals
+	CounterGoalsCompleted = "goals_completed" // completed target goals
+)
+
+// counterEvents are the events that can change each counter.
+var counterEvents = map[string]string{
+	CounterWorkouts:       EventWorkoutLogged,
+	CounterArticles:       EventArticleSaved,
+	CounterMetrics:        EventMetricRecorded,
+	CounterMeals:          EventMealLogged,
+	CounterMealDays:       EventMealLogged,
+	CounterGoalStreak:     EventGoalEvaluated,
+	CounterGoalsCompleted: EventGoalEvaluated,
+}
+
+//go:embed rules.yml
+var defaultRules []byte
+
+// Rule represents an achievement with the condition it is earned on.
+type Rule struct {
+	ID          string `yaml:"id"`
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Counter     string `yaml:"counter"`
+	Threshold   int64  `yaml:"threshold"`
+}
+
+// Rules holds the achievement rules in their declared order.
+type Rules struct {
+	rules []Rule
+}
+
+// DefaultRules returns the embedded rules.
+func DefaultRules() (*Rules, error) {
+	return ParseRules(defaultRules)
+}
+
+// LoadRules reads the rules from the YAML file.
+func LoadRules(path string) (*Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(data)
+}
+
+// ParseRules parses and validates the rules from YAML.
+func ParseRules(data []byte) (*Rules, error) {
+	var file struct {
+		Achievements []Rule `yaml:"achievements"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid achievement rules: %w", err)
+	}
+
+	ids := make(map[string]bool, len(file.Achievements))
+	for i, rule := range file.Achievements {
+		switch {
+		case rule.ID == "" || rule.Title == "":
+			return nil, fmt.Errorf("achievement %d: id and title are required", i)
+		case ids[rule.ID]:
+			return nil, fmt.Errorf("achievement %s: duplicate id", rule.ID)
+		case counterEvents[rule.Counter] == "":
+			return nil, fmt.Errorf("achievement %s: unknown counter %q", rule.ID, rule.Counter)
+		case rule.Threshold <= 0:
+			return nil, fmt.Errorf("achievement %s: threshold must be positive", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+	if len(file.Achievements) == 0 {
+		return nil, errors.New("no achievement rules")
+	}
+	return &Rules{rules: file.Achievements}, nil
+}
+
+// All returns all the rules.
+func (r *Rules) All() []Rule {
+	return r.rules
+}
+
+// Counters returns the counters the rules depend on, only the ones the event can change if it is set.
+func (r *Rules) Counters(event string) []string {
+	var counters []string
+	for _, rule := range r.rules {
+		if event != "" && counterEvents[rule.Counter] != event {
+			continue
+		}
+		if !slices.Contains(counters, rule.Counter) {
+			counters = append(counters, rule.Counter)
+		}
+	}
+	return counters
+}
+
+// Reached returns the rules whose counters reached their thresholds.
+// Rules of the counters missing from the values aren't reached.
+func (r *Rules) Reached(values map[string]int64) []Rule {
+	var reached []Rule
+	for _, rule := range r.rules {
+		if value, ok := values[rule.Counter]; ok && value >= rule.Threshold {
+			reached = append(reached, rule)
+		}
+	}
+	return reached
+}
+
+// Progress returns how close the value is to the threshold of the rule, from 0 to 1.
+func (r Rule) Progress(value int64) float64 {
+	return min(float64(value)/float64(r.Threshold), 1)
+}
@@ -0,0 +1,55 @@
+package achievements
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRules(t *testing.T) {
+	rules, err := DefaultRules()
+	require.NoError(t, err)
+	require.NotEmpty(t, rules.All())
+
+	assert.Equal(t, []string{CounterMeals, CounterMealDays}, rules.Counters(EventMealLogged))
+	assert.Equal(t, []string{CounterWorkouts}, rules.Counters(EventWorkoutLogged))
+	assert.Len(t, rules.Counters(""), 7)
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"unknown counter", "achievements:\n  - {id: a, title: A, counter: pushups, threshold: 1}"},
+		{"duplicate id", "achievements:\n  - {id: a, title: A, counter: workouts, threshold: 1}\n  - {id: a, title: B, counter: meals, threshold: 1}"},
+		{"zero threshold", "achievements:\n  - {id: a, title: A, counter: workouts, threshold: 0}"},
+		{"no title", "achievements:\n  - {id: a, counter: workouts, threshold: 1}"},
+		{"empty", "achievements: []"},
+		{"invalid yaml", "achievements: {"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReached(t *testing.T) {
+	rules, err := ParseRules([]byte(`achievements:
+  - {id: first_workout, title: A, counter: workouts, threshold: 1}
+  - {id: workouts_10, title: B, counter: workouts, threshold: 10}
+  - {id: reader_10, title: C, counter: articles, threshold: 10}
+`))
+	require.NoError(t, err)
+
+	reached := rules.Reached(map[string]int64{CounterWorkouts: 3})
+	require.Len(t, reached, 1)
+	assert.Equal(t, "first_workout", reached[0].ID)
+	assert.Empty(t, rules.Reached(map[string]int64{CounterArticles: 9}))
+
+	assert.Equal(t, 0.3, rules.All()[1].Progress(3))
+	assert.Equal(t, 1.0, rules.All()[1].Progress(12))
+}
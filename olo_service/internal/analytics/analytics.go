@@ -0,0 +1,199 @@
+// Package analytics provides the training analytics of logged workouts.
+//
+// It estimates one-rep maxes, finds personal records and sums the training
+// volume of muscle groups. The functions are pure, the sets are loaded by the caller.
+package analytics
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"cmp"
+	"slices"
+)
+
+// Formula is a formula of the one-rep max estimation.
+type Formula int
+
+const (
+	Epley   Formula = iota // weight * (1 + reps / 30)
+	Brzycki                // weight * 36 / (37 - reps)
+)
+
+// Kinds of personal records in the order they are reported.
+const (
+	RecordOneRepMax = "one_rep_max"
+	RecordWeight    = "max_weight"
+	RecordReps      = "max_reps"
+	RecordVolume    = "max_volume"
+	RecordDuration  = "max_duration"
+	RecordDistance  = "max_distance"
+)
+
+var recordKinds = []string{RecordOneRepMax, RecordWeight, RecordReps, RecordVolume, RecordDuration, RecordDistance}
+
+const week = 7 * 24 * 60 * 60
+
+// OneRepMax estimates the maximum weight that can be lifted once from a set of reps with the weight.
+// Brzycki isn't defined from 37 reps, Epley is used there. Sets without weight or reps give 0.
+func OneRepMax(weightKg float64, reps int, formula Formula) float64 {
+	switch {
+	case weightKg <= 0 || reps <= 0:
+		return 0
+	case reps == 1:
+		return weightKg
+	case formula == Brzycki && reps < 37:
+		return weightKg * 36 / float64(37-reps)
+	}
+	return weightKg * (1 + float64(reps)/30)
+}
+
+// setValue returns the value of the set for the kind of record, one-rep maxes are estimated with Epley.
+func setValue(set entity.WorkoutSet, kind string) float64 {
+	switch kind {
+	case RecordOneRepMax:
+		return OneRepMax(set.WeightKg, set.Reps, Epley)
+	case RecordWeight:
+		return set.WeightKg
+	case RecordReps:
+		return float64(set.Reps)
+	case RecordVolume:
+		return set.WeightKg * float64(set.Reps)
+	case RecordDuration:
+		return float64(set.DurationSec)
+	case RecordDistance:
+		return set.DistanceM
+	}
+	return 0
+}
+
+// Records returns the personal records of each exercise of the sets, ordered by exercise and kind.
+// A record is set by the earliest set with the best value, kinds the exercise has no value of are skipped.
+func Records(sets []entity.LoggedSet) []entity.PersonalRecord {
+	type key struct {
+		exerciseId int64
+		kind       int
+	}
+	best := make(map[key]entity.PersonalRecord)
+	for _, set := range sets {
+		for i, kind := range recordKinds {
+			value := setValue(set.WorkoutSet, kind)
+			if value <= 0 {
+				continue
+			}
+			k := key{set.ExerciseID, i}
+			record, ok := best[k]
+			if ok && (value < record.Value || value == record.Value && set.StartedAt >= record.AchievedAt) {
+				continue
+			}
+			best[k] = entity.PersonalRecord{
+				ExerciseID: set.ExerciseID,
+				Kind:       kind,
+				Value:      value,
+				WorkoutID:  set.WorkoutID,
+				AchievedAt: set.StartedAt,
+			}
+		}
+	}
+
+	keys := make([]key, 0, len(best))
+	for k := range best {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		return cmp.Or(cmp.Compare(a.exerciseId, b.exerciseId), cmp.Compare(a.kind, b.kind))
+	})
+	records := make([]entity.PersonalRecord, len(keys))
+	for i, k := range keys {
+		records[i] = best[k]
+	}
+	return records
+}
+
+// NewRecords returns the records of current that beat the records of previous.
+// The first result in an exercise sets no records, there is nothing to beat yet.
+func NewRecords(previous, current []entity.PersonalRecord) []entity.PersonalRecord {
+	type key struct {
+		exerciseId int64
+		kind       string
+	}
+	best := make(map[key]float64, len(previous))
+	exercises := make(map[int64]bool)
+	for _, record := range previous {
+		best[key{record.ExerciseID, record.Kind}] = record.Value
+		exercises[record.ExerciseID] = true
+	}
+
+	var records []entity.PersonalRecord
+	for _, record := range current {
+		if exercises[record.ExerciseID] && record.Value > best[key{record.ExerciseID, record.Kind}] {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// Progress returns the result of each workout of the sets, oldest first.
+func Progress(sets []entity.LoggedSet, formula Formula) []entity.ExerciseProgress {
+	index := make(map[int64]int)
+	var progress []entity.ExerciseProgress
+	for _, set := range sets {
+		i, ok := index[set.WorkoutID]
+		if !ok {
+			i = len(progress)
+			index[set.WorkoutID] = i
+			progress = append(progress, entity.ExerciseProgress{
+				WorkoutID: set.WorkoutID,
+				StartedAt: set.StartedAt,
+			})
+		}
+		p := &progress[i]
+		p.Sets++
+		p.OneRepMax = max(p.OneRepMax, OneRepMax(set.WeightKg, set.Reps, formula))
+		p.VolumeKg += set.WeightKg * float64(set.Reps)
+	}
+
+	slices.SortFunc(progress, func(a, b entity.ExerciseProgress) int {
+		return cmp.Or(cmp.Compare(a.StartedAt, b.StartedAt), cmp.Compare(a.WorkoutID, b.WorkoutID))
+	})
+	return progress
+}
+
+// WeeklyVolume returns the training volume of each muscle group of the sets per week,
+// ordered by week and muscle group. Weeks start at the anchor, a unix time, every seven days.
+func WeeklyVolume(sets []entity.LoggedSet, anchor int64) []entity.MuscleGroupVolume {
+	type key struct {
+		weekStart   int64
+		muscleGroup string
+	}
+	index := make(map[key]int)
+	var volume []entity.MuscleGroupVolume
+	for _, set := range sets {
+		k := key{weekStart(set.StartedAt, anchor), set.MuscleGroup}
+		i, ok := index[k]
+		if !ok {
+			i = len(volume)
+			index[k] = i
+			volume = append(volume, entity.MuscleGroupVolume{
+				WeekStart:   k.weekStart,
+				MuscleGroup: k.muscleGroup,
+			})
+		}
+		v := &volume[i]
+		v.Sets++
+		v.Reps += set.Reps
+		v.VolumeKg += set.WeightKg * float64(set.Reps)
+	}
+
+	slices.SortFunc(volume, func(a, b entity.MuscleGroupVolume) int {
+		return cmp.Or(cmp.Compare(a.WeekStart, b.WeekStart), cmp.Compare(a.MuscleGroup, b.MuscleGroup))
+	})
+	return volume
+}
+
+// weekStart returns the start of the week of the time.
+func weekStart(t, anchor int64) int64 {
+	n := (t - anchor) / week
+	if (t-anchor)%week < 0 {
+		n--
+	}
+	return anchor + n*week
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loggedSet(workoutId, startedAt, exerciseId int64, muscleGroup string, reps int, weightKg float64) entity.LoggedSet {
+	return entity.LoggedSet{
+		WorkoutSet: entity.WorkoutSet{
+			WorkoutID:  workoutId,
+			ExerciseID: exerciseId,
+			Reps:       reps,
+			WeightKg:   weightKg,
+		},
+		StartedAt:   startedAt,
+		MuscleGroup: muscleGroup,
+	}
+}
+
+func TestOneRepMax(t *testing.T) {
+	assert.InDelta(t, 133.33, OneRepMax(100, 10, Epley), 0.01)
+	assert.InDelta(t, 133.33, OneRepMax(100, 10, Brzycki), 0.01)
+	assert.InDelta(t, 116.67, OneRepMax(100, 5, Epley), 0.01)
+	assert.InDelta(t, 112.5, OneRepMax(100, 5, Brzycki), 0.01)
+	assert.Equal(t, 100.0, OneRepMax(100, 1, Brzycki))
+	assert.Equal(t, 0.0, OneRepMax(0, 10, Epley))
+	assert.Equal(t, 0.0, OneRepMax(100, 0, Epley))
+	// Brzycki is not defined from 37 reps
+	assert.Equal(t, OneRepMax(20, 40, Epley), OneRepMax(20, 40, Brzycki))
+}
+
+func TestRecords(t *testing.T) {
+	history := []entity.LoggedSet{
+		loggedSet(1, 100, 1, "chest", 5, 100),
+		loggedSet(1, 100, 1, "chest", 12, 60),
+		loggedSet(2, 200, 1, "chest", 5, 100),
+		loggedSet(2, 200, 2, "back", 10, 0),
+	}
+	records := Records(history)
+	require.Len(t, records, 5)
+
+	assert.Equal(t, entity.PersonalRecord{ExerciseID: 1, Kind: RecordOneRepMax, Value: 116.66666666666667, WorkoutID: 1, AchievedAt: 100}, records[0])
+	assert.Equal(t, entity.PersonalRecord{ExerciseID: 1, Kind: RecordWeight, Value: 100, WorkoutID: 1, AchievedAt: 100}, records[1])
+	assert.Equal(t, RecordReps, records[2].Kind)
+	assert.Equal(t, 12.0, records[2].Value)
+	assert.Equal(t, entity.PersonalRecord{ExerciseID: 1, Kind: RecordVolume, Value: 720, WorkoutID: 1, AchievedAt: 100}, records[3])
+	assert.Equal(t, entity.PersonalRecord{ExerciseID: 2, Kind: RecordReps, Value: 10, WorkoutID: 2, AchievedAt: 200}, records[4])
+
+	current := Records([]entity.LoggedSet{
+		loggedSet(3, 300, 1, "chest", 6, 102.5),
+		loggedSet(3, 300, 2, "back", 8, 0),
+		loggedSet(3, 300, 3, "legs", 5, 120),
+	})
+	newRecords := NewRecords(records, current)
+	require.Len(t, newRecords, 2)
+	assert.Equal(t, RecordOneRepMax, newRecords[0].Kind)
+	assert.Equal(t, RecordWeight, newRecords[1].Kind)
+	assert.Empty(t, NewRecords(nil, current))
+}
+
+func TestWeeklyVolume(t *testing.T) {
+	const monday = 4 * 24 * 60 * 60
+	const day = 24 * 60 * 60
+	sets := []entity.LoggedSet{
+		loggedSet(1, monday+2*day, 1, "chest", 10, 50),
+		loggedSet(1, monday+2*day, 2, "back", 8, 40),
+		loggedSet(2, monday+6*day, 1, "chest", 5, 60),
+		loggedSet(3, monday+7*day, 1, "chest", 10, 50),
+	}
+	volume := WeeklyVolume(sets, monday)
+	assert.Equal(t, []entity.MuscleGroupVolume{
+		{WeekStart: monday, MuscleGroup: "back", Sets: 1, Reps: 8, VolumeKg: 320},
+		{WeekStart: monday, MuscleGroup: "chest", Sets: 2, Reps: 15, VolumeKg: 800},
+		{WeekStart: monday + 7*day, MuscleGroup: "chest", Sets: 1, Reps: 10, VolumeKg: 500},
+	}, volume)
+
+	// A workout before the anchor belongs to the previous week
+	volume = WeeklyVolume([]entity.LoggedSet{loggedSet(1, monday-day, 1, "chest", 1, 1)}, monday)
+	assert.Equal(t, int64(monday-7*day), volume[0].WeekStart)
+}
+
+func TestProgress(t *testing.T) {
+	progress := Progress([]entity.LoggedSet{
+		loggedSet(2, 200, 1, "chest", 5, 100),
+		loggedSet(1, 100, 1, "chest", 10, 60),
+		loggedSet(1, 100, 1, "chest", 8, 70),
+	}, Epley)
+	require.Len(t, progress, 2)
+	assert.Equal(t, int64(1), progress[0].WorkoutID)
+	assert.Equal(t, 2, progress[0].Sets)
+	assert.InDelta(t, 88.67, progress[0].OneRepMax, 0.01)
+	assert.Equal(t, 1160.0, progress[0].VolumeKg)
+	assert.Equal(t, int64(2), progress[1].WorkoutID)
+}
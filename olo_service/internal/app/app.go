@@ -6,23 +6,40 @@ package app
 
 import (
 	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/account"
+	"OLO-backend/olo_service/internal/achievements"
 	"OLO-backend/olo_service/internal/config"
+	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/handler"
 	"OLO-backend/olo_service/internal/repository"
 	"OLO-backend/olo_service/internal/repository/provider"
+	"OLO-backend/olo_service/internal/search"
 	"OLO-backend/olo_service/internal/service"
+	"OLO-backend/olo_service/internal/widgettype"
 	"OLO-backend/pkg/utils/jwt"
+	"OLO-backend/pkg/utils/logger/sl"
+	"OLO-backend/pkg/utils/policy"
+	"context"
 	"fmt"
 	"google.golang.org/grpc"
 	"log/slog"
 	"net"
+	"time"
 )
 
 type App struct {
 	log        *slog.Logger
 	handler    *handler.OloHandler
+	validator  *jwt.Validator
+	enforcer   *policy.Enforcer
 	gRPCServer *grpc.Server
 	port       int
+	accounts   *account.Client
+
+	goals            *service.GoalService
+	evaluateInterval time.Duration
+	stopEvaluator    context.CancelFunc
+	evaluatorDone    chan struct{}
 }
 
 // New is the entry point for the API Gateway application.
@@ -43,20 +60,117 @@ func New(log *slog.Logger, cfg *config.Config) (app *App) {
 	if err != nil {
 		panic(err)
 	}
+	// Tokens revoked by the auth service are only seen through the shared store,
+	// the memory store is for running the service alone.
+	switch cfg.RevocationStore {
+	case "mysql":
+		revocations, err := jwt.NewMySQLRevocationStore(dbProvider.DB)
+		if err != nil {
+			panic(fmt.Errorf("error init revocation store: %v", err))
+		}
+		validator.SetRevocationStore(revocations)
+	case "memory":
+	default:
+		panic(fmt.Errorf("unknown revocation store %q", cfg.RevocationStore))
+	}
+
+	enforcer, err := policy.NewEnforcer(cfg.Policy)
+	if err != nil {
+		panic(fmt.Errorf("error init access policy: %v", err))
+	}
+
+	searchBackend, err := newSearchBackend(cfg.Search, dbProvider, repos)
+	if err != nil {
+		panic(fmt.Errorf("error init search: %v", err))
+	}
+
+	widgetTypes, err := widgettype.NewRegistry()
+	if err != nil {
+		panic(fmt.Errorf("error init widget types: %v", err))
+	}
+
+	rules, err := newAchievementRules(cfg.Achievements)
+	if err != nil {
+		panic(fmt.Errorf("error load achievement rules: %v", err))
+	}
+
+	accounts, err := account.NewClient(fmt.Sprintf("%s:%d", cfg.AuthService.Host, cfg.AuthService.Port))
+	if err != nil {
+		panic(fmt.Errorf("error init auth service client: %v", err))
+	}
 
-	oloService := service.NewOloService(log, repos)
-	oloHandler := handler.NewOloHandler(oloService, validator)
+	achievementService := service.NewAchievementService(log, repos, rules)
+	oloService := service.NewOloService(log, repos, searchBackend, widgetTypes, achievementService)
+	oloService.SetWidgetHistoryRetention(cfg.WidgetHistory.MaxVersions, cfg.WidgetHistory.MaxAge)
+	metricService := service.NewMetricService(log, repos, achievementService)
+	analyticsService := service.NewAnalyticsService(log, repos)
+	workoutService := service.NewWorkoutService(log, repos, analyticsService, achievementService)
+	nutritionService := service.NewNutritionService(log, repos, achievementService)
+	profileService := service.NewProfileService(log, repos)
+	goalService := service.NewGoalService(log, repos, achievementService)
+	exportService := service.NewExportService(log, repos, accounts)
+	accountService := service.NewAccountService(log, repos)
+	oloHandler := handler.NewOloHandler(oloService, metricService, workoutService, analyticsService, nutritionService,
+		profileService, goalService, achievementService, exportService, accountService)
 	app = &App{
-		log:     log,
-		handler: oloHandler,
-		port:    cfg.GRPC.Port,
+		log:       log,
+		handler:   oloHandler,
+		validator: validator,
+		enforcer:  enforcer,
+		port:      cfg.GRPC.Port,
+		accounts:  accounts,
+
+		goals:            goalService,
+		evaluateInterval: cfg.Goals.EvaluateInterval,
 	}
 	return
 }
 
+// newSearchBackend creates the article search backend selected in the config.
+// The in-memory index is filled with all the articles from the database.
+func newSearchBackend(cfg config.SearchConfig, dbProvider *provider.MySQLProvider, repos *repository.Repository) (search.Backend, error) {
+	switch cfg.Backend {
+	case search.BackendMySQL:
+		return search.NewMySQLBackend(dbProvider), nil
+	case search.BackendMemory:
+		articles, err := repos.GetAllArticles(entity.ArticleQuery{}, nil)
+		if err != nil {
+			return nil, err
+		}
+		backend := search.NewMemoryBackend()
+		return backend, backend.IndexAll(articles)
+	}
+	return nil, fmt.Errorf("unknown search backend %q", cfg.Backend)
+}
+
+// newAchievementRules loads the achievement rules from the file in the config or the embedded ones.
+func newAchievementRules(cfg config.AchievementsConfig) (*achievements.Rules, error) {
+	if cfg.RulesPath == "" {
+		return achievements.DefaultRules()
+	}
+	return achievements.LoadRules(cfg.RulesPath)
+}
+
 func (a *App) Start() {
-	a.gRPCServer = grpc.NewServer()
+	a.gRPCServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			a.validator.UnaryServerInterceptor(),
+			a.enforcer.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(
+			a.validator.StreamServerInterceptor(),
+			a.enforcer.StreamServerInterceptor()),
+	)
 	generated.RegisterOLOServer(a.gRPCServer, a.handler)
+
+	// Фоновая оценка целей пользователей, по умолчанию раз в сутки
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopEvaluator = cancel
+	a.evaluatorDone = make(chan struct{})
+	go func() {
+		defer close(a.evaluatorDone)
+		a.goals.RunEvaluator(ctx, a.evaluateInterval)
+	}()
+
 	if err := a.run(); err != nil {
 		panic(err)
 	}
@@ -88,4 +202,12 @@ func (a *App) Stop() {
 
 	a.log.With(slog.String("op", op)).Info("stopping gRPC server", slog.Int("port", a.port))
 	a.gRPCServer.GracefulStop()
+
+	if a.stopEvaluator != nil {
+		a.stopEvaluator()
+		<-a.evaluatorDone
+	}
+	if err := a.accounts.Close(); err != nil {
+		a.log.Error("failed to close auth service client", sl.Err(err))
+	}
 }
@@ -0,0 +1,109 @@
+// Package calculator provides the body and energy calculations of a fitness profile.
+//
+// BMR is calculated with the Mifflin-St Jeor equation, TDEE multiplies it by the
+// factor of the activity level. Macro targets are derived from TDEE adjusted to the goal weight.
+package calculator
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"time"
+)
+
+// Sexes of a profile.
+const (
+	SexMale   = "male"
+	SexFemale = "female"
+)
+
+// ActivityFactors are the TDEE multipliers of the activity levels.
+var ActivityFactors = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// Adjustments of the calorie target to the goal weight.
+const (
+	weightLossDeficit = 500  // kcal
+	weightGainSurplus = 300  // kcal
+	goalTolerance     = 1.0  // kg, closer goals mean keeping the weight
+	fatShare          = 0.25 // share of calories from fat
+)
+
+// Minimum calorie targets, lower intake isn't safe without supervision.
+var minCalories = map[string]float64{
+	SexMale:   1500,
+	SexFemale: 1200,
+}
+
+// BMI returns the body mass index.
+func BMI(weightKg, heightCm float64) float64 {
+	heightM := heightCm / 100
+	return weightKg / (heightM * heightM)
+}
+
+// BMICategory returns the WHO category of the body mass index.
+func BMICategory(bmi float64) string {
+	switch {
+	case bmi < 18.5:
+		return "underweight"
+	case bmi < 25:
+		return "normal"
+	case bmi < 30:
+		return "overweight"
+	}
+	return "obese"
+}
+
+// BMR returns the basal metabolic rate in kcal per day by the Mifflin-St Jeor equation.
+func BMR(weightKg, heightCm float64, age int, sex string) float64 {
+	bmr := 10*weightKg + 6.25*heightCm - 5*float64(age)
+	if sex == SexMale {
+		return bmr + 5
+	}
+	return bmr - 161
+}
+
+// TDEE returns the total daily energy expenditure in kcal, unknown activity levels count as sedentary.
+func TDEE(bmr float64, activityLevel string) float64 {
+	factor, ok := ActivityFactors[activityLevel]
+	if !ok {
+		factor = ActivityFactors["sedentary"]
+	}
+	return bmr * factor
+}
+
+// Targets returns the recommended daily calories and macros. Calories are cut to lose weight
+// and raised to gain it if the goal weight is set, protein is higher while losing weight
+// to keep the muscles, a quarter of calories comes from fat and the rest from carbs.
+func Targets(tdee, weightKg, goalWeightKg float64, sex string) entity.Nutrients {
+	calories, proteinPerKg := tdee, 1.6
+	switch {
+	case goalWeightKg == 0:
+	case goalWeightKg < weightKg-goalTolerance:
+		calories, proteinPerKg = max(tdee-weightLossDeficit, minCalories[sex]), 2.0
+	case goalWeightKg > weightKg+goalTolerance:
+		calories = tdee + weightGainSurplus
+	}
+
+	protein := proteinPerKg * weightKg
+	fat := calories * fatShare / 9
+	carbs := max(calories-protein*4-fat*9, 0) / 4
+	return entity.Nutrients{
+		Calories: calories,
+		Protein:  protein,
+		Fat:      fat,
+		Carbs:    carbs,
+	}
+}
+
+// Age returns the age in full years on the date.
+func Age(birthDate, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || now.Month() == birthDate.Month() && now.Day() < birthDate.Day() {
+		age--
+	}
+	return age
+}
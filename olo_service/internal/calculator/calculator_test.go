@@ -0,0 +1,50 @@
+package calculator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBMI(t *testing.T) {
+	bmi := BMI(70, 175)
+	assert.InDelta(t, 22.86, bmi, 0.01)
+	assert.Equal(t, "normal", BMICategory(bmi))
+	assert.Equal(t, "underweight", BMICategory(18.4))
+	assert.Equal(t, "overweight", BMICategory(25))
+	assert.Equal(t, "obese", BMICategory(30))
+}
+
+func TestBMRAndTDEE(t *testing.T) {
+	assert.InDelta(t, 1780, BMR(80, 180, 30, SexMale), 0.001)
+	assert.InDelta(t, 1395.25, BMR(65, 165, 25, SexFemale), 0.001)
+	assert.InDelta(t, 2759, TDEE(1780, "moderate"), 0.001)
+	assert.InDelta(t, 1674.3, TDEE(1395.25, "unknown"), 0.001)
+}
+
+func TestTargets(t *testing.T) {
+	keep := Targets(2500, 80, 0, SexMale)
+	assert.Equal(t, 2500.0, keep.Calories)
+	assert.InDelta(t, 128, keep.Protein, 0.001)
+	assert.InDelta(t, 69.44, keep.Fat, 0.01)
+	assert.InDelta(t, 340.75, keep.Carbs, 0.01)
+	// the macros add up to the calories
+	assert.InDelta(t, keep.Calories, keep.Protein*4+keep.Fat*9+keep.Carbs*4, 0.001)
+
+	assert.Equal(t, 2500.0, Targets(2500, 80, 80.5, SexMale).Calories)
+
+	lose := Targets(2500, 80, 70, SexMale)
+	assert.Equal(t, 2000.0, lose.Calories)
+	assert.InDelta(t, 160, lose.Protein, 0.001)
+
+	assert.Equal(t, 1200.0, Targets(1500, 60, 50, SexFemale).Calories)
+	assert.Equal(t, 2800.0, Targets(2500, 60, 70, SexFemale).Calories)
+}
+
+func TestAge(t *testing.T) {
+	birthDate := time.Date(1990, time.June, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 33, Age(birthDate, time.Date(2024, time.June, 14, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 34, Age(birthDate, time.Date(2024, time.June, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 34, Age(birthDate, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)))
+}
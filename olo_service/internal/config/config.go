@@ -1,21 +1,58 @@
 package config
 
 import (
+	"OLO-backend/pkg/utils/policy"
 	"flag"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
 type Config struct {
-	Env           string      `yaml:"env" env-default:"local"`
-	GRPC          GRPCConfig  `yaml:"grpc"`
-	MySQLSettings MySQLConfig `yaml:"mysql_settings"`
+	Env             string             `yaml:"env" env-default:"local"`
+	GRPC            GRPCConfig         `yaml:"grpc"`
+	MySQLSettings   MySQLConfig        `yaml:"mysql_settings"`
+	RevocationStore string             `yaml:"revocation_store" env-default:"memory"` // mysql or memory
+	Policy          policy.Config      `yaml:"policy"`
+	Search          SearchConfig       `yaml:"search"`
+	WidgetHistory   HistoryConfig      `yaml:"widget_history"`
+	Goals           GoalsConfig        `yaml:"goals"`
+	Achievements    AchievementsConfig `yaml:"achievements"`
+	AuthService     AuthServiceConfig  `yaml:"auth_service"`
+}
+
+// SearchConfig represents the settings of the article search.
+type SearchConfig struct {
+	Backend string `yaml:"backend" env-default:"mysql"` // mysql or memory
 }
 
 type GRPCConfig struct {
 	Port int `yaml:"port"`
 }
 
+// HistoryConfig represents the retention of widget revisions.
+// The current version of a widget is kept regardless of the limits.
+type HistoryConfig struct {
+	MaxVersions int           `yaml:"max_versions" env-default:"50"`
+	MaxAge      time.Duration `yaml:"max_age" env-default:"2160h"`
+}
+
+// GoalsConfig represents the settings of the background evaluation of goals.
+type GoalsConfig struct {
+	EvaluateInterval time.Duration `yaml:"evaluate_interval" env-default:"24h"`
+}
+
+// AchievementsConfig represents the settings of the achievements.
+type AchievementsConfig struct {
+	RulesPath string `yaml:"rules_path"` // YAML file with the rules, the embedded ones are used if empty
+}
+
+// AuthServiceConfig represents the address of the auth service the accounts of users are requested from.
+type AuthServiceConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
 // region databases providers
 
 type MySQLConfig struct {
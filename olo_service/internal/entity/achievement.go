@@ -0,0 +1,19 @@
+package entity
+
+// Achievement represents an achievement with the progress of a user towards it.
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	Counter     string  // activity counter the achievement is earned by
+	Threshold   int64   // value of the counter the achievement is earned at
+	Value       int64   // current value of the counter
+	Progress    float64 // from 0 to 1
+	EarnedAt    int64   // unix time in seconds, 0 if the achievement is locked
+}
+
+// EarnedAchievement represents an achievement earned by a user.
+type EarnedAchievement struct {
+	ID       string `db:"achievement_id"`
+	EarnedAt int64  `db:"earned_at"`
+}
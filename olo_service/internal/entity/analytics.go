@@ -0,0 +1,44 @@
+package entity
+
+// LoggedSet represents a set of a workout of a user with the details of its workout and exercise.
+type LoggedSet struct {
+	WorkoutSet
+	StartedAt   int64  `db:"started_at"` // start of the workout, unix time in seconds
+	MuscleGroup string `db:"muscle_group"`
+}
+
+// PersonalRecord represents the best result of a user in an exercise.
+type PersonalRecord struct {
+	ExerciseID int64
+	Kind       string  // one_rep_max, max_weight, max_reps, max_volume, max_duration or max_distance
+	Value      float64 // kg, reps, kg, seconds or meters depending on the kind
+	WorkoutID  int64   // workout where the record was set
+	AchievedAt int64   // start of the workout, unix time in seconds
+}
+
+// ExerciseProgress represents the result of a user in an exercise during a workout.
+type ExerciseProgress struct {
+	WorkoutID int64
+	StartedAt int64
+	Sets      int
+	OneRepMax float64 // best estimated one-rep max of the sets, kg
+	VolumeKg  float64 // sum of weight times reps of the sets
+}
+
+// MuscleGroupVolume represents the training volume of a muscle group during a week.
+type MuscleGroupVolume struct {
+	WeekStart   int64 // unix time in seconds
+	MuscleGroup string
+	Sets        int
+	Reps        int
+	VolumeKg    float64 // sum of weight times reps of the sets
+}
+
+// ExerciseStats represents the analytics of a user in an exercise.
+type ExerciseStats struct {
+	Exercise     Exercise
+	OneRepMax    float64             // best estimated one-rep max of all time, kg
+	Records      []PersonalRecord    // all-time bests
+	Progress     []ExerciseProgress  // workouts in the time range, oldest first
+	WeeklyVolume []MuscleGroupVolume // volume of all muscle groups in the time range
+}
@@ -1,7 +1,51 @@
 package entity
 
 type Article struct {
-	ID     int64  `db:"id"`
-	Header string `db:"header"`
-	Body   string `db:"body"`
+	ID         int64    `db:"id"`
+	Header     string   `db:"header"`
+	Body       string   `db:"body"`
+	CategoryID int64    `db:"category_id"` // 0 if the article has no category
+	Tags       []string `db:"-"`
+}
+
+// Category represents a topic articles are grouped by.
+type Category struct {
+	ID   int64  `db:"id"`
+	Slug string `db:"slug"`
+	Name string `db:"name"`
+}
+
+// ArticleSort represents the order of articles in a listing.
+type ArticleSort int
+
+const (
+	ArticleSortIDAsc ArticleSort = iota
+	ArticleSortIDDesc
+	ArticleSortHeaderAsc
+	ArticleSortHeaderDesc
+)
+
+// ArticleQuery represents the parameters of an article listing.
+type ArticleQuery struct {
+	PageSize     int
+	PageToken    string
+	Sort         ArticleSort
+	HeaderFilter string
+	Tag          string // only articles with the tag, if set
+	CategoryID   int64  // only articles of the category, if set
+}
+
+// ArticleCursor represents the position in an article listing after which the next page starts.
+type ArticleCursor struct {
+	ID     int64  `json:"id"`
+	Header string `json:"header,omitempty"`
+}
+
+// ArticleSearchHit represents an article found by a search query.
+// The snippets are HTML-escaped, the matched words are wrapped in <b> tags.
+type ArticleSearchHit struct {
+	Article       Article
+	Score         float64
+	HeaderSnippet string
+	BodySnippet   string
 }
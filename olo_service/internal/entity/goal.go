@@ -0,0 +1,31 @@
+package entity
+
+// Goal represents a goal of a user with the result of its latest evaluation.
+type Goal struct {
+	ID         int64   `db:"id"`
+	UserID     int64   `db:"id_user"`
+	Title      string  `db:"title"`
+	Kind       string  `db:"kind"`        // daily or target
+	MetricType string  `db:"metric_type"` // type of the metric the goal is evaluated from
+	Comparison string  `db:"comparison"`  // at_least or at_most
+	Target     float64 `db:"target"`
+	StartValue float64 `db:"start_value"` // value of the metric when a target goal was created
+	Deadline   string  `db:"deadline"`    // YYYY-MM-DD, empty if a target goal has no deadline
+	UTCOffset  int     `db:"utc_offset"`  // time zone of the user in seconds, days start at its midnight
+	CreatedAt  int64   `db:"created_at"`
+
+	Status        string  `db:"status"`        // active, completed or failed
+	CurrentValue  float64 `db:"current_value"` // value of today for daily goals, latest value for target goals
+	Progress      float64 `db:"progress"`      // from 0 to 1
+	CurrentStreak int     `db:"current_streak"`
+	LongestStreak int     `db:"longest_streak"`
+	CompletedAt   int64   `db:"completed_at"`
+	EvaluatedAt   int64   `db:"evaluated_at"`
+}
+
+// GoalDay represents the value of the metric of a goal during a day.
+type GoalDay struct {
+	Date  string // local date of the user, YYYY-MM-DD
+	Value float64
+	Met   bool
+}
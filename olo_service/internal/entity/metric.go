@@ -0,0 +1,38 @@
+package entity
+
+// Metric represents a health reading of a user, e.g. a step count or weight.
+type Metric struct {
+	ID         int64   `db:"id"`
+	Type       string  `db:"type"`
+	RecordedAt int64   `db:"recorded_at"` // unix time in seconds
+	Value      float64 `db:"value"`
+	Unit       string  `db:"unit"`
+	Source     string  `db:"source"` // device or app the reading came from
+}
+
+// MetricBucketSize represents the interval metrics are downsampled to.
+type MetricBucketSize int
+
+const (
+	MetricBucketDay MetricBucketSize = iota
+	MetricBucketHour
+	MetricBucketWeek
+)
+
+// MetricQuery represents the parameters of a downsampled metric query.
+type MetricQuery struct {
+	Type      string
+	From, To  int64 // unix time in seconds, To is exclusive
+	Bucket    MetricBucketSize
+	UTCOffset int // offset of the user's time zone in seconds, buckets start at the local midnight
+}
+
+// MetricBucket represents the aggregated readings of a metric in a time interval.
+type MetricBucket struct {
+	Start int64   `db:"bucket_start"` // unix time in seconds
+	Count int64   `db:"count"`
+	Avg   float64 `db:"avg"`
+	Min   float64 `db:"min"`
+	Max   float64 `db:"max"`
+	Sum   float64 `db:"sum"`
+}
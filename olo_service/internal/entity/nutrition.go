@@ -0,0 +1,59 @@
+package entity
+
+// Nutrients represents the energy and macronutrients of food.
+type Nutrients struct {
+	Calories float64 `db:"calories"` // kcal
+	Protein  float64 `db:"protein"`  // g
+	Fat      float64 `db:"fat"`      // g
+	Carbs    float64 `db:"carbs"`    // g
+}
+
+// Add returns the sum of the nutrients.
+func (n Nutrients) Add(other Nutrients) Nutrients {
+	return Nutrients{
+		Calories: n.Calories + other.Calories,
+		Protein:  n.Protein + other.Protein,
+		Fat:      n.Fat + other.Fat,
+		Carbs:    n.Carbs + other.Carbs,
+	}
+}
+
+// Food represents a food of the catalog with its nutrients per 100 g.
+type Food struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+	Nutrients
+}
+
+// Meal represents a portion of food a user ate.
+type Meal struct {
+	ID        int64   `db:"id"`
+	Date      string  `db:"date"`      // local date of the user, YYYY-MM-DD
+	MealType  string  `db:"meal_type"` // breakfast, lunch, dinner or snack
+	FoodID    int64   `db:"id_food"`
+	FoodName  string  `db:"food_name"`
+	Grams     float64 `db:"grams"`
+	CreatedAt int64   `db:"created_at"` // unix time in seconds
+	Nutrients         // nutrients of the portion
+}
+
+// DayNutrients represents the nutrients a user ate during a day.
+type DayNutrients struct {
+	Date string `db:"date"`
+	Nutrients
+}
+
+// DailyNutrition represents the nutrition diary of a user for a day.
+type DailyNutrition struct {
+	Date   string
+	Totals Nutrients
+	Meals  []Meal
+}
+
+// WeeklyNutrition represents the nutrition of a user during a week starting on Monday.
+type WeeklyNutrition struct {
+	WeekStart string
+	Days      []DayNutrients // all seven days of the week
+	Totals    Nutrients
+	Average   Nutrients // average of the days with meals
+}
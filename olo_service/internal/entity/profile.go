@@ -0,0 +1,29 @@
+package entity
+
+// Profile represents the fitness profile of a user.
+type Profile struct {
+	HeightCm      float64 `db:"height_cm"`
+	BirthDate     string  `db:"birth_date"`     // YYYY-MM-DD
+	Sex           string  `db:"sex"`            // male or female
+	ActivityLevel string  `db:"activity_level"` // sedentary, light, moderate, active or very_active
+	Units         string  `db:"units"`          // metric or imperial, values are always stored in metric units
+	GoalWeightKg  float64 `db:"goal_weight_kg"` // 0 if not set
+	UpdatedAt     int64   `db:"updated_at"`     // unix time in seconds
+}
+
+// ProfileCalculations represents the values derived from the fitness profile and the latest weight of a user.
+type ProfileCalculations struct {
+	WeightKg    float64 // latest weight metric
+	BMI         float64
+	BMICategory string  // underweight, normal, overweight or obese
+	BMR         float64 // kcal per day
+	TDEE        float64 // kcal per day
+	Targets     Nutrients
+}
+
+// WidgetDefaults represents the values a widget uses when its data doesn't set them.
+type WidgetDefaults struct {
+	GoalKcal float64    // calorie counters
+	Macros   *Nutrients // calorie counters
+	TargetKg float64    // weight trackers
+}
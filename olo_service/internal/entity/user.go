@@ -5,3 +5,11 @@ type User struct {
 	Email string
 	Role  string
 }
+
+// Account represents the account of a user in the auth service.
+type Account struct {
+	UserID       int64
+	Email        string
+	Role         string
+	DateRegister string
+}
@@ -1,6 +1,30 @@
 package entity
 
 type Widget struct {
-	ID   int64  `db:"id"`
-	Data string `db:"data"`
+	ID      int64  `db:"id"`
+	Type    string `db:"type"` // empty for widgets created before the widget types were introduced
+	Data    string `db:"data"`
+	Version int    `db:"version"` // version of the data, incremented on every update
+	WidgetLayout
+	Nutrition *Nutrients      `db:"-"` // totals of the nutrition diary, only for calorie counters
+	Defaults  *WidgetDefaults `db:"-"` // defaults derived from the fitness profile
+}
+
+// WidgetRevision represents a saved version of the data of a widget.
+type WidgetRevision struct {
+	WidgetID  int64  `db:"id_widget"`
+	Version   int    `db:"version"`
+	Type      string `db:"type"`
+	Data      string `db:"data"`
+	CreatedAt int64  `db:"created_at"` // unix time in seconds
+}
+
+// WidgetLayout represents where a widget sits on the dashboard grid.
+type WidgetLayout struct {
+	Position int  `db:"position"` // order of the widget on the dashboard
+	X        int  `db:"grid_x"`
+	Y        int  `db:"grid_y"`
+	Width    int  `db:"width"`
+	Height   int  `db:"height"`
+	Visible  bool `db:"visible"`
 }
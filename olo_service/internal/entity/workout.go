@@ -0,0 +1,36 @@
+package entity
+
+// Exercise represents an exercise of the catalog.
+type Exercise struct {
+	ID          int64  `db:"id"`
+	Name        string `db:"name"`
+	MuscleGroup string `db:"muscle_group"` // chest, back, legs, shoulders, arms, core or cardio
+	Kind        string `db:"kind"`         // strength or cardio
+}
+
+// Workout represents a workout session of a user.
+type Workout struct {
+	ID         int64        `db:"id"`
+	Name       string       `db:"name"`
+	StartedAt  int64        `db:"started_at"`  // unix time in seconds
+	FinishedAt int64        `db:"finished_at"` // unix time in seconds, 0 if the workout isn't finished
+	Notes      string       `db:"notes"`
+	Sets       []WorkoutSet `db:"-"`
+}
+
+// WorkoutSet represents a set of an exercise done during a workout.
+type WorkoutSet struct {
+	ID          int64   `db:"id"`
+	WorkoutID   int64   `db:"id_workout"`
+	ExerciseID  int64   `db:"id_exercise"`
+	Reps        int     `db:"reps"`
+	WeightKg    float64 `db:"weight_kg"`
+	DurationSec int     `db:"duration_sec"`
+	DistanceM   float64 `db:"distance_m"`
+}
+
+// WorkoutCursor represents the position in the workout history after which the next page starts.
+type WorkoutCursor struct {
+	StartedAt int64 `json:"s"`
+	ID        int64 `json:"i"`
+}
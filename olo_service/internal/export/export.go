@@ -0,0 +1,171 @@
+// Package export provides the writers of the data export of users.
+//
+// An export is a ZIP archive of JSON and CSV files written in one pass, so it
+// can be sent to the user while it is being built instead of kept in memory.
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Archive writes the files of an export into a ZIP archive.
+// Only the latest file is open, adding a file finishes the previous one.
+type Archive struct {
+	zip *zip.Writer
+	csv *CSVFile
+}
+
+// NewArchive creates an archive writing to w.
+func NewArchive(w io.Writer) *Archive {
+	return &Archive{zip: zip.NewWriter(w)}
+}
+
+// create finishes the open file and starts the next one.
+func (a *Archive) create(name string) (io.Writer, error) {
+	if err := a.finish(); err != nil {
+		return nil, err
+	}
+	return a.zip.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Now(),
+	})
+}
+
+// finish flushes the open CSV file.
+func (a *Archive) finish() error {
+	if a.csv == nil {
+		return nil
+	}
+	a.csv.w.Flush()
+	err := a.csv.w.Error()
+	a.csv = nil
+	return err
+}
+
+// JSON adds a file with the value encoded as indented JSON.
+func (a *Archive) JSON(name string, v any) error {
+	w, err := a.create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// CSV adds a CSV file with the header, its rows are written to the returned file
+// until the next file is added.
+func (a *Archive) CSV(name string, header ...string) (*CSVFile, error) {
+	w, err := a.create(name)
+	if err != nil {
+		return nil, err
+	}
+	a.csv = &CSVFile{w: csv.NewWriter(w)}
+	return a.csv, a.csv.w.Write(header)
+}
+
+// Close finishes the open file and writes the end of the archive, it doesn't close the underlying writer.
+func (a *Archive) Close() error {
+	if err := a.finish(); err != nil {
+		return err
+	}
+	return a.zip.Close()
+}
+
+// CSVFile writes the rows of a CSV file of an archive.
+type CSVFile struct {
+	w *csv.Writer
+}
+
+// Write writes a row of the values. Strings are written as is, numbers in the shortest form
+// and Time values in RFC 3339, a zero Time is written as an empty field.
+func (f *CSVFile) Write(values ...any) error {
+	row := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			row[i] = v
+		case int:
+			row[i] = strconv.Itoa(v)
+		case int64:
+			row[i] = strconv.FormatInt(v, 10)
+		case float64:
+			row[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			row[i] = strconv.FormatBool(v)
+		case Time:
+			row[i] = v.String()
+		default:
+			return fmt.Errorf("unsupported csv value %T", value)
+		}
+	}
+	return f.w.Write(row)
+}
+
+// Time is a unix time in seconds written in RFC 3339 in UTC.
+type Time int64
+
+// String returns the time in RFC 3339, or an empty string if it is zero.
+func (t Time) String() string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
+
+// MarshalText encodes the time in RFC 3339 for JSON files.
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// ChunkWriter splits the written data into chunks of a fixed size and passes them to send.
+// The last chunk can be shorter, it is sent on Flush.
+type ChunkWriter struct {
+	buf  []byte
+	size int
+	send func([]byte) error
+}
+
+// NewChunkWriter creates a ChunkWriter sending chunks of the size.
+// Every chunk has its own buffer, so send can keep it after it returns.
+func NewChunkWriter(size int, send func(chunk []byte) error) *ChunkWriter {
+	return &ChunkWriter{
+		buf:  make([]byte, 0, size),
+		size: size,
+		send: send,
+	}
+}
+
+// Write buffers p and sends every filled chunk.
+func (w *ChunkWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), w.size-len(w.buf))
+		w.buf = append(w.buf, p[:n]...)
+		p = p[n:]
+		written += n
+		if len(w.buf) == w.size {
+			if err := w.Flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Flush sends the buffered data if there is any.
+func (w *ChunkWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	chunk := w.buf
+	w.buf = make([]byte, 0, w.size)
+	return w.send(chunk)
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	var buf bytes.Buffer
+	archive := NewArchive(&buf)
+
+	require.NoError(t, archive.JSON("account.json", map[string]any{"email": "user@mail.ru", "created": Time(86400)}))
+	metrics, err := archive.CSV("metrics.csv", "type", "value", "recorded_at")
+	require.NoError(t, err)
+	require.NoError(t, metrics.Write("steps", 10000.0, Time(0)))
+	require.NoError(t, metrics.Write("weight, morning", 72.5, Time(60)))
+	assert.Error(t, metrics.Write(struct{}{}))
+	_, err = archive.CSV("meals.csv", "id")
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, reader.File, 3)
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		r, err := file.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(r)
+		require.NoError(t, err)
+		files[file.Name] = string(data)
+	}
+	assert.Equal(t, "{\n  \"created\": \"1970-01-02T00:00:00Z\",\n  \"email\": \"user@mail.ru\"\n}\n", files["account.json"])
+	assert.Equal(t, "type,value,recorded_at\nsteps,10000,\n\"weight, morning\",72.5,1970-01-01T00:01:00Z\n", files["metrics.csv"])
+	assert.Equal(t, "id\n", files["meals.csv"])
+}
+
+func TestChunkWriter(t *testing.T) {
+	var chunks []string
+	w := NewChunkWriter(4, func(chunk []byte) error {
+		chunks = append(chunks, string(chunk))
+		return nil
+	})
+
+	n, err := w.Write([]byte("abcdefghij"))
+	require.NoError(t, err)
+	assert.Equal(t, 10, n)
+	_, err = w.Write([]byte("kl"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "efgh", "ijkl"}, chunks)
+
+	_, err = w.Write([]byte("m"))
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Flush())
+	assert.Equal(t, []string{"abcd", "efgh", "ijkl", "m"}, chunks)
+}
+
+func TestChunkWriterArchive(t *testing.T) {
+	// The chunks are kept without copying, as a gRPC stream may hold the sent messages.
+	var chunks [][]byte
+	w := NewChunkWriter(16, func(chunk []byte) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+
+	var whole bytes.Buffer
+	archive := NewArchive(io.MultiWriter(&whole, w))
+	require.NoError(t, archive.JSON("account.json", map[string]any{"email": "user@mail.ru", "created": Time(86400)}))
+	metrics, err := archive.CSV("metrics.csv", "type", "value", "recorded_at")
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, metrics.Write("steps", float64(i*100), Time(i*60)))
+	}
+	require.NoError(t, archive.Close())
+	require.NoError(t, w.Flush())
+
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks[:len(chunks)-1] {
+		assert.Len(t, chunk, 16)
+	}
+	assert.Equal(t, whole.Bytes(), bytes.Join(chunks, nil))
+}
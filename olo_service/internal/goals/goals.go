@@ -0,0 +1,51 @@
+// Package goals provides the evaluation of goals against the recorded metrics.
+//
+// A daily goal is met on a day when the metric of the day satisfies the comparison with
+// the target, consecutive met days make a streak. A target goal is completed when the
+// latest value of the metric reaches the target.
+package goals
+
+// Comparisons of a metric with the target of a goal.
+const (
+	AtLeast = "at_least"
+	AtMost  = "at_most"
+)
+
+// Met reports whether the value satisfies the comparison with the target.
+func Met(value, target float64, comparison string) bool {
+	if comparison == AtMost {
+		return value <= target
+	}
+	return value >= target
+}
+
+// Streaks returns the current and the longest streaks of met days, the days are ordered
+// oldest first and the last one is today. Today doesn't break the current streak
+// until it is met, the day isn't over yet.
+func Streaks(met []bool) (current, longest int) {
+	run := 0
+	for _, ok := range met {
+		if ok {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+
+	current = run
+	if n := len(met); n > 0 && !met[n-1] {
+		for i := n - 2; i >= 0 && met[i]; i-- {
+			current++
+		}
+	}
+	return current, longest
+}
+
+// Progress returns how far the value moved from the start to the target, from 0 to 1.
+func Progress(start, value, target float64) float64 {
+	if start == target {
+		return 1
+	}
+	return min(max((value-start)/(target-start), 0), 1)
+}
@@ -0,0 +1,47 @@
+package goals
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMet(t *testing.T) {
+	assert.True(t, Met(10000, 10000, AtLeast))
+	assert.False(t, Met(9999, 10000, AtLeast))
+	assert.True(t, Met(1800, 2000, AtMost))
+	assert.False(t, Met(2100, 2000, AtMost))
+}
+
+func TestStreaks(t *testing.T) {
+	tests := []struct {
+		name    string
+		met     []bool
+		current int
+		longest int
+	}{
+		{"no days", nil, 0, 0},
+		{"today met", []bool{true, false, true, true}, 2, 2},
+		{"today not met yet", []bool{true, true, true, false}, 3, 3},
+		{"yesterday missed", []bool{true, true, true, false, false}, 0, 3},
+		{"longest in the past", []bool{true, true, true, false, true}, 1, 3},
+		{"only today", []bool{false}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := Streaks(tt.met)
+			assert.Equal(t, tt.current, current)
+			assert.Equal(t, tt.longest, longest)
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	// lose 5 kg from 80
+	assert.InDelta(t, 0.4, Progress(80, 78, 75), 1e-9)
+	assert.Equal(t, 0.0, Progress(80, 81, 75))
+	assert.Equal(t, 1.0, Progress(80, 74, 75))
+	// gain from 60 to 65
+	assert.InDelta(t, 0.5, Progress(60, 62.5, 65), 1e-9)
+	assert.Equal(t, 1.0, Progress(70, 70, 70))
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"context"
+)
+
+func (h *OloHandler) PurgeUserData(ctx context.Context, _ *generated.PurgeUserDataRequest) (*generated.PurgeUserDataResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.accounts.PurgeUserData(user.ID); err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.PurgeUserDataResponse{}, nil
+}
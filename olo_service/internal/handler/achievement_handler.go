@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+)
+
+// AchievementToResponse converts an Achievement entity to a generated.Achievement.
+func AchievementToResponse(achievement entity.Achievement) *generated.Achievement {
+	return &generated.Achievement{
+		Id:          achievement.ID,
+		Title:       achievement.Title,
+		Description: achievement.Description,
+		Counter:     achievement.Counter,
+		Threshold:   achievement.Threshold,
+		Value:       achievement.Value,
+		Progress:    achievement.Progress,
+		Earned:      achievement.EarnedAt != 0,
+		EarnedAt:    achievement.EarnedAt,
+	}
+}
+
+func (h *OloHandler) ListAchievements(ctx context.Context, _ *generated.ListAchievementsRequest) (*generated.ListAchievementsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	achievements, err := h.badges.ListAchievements(user.ID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ListAchievementsResponse{
+		Achievements: h.mapperBadge.MapEach(achievements),
+	}, nil
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/analytics"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PersonalRecordToResponse converts a PersonalRecord entity to a generated.PersonalRecord.
+func PersonalRecordToResponse(record entity.PersonalRecord) *generated.PersonalRecord {
+	return &generated.PersonalRecord{
+		ExerciseId: record.ExerciseID,
+		Kind:       record.Kind,
+		Value:      record.Value,
+		WorkoutId:  record.WorkoutID,
+		AchievedAt: record.AchievedAt,
+	}
+}
+
+// ExerciseProgressToResponse converts an ExerciseProgress entity to a generated.ExerciseProgress.
+func ExerciseProgressToResponse(progress entity.ExerciseProgress) *generated.ExerciseProgress {
+	return &generated.ExerciseProgress{
+		WorkoutId: progress.WorkoutID,
+		StartedAt: progress.StartedAt,
+		Sets:      int32(progress.Sets),
+		OneRepMax: progress.OneRepMax,
+		VolumeKg:  progress.VolumeKg,
+	}
+}
+
+// MuscleGroupVolumeToResponse converts a MuscleGroupVolume entity to a generated.MuscleGroupVolume.
+func MuscleGroupVolumeToResponse(volume entity.MuscleGroupVolume) *generated.MuscleGroupVolume {
+	return &generated.MuscleGroupVolume{
+		WeekStart:   volume.WeekStart,
+		MuscleGroup: volume.MuscleGroup,
+		Sets:        int32(volume.Sets),
+		Reps:        int32(volume.Reps),
+		VolumeKg:    volume.VolumeKg,
+	}
+}
+
+// oneRepMaxFormulas maps the formulas of requests to the analytics ones.
+var oneRepMaxFormulas = map[generated.OneRepMaxFormula]analytics.Formula{
+	generated.OneRepMaxFormula_ONE_REP_MAX_FORMULA_EPLEY:   analytics.Epley,
+	generated.OneRepMaxFormula_ONE_REP_MAX_FORMULA_BRZYCKI: analytics.Brzycki,
+}
+
+func (h *OloHandler) GetExerciseStats(ctx context.Context, req *generated.GetExerciseStatsRequest) (*generated.GetExerciseStatsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetExerciseId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "exercise_id is required")
+	}
+	formula, ok := oneRepMaxFormulas[req.GetFormula()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown one-rep max formula")
+	}
+
+	stats, err := h.analytics.GetExerciseStats(user.ID, req.GetExerciseId(), req.GetFrom(), req.GetTo(),
+		int(req.GetUtcOffsetMinutes())*60, formula)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.GetExerciseStatsResponse{
+		Exercise:     h.mapperExercise.Map(stats.Exercise),
+		OneRepMax:    stats.OneRepMax,
+		Records:      h.mapperRecord.MapEach(stats.Records),
+		Progress:     h.mapperProgress.MapEach(stats.Progress),
+		WeeklyVolume: h.mapperVolume.MapEach(stats.WeeklyVolume),
+	}, nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/internal/service"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serviceError converts an error returned by a service to a gRPC status error.
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrArticleNotFound),
+		errors.Is(err, service.ErrWidgetNotFound),
+		errors.Is(err, service.ErrRevisionNotFound),
+		errors.Is(err, service.ErrMetricNotFound),
+		errors.Is(err, service.ErrWorkoutNotFound),
+		errors.Is(err, service.ErrExerciseNotFound),
+		errors.Is(err, service.ErrFoodNotFound),
+		errors.Is(err, service.ErrMealNotFound),
+		errors.Is(err, service.ErrProfileNotFound),
+		errors.Is(err, service.ErrGoalNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrInvalidPageToken),
+		errors.Is(err, service.ErrEmptySearchQuery),
+		errors.Is(err, service.ErrCategoryNotFound),
+		errors.Is(err, service.ErrUnknownWidgetType),
+		errors.Is(err, service.ErrInvalidWidgetData),
+		errors.Is(err, service.ErrWidgetTypeChanged),
+		errors.Is(err, service.ErrInvalidLayout),
+		errors.Is(err, service.ErrInvalidMetric),
+		errors.Is(err, service.ErrInvalidTimeRange),
+		errors.Is(err, service.ErrInvalidExercise),
+		errors.Is(err, service.ErrInvalidWorkout),
+		errors.Is(err, service.ErrInvalidDate),
+		errors.Is(err, service.ErrInvalidMeal),
+		errors.Is(err, service.ErrInvalidProfile),
+		errors.Is(err, service.ErrInvalidGoal):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrExerciseExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
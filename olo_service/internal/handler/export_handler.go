@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/export"
+)
+
+// exportChunkSize is the size of the parts the export archive is streamed in.
+const exportChunkSize = 64 * 1024
+
+func (h *OloHandler) ExportMyData(_ *generated.ExportMyDataRequest, stream generated.OLO_ExportMyDataServer) error {
+	user, err := h.getUser(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	w := export.NewChunkWriter(exportChunkSize, func(chunk []byte) error {
+		return stream.Send(&generated.ExportChunk{Data: chunk})
+	})
+	if err := h.exports.ExportUserData(stream.Context(), user.ID, w); err != nil {
+		return serviceError(err)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GoalToGoalResponse converts a Goal entity to a generated.Goal.
+func GoalToGoalResponse(goal entity.Goal) *generated.Goal {
+	return &generated.Goal{
+		Id:               goal.ID,
+		Title:            goal.Title,
+		Kind:             goal.Kind,
+		MetricType:       goal.MetricType,
+		Comparison:       goal.Comparison,
+		Target:           goal.Target,
+		StartValue:       goal.StartValue,
+		Deadline:         goal.Deadline,
+		UtcOffsetMinutes: int32(goal.UTCOffset / 60),
+		CreatedAt:        goal.CreatedAt,
+		Status:           goal.Status,
+		CurrentValue:     goal.CurrentValue,
+		Progress:         goal.Progress,
+		CurrentStreak:    int32(goal.CurrentStreak),
+		LongestStreak:    int32(goal.LongestStreak),
+		CompletedAt:      goal.CompletedAt,
+		EvaluatedAt:      goal.EvaluatedAt,
+	}
+}
+
+// GoalDayToResponse converts a GoalDay entity to a generated.GoalDay.
+func GoalDayToResponse(day entity.GoalDay) *generated.GoalDay {
+	return &generated.GoalDay{
+		Date:  day.Date,
+		Value: day.Value,
+		Met:   day.Met,
+	}
+}
+
+func (h *OloHandler) CreateGoal(ctx context.Context, req *generated.CreateGoalRequest) (*generated.Goal, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := h.goals.CreateGoal(user.ID, entity.Goal{
+		Title:      req.GetTitle(),
+		Kind:       req.GetKind(),
+		MetricType: req.GetMetricType(),
+		Comparison: req.GetComparison(),
+		Target:     req.GetTarget(),
+		StartValue: req.GetStartValue(),
+		Deadline:   req.GetDeadline(),
+		UTCOffset:  int(req.GetUtcOffsetMinutes()) * 60,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperGoal.Map(goal), nil
+}
+
+func (h *OloHandler) ListGoals(ctx context.Context, req *generated.ListGoalsRequest) (*generated.ListGoalsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := h.goals.ListGoals(user.ID, req.GetStatus())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ListGoalsResponse{
+		Goals: h.mapperGoal.MapEach(goals),
+	}, nil
+}
+
+func (h *OloHandler) GetGoalProgress(ctx context.Context, req *generated.GetGoalProgressRequest) (*generated.GoalProgress, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	goal, history, err := h.goals.GetGoalProgress(user.ID, req.GetId(), int(req.GetDays()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.GoalProgress{
+		Goal:    h.mapperGoal.Map(goal),
+		History: h.mapperGoalDay.MapEach(history),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+)
+
+// MetricToMetricResponse converts a Metric entity to a generated.Metric.
+func MetricToMetricResponse(metric entity.Metric) *generated.Metric {
+	return &generated.Metric{
+		Id:         metric.ID,
+		Type:       metric.Type,
+		RecordedAt: metric.RecordedAt,
+		Value:      metric.Value,
+		Unit:       metric.Unit,
+		Source:     metric.Source,
+	}
+}
+
+// MetricBucketToResponse converts a MetricBucket entity to a generated.MetricBucket.
+func MetricBucketToResponse(bucket entity.MetricBucket) *generated.MetricBucket {
+	return &generated.MetricBucket{
+		Start: bucket.Start,
+		Count: bucket.Count,
+		Avg:   bucket.Avg,
+		Min:   bucket.Min,
+		Max:   bucket.Max,
+		Sum:   bucket.Sum,
+	}
+}
+
+func (h *OloHandler) RecordMetrics(ctx context.Context, req *generated.RecordMetricsRequest) (*generated.RecordMetricsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]entity.Metric, len(req.GetMetrics()))
+	for i, metric := range req.GetMetrics() {
+		metrics[i] = entity.Metric{
+			Type:       metric.GetType(),
+			RecordedAt: metric.GetRecordedAt(),
+			Value:      metric.GetValue(),
+			Unit:       metric.GetUnit(),
+			Source:     metric.GetSource(),
+		}
+	}
+
+	if err := h.metrics.RecordMetrics(user.ID, metrics); err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.RecordMetricsResponse{
+		Recorded: int32(len(metrics)),
+	}, nil
+}
+
+func (h *OloHandler) QueryMetrics(ctx context.Context, req *generated.QueryMetricsRequest) (*generated.QueryMetricsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, unit, err := h.metrics.QueryMetrics(user.ID, entity.MetricQuery{
+		Type:      req.GetType(),
+		From:      req.GetFrom(),
+		To:        req.GetTo(),
+		Bucket:    entity.MetricBucketSize(req.GetBucket()),
+		UTCOffset: int(req.GetUtcOffsetMinutes()) * 60,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.QueryMetricsResponse{
+		Buckets: h.mapperBucket.MapEach(buckets),
+		Unit:    unit,
+	}, nil
+}
+
+func (h *OloHandler) GetLatestMetric(ctx context.Context, req *generated.GetLatestMetricRequest) (*generated.Metric, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	metric, err := h.metrics.GetLatestMetric(user.ID, req.GetType())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperMetric.Map(metric), nil
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// nutrientsToResponse converts Nutrients to a generated.Nutrients, nil stays nil.
+func nutrientsToResponse(nutrients *entity.Nutrients) *generated.Nutrients {
+	if nutrients == nil {
+		return nil
+	}
+	return &generated.Nutrients{
+		Calories: nutrients.Calories,
+		Protein:  nutrients.Protein,
+		Fat:      nutrients.Fat,
+		Carbs:    nutrients.Carbs,
+	}
+}
+
+// FoodToFoodResponse converts a Food entity to a generated.Food.
+func FoodToFoodResponse(food entity.Food) *generated.Food {
+	return &generated.Food{
+		Id:       food.ID,
+		Name:     food.Name,
+		Per_100G: nutrientsToResponse(&food.Nutrients),
+	}
+}
+
+// MealToMealResponse converts a Meal entity to a generated.Meal.
+func MealToMealResponse(meal entity.Meal) *generated.Meal {
+	return &generated.Meal{
+		Id:        meal.ID,
+		Date:      meal.Date,
+		MealType:  meal.MealType,
+		FoodId:    meal.FoodID,
+		FoodName:  meal.FoodName,
+		Grams:     meal.Grams,
+		Nutrients: nutrientsToResponse(&meal.Nutrients),
+		CreatedAt: meal.CreatedAt,
+	}
+}
+
+// DayNutrientsToResponse converts a DayNutrients entity to a generated.DayNutrients.
+func DayNutrientsToResponse(day entity.DayNutrients) *generated.DayNutrients {
+	return &generated.DayNutrients{
+		Date:      day.Date,
+		Nutrients: nutrientsToResponse(&day.Nutrients),
+	}
+}
+
+func (h *OloHandler) SearchFoods(_ context.Context, req *generated.SearchFoodsRequest) (*generated.SearchFoodsResponse, error) {
+	foods, err := h.nutrition.SearchFoods(req.GetQuery(), int(req.GetLimit()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.SearchFoodsResponse{
+		Foods: h.mapperFood.MapEach(foods),
+	}, nil
+}
+
+func (h *OloHandler) LogMeal(ctx context.Context, req *generated.LogMealRequest) (*generated.Meal, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFoodId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "food_id is required")
+	}
+
+	meal, err := h.nutrition.LogMeal(user.ID, entity.Meal{
+		Date:     req.GetDate(),
+		MealType: req.GetMealType(),
+		FoodID:   req.GetFoodId(),
+		Grams:    req.GetGrams(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperMeal.Map(meal), nil
+}
+
+func (h *OloHandler) DeleteMeal(ctx context.Context, req *generated.DeleteMealRequest) (*generated.MealResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := h.nutrition.DeleteMeal(user.ID, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.MealResponse{
+		Response: fmt.Sprintf("Successfully delete meal (%d) for user (%d)!", req.GetId(), user.ID),
+	}, nil
+}
+
+func (h *OloHandler) GetDailyNutrition(ctx context.Context, req *generated.GetDailyNutritionRequest) (*generated.DailyNutrition, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nutrition, err := h.nutrition.GetDailyNutrition(user.ID, req.GetDate())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.DailyNutrition{
+		Date:   nutrition.Date,
+		Totals: nutrientsToResponse(&nutrition.Totals),
+		Meals:  h.mapperMeal.MapEach(nutrition.Meals),
+	}, nil
+}
+
+func (h *OloHandler) GetWeeklyNutrition(ctx context.Context, req *generated.GetWeeklyNutritionRequest) (*generated.WeeklyNutrition, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	week, err := h.nutrition.GetWeeklyNutrition(user.ID, req.GetDate())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.WeeklyNutrition{
+		WeekStart: week.WeekStart,
+		Days:      h.mapperDay.MapEach(week.Days),
+		Totals:    nutrientsToResponse(&week.Totals),
+		Average:   nutrientsToResponse(&week.Average),
+	}, nil
+}
@@ -1,6 +1,6 @@
 // Package handler provides gRPC handler functions for OLO service endpoints.
 //
-// This package includes handler functions for handling gRPC requests related to articles and widgets.
+// This package includes handler functions for handling gRPC requests related to articles, widgets, health metrics, workouts, training analytics, nutrition, fitness profiles and goals.
 package handler
 
 import (
@@ -8,19 +8,44 @@ import (
 	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/mapper"
 	"OLO-backend/olo_service/internal/service"
+	"OLO-backend/olo_service/internal/widgettype"
 	"OLO-backend/pkg/utils/jwt"
 	"context"
 	"fmt"
-	jwtgo "github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"slices"
+	"strings"
+	"unicode/utf8"
 )
 
 // ArticleToArticleResponse converts an Article entity to a generated.Article.
 func ArticleToArticleResponse(article entity.Article) *generated.Article {
 	return &generated.Article{
-		Id:     uint64(article.ID),
-		Header: article.Header,
+		Id:         uint64(article.ID),
+		Header:     article.Header,
+		Body:       article.Body,
+		CategoryId: uint64(article.CategoryID),
+		Tags:       article.Tags,
+	}
+}
+
+// CategoryToCategoryResponse converts a Category entity to a generated.Category.
+func CategoryToCategoryResponse(category entity.Category) *generated.Category {
+	return &generated.Category{
+		Id:   uint64(category.ID),
+		Slug: category.Slug,
+		Name: category.Name,
+	}
+}
+
+// ArticleSearchHitToResponse converts an ArticleSearchHit entity to a generated.ArticleSearchHit.
+func ArticleSearchHitToResponse(hit entity.ArticleSearchHit) *generated.ArticleSearchHit {
+	return &generated.ArticleSearchHit{
+		Article:       ArticleToArticleResponse(hit.Article),
+		Score:         hit.Score,
+		HeaderSnippet: hit.HeaderSnippet,
+		BodySnippet:   hit.BodySnippet,
 	}
 }
 
@@ -29,59 +54,133 @@ func WidgetToWidgetResponse(widget entity.Widget) *generated.Widget {
 	return &generated.Widget{
 		Id:   widget.ID,
 		Data: widget.Data,
+		Type: widget.Type,
+		Layout: &generated.WidgetLayout{
+			Position: int32(widget.Position),
+			X:        int32(widget.X),
+			Y:        int32(widget.Y),
+			Width:    int32(widget.Width),
+			Height:   int32(widget.Height),
+			Visible:  widget.Visible,
+		},
+		Version:   int32(widget.Version),
+		Nutrition: nutrientsToResponse(widget.Nutrition),
+		Defaults:  widgetDefaultsToResponse(widget.Defaults),
+	}
+}
+
+// WidgetRevisionToResponse converts a WidgetRevision entity to a generated.WidgetRevision.
+func WidgetRevisionToResponse(revision entity.WidgetRevision) *generated.WidgetRevision {
+	return &generated.WidgetRevision{
+		Version:   int32(revision.Version),
+		Type:      revision.Type,
+		Data:      revision.Data,
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+// WidgetTypeToResponse converts a widget type to a generated.WidgetType.
+func WidgetTypeToResponse(widgetType widgettype.Type) *generated.WidgetType {
+	return &generated.WidgetType{
+		Name:   widgetType.Name,
+		Title:  widgetType.Title,
+		Schema: string(widgetType.Raw),
 	}
 }
 
 // OloHandler represents the gRPC handler for OLO service endpoints.
 type OloHandler struct {
 	service   *service.OloService
-	validator *jwt.Validator
+	metrics   *service.MetricService
+	workouts  *service.WorkoutService
+	analytics *service.AnalyticsService
+	nutrition *service.NutritionService
+	profiles  *service.ProfileService
+	goals     *service.GoalService
+	badges    *service.AchievementService
+	exports   *service.ExportService
+	accounts  *service.AccountService
 
-	mapperWidget  mapper.MapFunc[entity.Widget, *generated.Widget]
-	mapperArticle mapper.MapFunc[entity.Article, *generated.Article]
+	mapperWidget   mapper.MapFunc[entity.Widget, *generated.Widget]
+	mapperArticle  mapper.MapFunc[entity.Article, *generated.Article]
+	mapperHit      mapper.MapFunc[entity.ArticleSearchHit, *generated.ArticleSearchHit]
+	mapperCategory mapper.MapFunc[entity.Category, *generated.Category]
+	mapperType     mapper.MapFunc[widgettype.Type, *generated.WidgetType]
+	mapperRevision mapper.MapFunc[entity.WidgetRevision, *generated.WidgetRevision]
+	mapperMetric   mapper.MapFunc[entity.Metric, *generated.Metric]
+	mapperBucket   mapper.MapFunc[entity.MetricBucket, *generated.MetricBucket]
+	mapperExercise mapper.MapFunc[entity.Exercise, *generated.Exercise]
+	mapperWorkout  mapper.MapFunc[entity.Workout, *generated.Workout]
+	mapperRecord   mapper.MapFunc[entity.PersonalRecord, *generated.PersonalRecord]
+	mapperProgress mapper.MapFunc[entity.ExerciseProgress, *generated.ExerciseProgress]
+	mapperVolume   mapper.MapFunc[entity.MuscleGroupVolume, *generated.MuscleGroupVolume]
+	mapperFood     mapper.MapFunc[entity.Food, *generated.Food]
+	mapperMeal     mapper.MapFunc[entity.Meal, *generated.Meal]
+	mapperDay      mapper.MapFunc[entity.DayNutrients, *generated.DayNutrients]
+	mapperGoal     mapper.MapFunc[entity.Goal, *generated.Goal]
+	mapperGoalDay  mapper.MapFunc[entity.GoalDay, *generated.GoalDay]
+	mapperBadge    mapper.MapFunc[entity.Achievement, *generated.Achievement]
 
 	generated.UnimplementedOLOServer
 }
 
-func NewOloHandler(service *service.OloService, validator *jwt.Validator) *OloHandler {
+func NewOloHandler(service *service.OloService, metrics *service.MetricService, workouts *service.WorkoutService,
+	analytics *service.AnalyticsService, nutrition *service.NutritionService, profiles *service.ProfileService,
+	goals *service.GoalService, badges *service.AchievementService, exports *service.ExportService,
+	accounts *service.AccountService) *OloHandler {
 	return &OloHandler{
 		service:   service,
-		validator: validator,
+		metrics:   metrics,
+		workouts:  workouts,
+		analytics: analytics,
+		nutrition: nutrition,
+		profiles:  profiles,
+		goals:     goals,
+		badges:    badges,
+		exports:   exports,
+		accounts:  accounts,
 
-		mapperWidget:  WidgetToWidgetResponse,
-		mapperArticle: ArticleToArticleResponse,
+		mapperWidget:   WidgetToWidgetResponse,
+		mapperArticle:  ArticleToArticleResponse,
+		mapperHit:      ArticleSearchHitToResponse,
+		mapperCategory: CategoryToCategoryResponse,
+		mapperType:     WidgetTypeToResponse,
+		mapperRevision: WidgetRevisionToResponse,
+		mapperMetric:   MetricToMetricResponse,
+		mapperBucket:   MetricBucketToResponse,
+		mapperExercise: ExerciseToExerciseResponse,
+		mapperWorkout:  WorkoutToWorkoutResponse,
+		mapperRecord:   PersonalRecordToResponse,
+		mapperProgress: ExerciseProgressToResponse,
+		mapperVolume:   MuscleGroupVolumeToResponse,
+		mapperFood:     FoodToFoodResponse,
+		mapperMeal:     MealToMealResponse,
+		mapperDay:      DayNutrientsToResponse,
+		mapperGoal:     GoalToGoalResponse,
+		mapperGoalDay:  GoalDayToResponse,
+		mapperBadge:    AchievementToResponse,
 	}
 }
 
-// getToken retrieves and validates the JWT token from the context.
-func (h *OloHandler) getToken(ctx context.Context) (*jwtgo.Token, error) {
-	token, err := h.validator.TokenFromContextMetadata(ctx, "Authorization")
-	if err != nil {
-		// todo wrap error
-		return nil, fmt.Errorf("can't get token")
+// getUser returns the user the request was authenticated for by the jwt interceptor.
+func (h *OloHandler) getUser(ctx context.Context) (entity.User, error) {
+	user, ok := jwt.UserFromContext(ctx)
+	if !ok {
+		return entity.User{}, status.Error(codes.Unauthenticated, "request is not authenticated")
 	}
-	return token, nil
-}
-
-// newEntityUseToken extracts user information from the JWT token.
-func (h *OloHandler) newEntityUseToken(token *jwtgo.Token) entity.User {
-	id := int64(token.Claims.(jwtgo.MapClaims)["uid"].(float64))
-	email := token.Claims.(jwtgo.MapClaims)["email"].(string)
-	role := token.Claims.(jwtgo.MapClaims)["role"].(string)
 
 	return entity.User{
-		ID:    id,
-		Email: email,
-		Role:  role,
-	}
+		ID:    user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+	}, nil
 }
 
 func (h *OloHandler) HelloUser(ctx context.Context, _ *generated.HelloUserRequest) (*generated.HelloUserResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
 	return &generated.HelloUserResponse{
 		Message: fmt.Sprintf(
 			"Hello %s (%d)! I am the olo service. You have role %s",
@@ -89,15 +188,14 @@ func (h *OloHandler) HelloUser(ctx context.Context, _ *generated.HelloUserReques
 	}, nil
 }
 
-func (h *OloHandler) GetWidgets(ctx context.Context, _ *generated.GetWidgetsRequest) (*generated.GetWidgetsResponse, error) {
-	token, err := h.getToken(ctx)
+func (h *OloHandler) GetWidgets(ctx context.Context, req *generated.GetWidgetsRequest) (*generated.GetWidgetsResponse, error) {
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
-	widgets, err := h.service.GetWidgets(user.ID)
+	widgets, err := h.service.GetWidgets(user.ID, req.GetDate())
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
 	}
 
 	return &generated.GetWidgetsResponse{
@@ -106,16 +204,19 @@ func (h *OloHandler) GetWidgets(ctx context.Context, _ *generated.GetWidgetsRequ
 }
 
 func (h *OloHandler) UpdateWidget(ctx context.Context, req *generated.Widget) (*generated.WidgetResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
-	user := h.newEntityUseToken(token)
-
-	err = h.service.UpdateWidget(req.Data, req.Id, user.ID)
+	widget := entity.Widget{
+		ID:   req.GetId(),
+		Type: req.GetType(),
+		Data: req.GetData(),
+	}
+	err = h.service.UpdateWidget(widget, user.ID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
 	}
 	return &generated.WidgetResponse{
 		Response: fmt.Sprintf(
@@ -124,17 +225,29 @@ func (h *OloHandler) UpdateWidget(ctx context.Context, req *generated.Widget) (*
 	}, nil
 }
 
+func (h *OloHandler) ListWidgetTypes(_ context.Context, _ *generated.ListWidgetTypesRequest) (*generated.ListWidgetTypesResponse, error) {
+	return &generated.ListWidgetTypesResponse{
+		Types: h.mapperType.MapEach(h.service.ListWidgetTypes()),
+	}, nil
+}
+
 func (h *OloHandler) AddWidget(ctx context.Context, req *generated.AddWidgetRequest) (*generated.WidgetResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
-	user := h.newEntityUseToken(token)
+	if req.GetType() == "" {
+		return nil, status.Error(codes.InvalidArgument, "type is required")
+	}
 
-	widgetId, err := h.service.AddWidget(req.Data, user.ID)
+	widget := entity.Widget{
+		Type: req.GetType(),
+		Data: req.GetData(),
+	}
+	widgetId, err := h.service.AddWidget(widget, user.ID)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
 	}
 	return &generated.WidgetResponse{
 		Response: fmt.Sprintf(
@@ -143,43 +256,83 @@ func (h *OloHandler) AddWidget(ctx context.Context, req *generated.AddWidgetRequ
 	}, nil
 }
 
-func (h *OloHandler) GetUsersArticles(ctx context.Context, _ *generated.GetAllArticlesRequest) (*generated.GetAllArticlesResponse, error) {
-	token, err := h.getToken(ctx)
+func (h *OloHandler) GetUsersArticles(ctx context.Context, req *generated.GetAllArticlesRequest) (*generated.GetAllArticlesResponse, error) {
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
-	articles, err := h.service.GetUsersArticles(user.ID)
+	articles, nextPageToken, err := h.service.GetUsersArticles(user.ID, articleQuery(req))
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
 	}
 
 	return &generated.GetAllArticlesResponse{
-		Articles: h.mapperArticle.MapEach(articles),
+		Articles:      h.mapperArticle.MapEach(articles),
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
-func (h *OloHandler) GetAllArticles(ctx context.Context, _ *generated.GetAllArticlesRequest) (*generated.GetAllArticlesResponse, error) {
-	_, err := h.getToken(ctx)
+func (h *OloHandler) GetAllArticles(_ context.Context, req *generated.GetAllArticlesRequest) (*generated.GetAllArticlesResponse, error) {
+	articles, nextPageToken, err := h.service.GetAllArticles(articleQuery(req))
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
+	}
+
+	return &generated.GetAllArticlesResponse{
+		Articles:      h.mapperArticle.MapEach(articles),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// articleQuery converts the listing parameters of the request to an entity.ArticleQuery.
+func articleQuery(req *generated.GetAllArticlesRequest) entity.ArticleQuery {
+	return entity.ArticleQuery{
+		PageSize:     int(req.GetPageSize()),
+		PageToken:    req.GetPageToken(),
+		Sort:         entity.ArticleSort(req.GetSort()),
+		HeaderFilter: req.GetHeaderFilter(),
+		Tag:          normalizeTag(req.GetTag()),
+		CategoryID:   int64(req.GetCategoryId()),
+	}
+}
+
+func (h *OloHandler) GetArticlesByTag(_ context.Context, req *generated.GetArticlesByTagRequest) (*generated.GetAllArticlesResponse, error) {
+	tag := normalizeTag(req.GetTag())
+	if tag == "" {
+		return nil, status.Error(codes.InvalidArgument, "tag is required")
 	}
-	articles, err := h.service.GetAllArticles()
+
+	articles, nextPageToken, err := h.service.GetAllArticles(entity.ArticleQuery{
+		PageSize:  int(req.GetPageSize()),
+		PageToken: req.GetPageToken(),
+		Sort:      entity.ArticleSort(req.GetSort()),
+		Tag:       tag,
+	})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceError(err)
 	}
 
 	return &generated.GetAllArticlesResponse{
-		Articles: h.mapperArticle.MapEach(articles),
+		Articles:      h.mapperArticle.MapEach(articles),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (h *OloHandler) ListCategories(_ context.Context, _ *generated.ListCategoriesRequest) (*generated.ListCategoriesResponse, error) {
+	categories, err := h.service.ListCategories()
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ListCategoriesResponse{
+		Categories: h.mapperCategory.MapEach(categories),
 	}, nil
 }
 
 func (h *OloHandler) AddArticleForUser(ctx context.Context, req *generated.ArticleForUserRequest) (*generated.ArticleForUserResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
 	err = h.service.AddArticleForUser(req.ArticleId, user.ID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -192,11 +345,10 @@ func (h *OloHandler) AddArticleForUser(ctx context.Context, req *generated.Artic
 }
 
 func (h *OloHandler) DeleteArticleForUser(ctx context.Context, req *generated.ArticleForUserRequest) (*generated.ArticleForUserResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
 	err = h.service.DeleteArticleForUser(req.ArticleId, user.ID)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -208,12 +360,74 @@ func (h *OloHandler) DeleteArticleForUser(ctx context.Context, req *generated.Ar
 	}, nil
 }
 
+func (h *OloHandler) GetWidgetHistory(ctx context.Context, req *generated.GetWidgetHistoryRequest) (*generated.GetWidgetHistoryResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetWidgetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "widget_id is required")
+	}
+
+	revisions, err := h.service.GetWidgetHistory(req.GetWidgetId(), user.ID, int(req.GetBeforeVersion()), int(req.GetPageSize()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.GetWidgetHistoryResponse{
+		Revisions: h.mapperRevision.MapEach(revisions),
+	}, nil
+}
+
+func (h *OloHandler) RestoreWidgetVersion(ctx context.Context, req *generated.RestoreWidgetVersionRequest) (*generated.Widget, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetWidgetId() == 0 || req.GetVersion() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "widget_id and version are required")
+	}
+
+	widget, err := h.service.RestoreWidgetVersion(req.GetWidgetId(), int(req.GetVersion()), user.ID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperWidget.Map(widget), nil
+}
+
+func (h *OloHandler) ReorderWidgets(ctx context.Context, req *generated.ReorderWidgetsRequest) (*generated.GetWidgetsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	widgets := make([]entity.Widget, len(req.GetWidgets()))
+	for i, placement := range req.GetWidgets() {
+		widgets[i] = entity.Widget{
+			ID: placement.GetWidgetId(),
+			WidgetLayout: entity.WidgetLayout{
+				X:       int(placement.GetX()),
+				Y:       int(placement.GetY()),
+				Width:   int(placement.GetWidth()),
+				Height:  int(placement.GetHeight()),
+				Visible: placement.GetVisible(),
+			},
+		}
+	}
+
+	widgets, err = h.service.ReorderWidgets(user.ID, widgets)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.GetWidgetsResponse{
+		Widgets: h.mapperWidget.MapEach(widgets),
+	}, nil
+}
+
 func (h *OloHandler) DeleteWidget(ctx context.Context, req *generated.DeleteWidgetRequest) (*generated.WidgetResponse, error) {
-	token, err := h.getToken(ctx)
+	user, err := h.getUser(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
-	user := h.newEntityUseToken(token)
 
 	// todo validate params and throw error if data not right
 	err = h.service.DeleteWidgetForUser(req.WidgetId, user.ID)
@@ -226,3 +440,134 @@ func (h *OloHandler) DeleteWidget(ctx context.Context, req *generated.DeleteWidg
 			req.WidgetId, user.ID),
 	}, nil
 }
+
+func (h *OloHandler) SearchArticles(_ context.Context, req *generated.SearchArticlesRequest) (*generated.SearchArticlesResponse, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	hits, err := h.service.SearchArticles(req.GetQuery(), int(req.GetLimit()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.SearchArticlesResponse{
+		Hits: h.mapperHit.MapEach(hits),
+	}, nil
+}
+
+func (h *OloHandler) GetArticle(_ context.Context, req *generated.GetArticleRequest) (*generated.Article, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	article, err := h.service.GetArticle(int64(req.GetId()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperArticle.Map(article), nil
+}
+
+func (h *OloHandler) CreateArticle(_ context.Context, req *generated.CreateArticleRequest) (*generated.Article, error) {
+	if err := validateArticle(req.GetHeader()); err != nil {
+		return nil, err
+	}
+	tags, err := articleTags(req.GetTags())
+	if err != nil {
+		return nil, err
+	}
+
+	article, err := h.service.CreateArticle(entity.Article{
+		Header:     req.GetHeader(),
+		Body:       req.GetBody(),
+		CategoryID: int64(req.GetCategoryId()),
+		Tags:       tags,
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperArticle.Map(article), nil
+}
+
+func (h *OloHandler) UpdateArticle(_ context.Context, req *generated.Article) (*generated.Article, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := validateArticle(req.GetHeader()); err != nil {
+		return nil, err
+	}
+	tags, err := articleTags(req.GetTags())
+	if err != nil {
+		return nil, err
+	}
+
+	article := entity.Article{
+		ID:         int64(req.GetId()),
+		Header:     req.GetHeader(),
+		Body:       req.GetBody(),
+		CategoryID: int64(req.GetCategoryId()),
+		Tags:       tags,
+	}
+	if err := h.service.UpdateArticle(article); err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperArticle.Map(article), nil
+}
+
+func (h *OloHandler) DeleteArticle(_ context.Context, req *generated.DeleteArticleRequest) (*generated.ArticleResponse, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := h.service.DeleteArticle(int64(req.GetId())); err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ArticleResponse{
+		Response: fmt.Sprintf("Successfully delete article (%d)!", req.GetId()),
+	}, nil
+}
+
+// maxArticleHeaderLength is the size of the articles.header column.
+const maxArticleHeaderLength = 100
+
+// validateArticle validates the article fields set by an admin.
+func validateArticle(header string) error {
+	if header == "" {
+		return status.Error(codes.InvalidArgument, "header is required")
+	}
+	if utf8.RuneCountInString(header) > maxArticleHeaderLength {
+		return status.Errorf(codes.InvalidArgument, "header must be at most %d characters", maxArticleHeaderLength)
+	}
+	return nil
+}
+
+// Limits of article tags, maxTagLength is the size of the tags.name column.
+const (
+	maxTagLength     = 50
+	maxTagsOfArticle = 10
+)
+
+// normalizeTag returns the tag in the form it is stored in.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// articleTags normalizes and validates the tags of an article, duplicates are dropped.
+func articleTags(tags []string) ([]string, error) {
+	var result []string
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			return nil, status.Error(codes.InvalidArgument, "tag must not be empty")
+		}
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return nil, status.Errorf(codes.InvalidArgument, "tag must be at most %d characters", maxTagLength)
+		}
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	if len(result) > maxTagsOfArticle {
+		return nil, status.Errorf(codes.InvalidArgument, "article can have at most %d tags", maxTagsOfArticle)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+)
+
+// widgetDefaultsToResponse converts WidgetDefaults to a generated.WidgetDefaults, nil stays nil.
+func widgetDefaultsToResponse(defaults *entity.WidgetDefaults) *generated.WidgetDefaults {
+	if defaults == nil {
+		return nil
+	}
+	return &generated.WidgetDefaults{
+		GoalKcal: defaults.GoalKcal,
+		Macros:   nutrientsToResponse(defaults.Macros),
+		TargetKg: defaults.TargetKg,
+	}
+}
+
+// profileResponse converts a profile with its calculations to a generated.ProfileResponse.
+func profileResponse(profile entity.Profile, calculations *entity.ProfileCalculations) *generated.ProfileResponse {
+	response := &generated.ProfileResponse{
+		Profile: &generated.Profile{
+			HeightCm:      profile.HeightCm,
+			BirthDate:     profile.BirthDate,
+			Sex:           profile.Sex,
+			ActivityLevel: profile.ActivityLevel,
+			Units:         profile.Units,
+			GoalWeightKg:  profile.GoalWeightKg,
+			UpdatedAt:     profile.UpdatedAt,
+		},
+	}
+	if calculations != nil {
+		response.Calculations = &generated.ProfileCalculations{
+			WeightKg:    calculations.WeightKg,
+			Bmi:         calculations.BMI,
+			BmiCategory: calculations.BMICategory,
+			Bmr:         calculations.BMR,
+			Tdee:        calculations.TDEE,
+			Targets:     nutrientsToResponse(&calculations.Targets),
+		}
+	}
+	return response
+}
+
+func (h *OloHandler) GetProfile(ctx context.Context, _ *generated.GetProfileRequest) (*generated.ProfileResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, calculations, err := h.profiles.GetProfile(user.ID)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return profileResponse(profile, calculations), nil
+}
+
+func (h *OloHandler) UpdateProfile(ctx context.Context, req *generated.Profile) (*generated.ProfileResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, calculations, err := h.profiles.UpdateProfile(user.ID, entity.Profile{
+		HeightCm:      req.GetHeightCm(),
+		BirthDate:     req.GetBirthDate(),
+		Sex:           req.GetSex(),
+		ActivityLevel: req.GetActivityLevel(),
+		Units:         req.GetUnits(),
+		GoalWeightKg:  req.GetGoalWeightKg(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return profileResponse(profile, calculations), nil
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"OLO-backend/olo_service/generated"
+	"OLO-backend/olo_service/internal/entity"
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ExerciseToExerciseResponse converts an Exercise entity to a generated.Exercise.
+func ExerciseToExerciseResponse(exercise entity.Exercise) *generated.Exercise {
+	return &generated.Exercise{
+		Id:          exercise.ID,
+		Name:        exercise.Name,
+		MuscleGroup: exercise.MuscleGroup,
+		Kind:        exercise.Kind,
+	}
+}
+
+// WorkoutToWorkoutResponse converts a Workout entity to a generated.Workout.
+func WorkoutToWorkoutResponse(workout entity.Workout) *generated.Workout {
+	sets := make([]*generated.WorkoutSet, len(workout.Sets))
+	for i, set := range workout.Sets {
+		sets[i] = &generated.WorkoutSet{
+			ExerciseId:  set.ExerciseID,
+			Reps:        int32(set.Reps),
+			WeightKg:    set.WeightKg,
+			DurationSec: int32(set.DurationSec),
+			DistanceM:   set.DistanceM,
+		}
+	}
+	return &generated.Workout{
+		Id:         workout.ID,
+		Name:       workout.Name,
+		StartedAt:  workout.StartedAt,
+		FinishedAt: workout.FinishedAt,
+		Notes:      workout.Notes,
+		Sets:       sets,
+	}
+}
+
+// workoutFromRequest converts a generated.Workout to a Workout entity.
+func workoutFromRequest(req *generated.Workout) entity.Workout {
+	sets := make([]entity.WorkoutSet, len(req.GetSets()))
+	for i, set := range req.GetSets() {
+		sets[i] = entity.WorkoutSet{
+			ExerciseID:  set.GetExerciseId(),
+			Reps:        int(set.GetReps()),
+			WeightKg:    set.GetWeightKg(),
+			DurationSec: int(set.GetDurationSec()),
+			DistanceM:   set.GetDistanceM(),
+		}
+	}
+	return entity.Workout{
+		ID:         req.GetId(),
+		Name:       req.GetName(),
+		StartedAt:  req.GetStartedAt(),
+		FinishedAt: req.GetFinishedAt(),
+		Notes:      req.GetNotes(),
+		Sets:       sets,
+	}
+}
+
+func (h *OloHandler) ListExercises(_ context.Context, req *generated.ListExercisesRequest) (*generated.ListExercisesResponse, error) {
+	exercises, err := h.workouts.ListExercises(req.GetMuscleGroup())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ListExercisesResponse{
+		Exercises: h.mapperExercise.MapEach(exercises),
+	}, nil
+}
+
+func (h *OloHandler) CreateExercise(_ context.Context, req *generated.Exercise) (*generated.Exercise, error) {
+	exercise, err := h.workouts.CreateExercise(entity.Exercise{
+		Name:        req.GetName(),
+		MuscleGroup: req.GetMuscleGroup(),
+		Kind:        req.GetKind(),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperExercise.Map(exercise), nil
+}
+
+func (h *OloHandler) CreateWorkout(ctx context.Context, req *generated.Workout) (*generated.Workout, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workout, records, err := h.workouts.CreateWorkout(user.ID, workoutFromRequest(req))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	response := h.mapperWorkout.Map(workout)
+	response.NewRecords = h.mapperRecord.MapEach(records)
+	return response, nil
+}
+
+func (h *OloHandler) GetWorkout(ctx context.Context, req *generated.GetWorkoutRequest) (*generated.Workout, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	workout, err := h.workouts.GetWorkout(user.ID, req.GetId())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return h.mapperWorkout.Map(workout), nil
+}
+
+func (h *OloHandler) UpdateWorkout(ctx context.Context, req *generated.Workout) (*generated.Workout, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	workout, records, err := h.workouts.UpdateWorkout(user.ID, workoutFromRequest(req))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	response := h.mapperWorkout.Map(workout)
+	response.NewRecords = h.mapperRecord.MapEach(records)
+	return response, nil
+}
+
+func (h *OloHandler) DeleteWorkout(ctx context.Context, req *generated.DeleteWorkoutRequest) (*generated.WorkoutResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	if err := h.workouts.DeleteWorkout(user.ID, req.GetId()); err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.WorkoutResponse{
+		Response: fmt.Sprintf("Successfully delete workout (%d) for user (%d)!", req.GetId(), user.ID),
+	}, nil
+}
+
+func (h *OloHandler) ListWorkouts(ctx context.Context, req *generated.ListWorkoutsRequest) (*generated.ListWorkoutsResponse, error) {
+	user, err := h.getUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workouts, nextPageToken, err := h.workouts.ListWorkouts(user.ID, req.GetFrom(), req.GetTo(), int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return &generated.ListWorkoutsResponse{
+		Workouts:      h.mapperWorkout.MapEach(workouts),
+		NextPageToken: nextPageToken,
+	}, nil
+}
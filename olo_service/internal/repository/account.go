@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/repository/provider"
+	"fmt"
+)
+
+type AccountRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewAccountRepo(mysqlProvider *provider.MySQLProvider) *AccountRepo {
+	return &AccountRepo{mysqlProvider: mysqlProvider}
+}
+
+// userTables are the tables with the data of users, the sets of workouts are deleted with their workouts.
+var userTables = []string{
+	"user_has_articles",
+	"widget_revisions",
+	"widgetsUser",
+	"metrics",
+	"workouts",
+	"meals",
+	"user_profiles",
+	"goals",
+	"user_achievements",
+}
+
+// PurgeUserData deletes all the data of the user in one transaction.
+func (r *AccountRepo) PurgeUserData(userId int64) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range userTables {
+		if _, err := tx.Exec("DELETE FROM `"+table+"` WHERE `id_user` = ?", userId); err != nil {
+			return fmt.Errorf("error purge %s: %w", table, err)
+		}
+	}
+	return tx.Commit()
+}
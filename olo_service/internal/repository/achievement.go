@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"fmt"
+)
+
+type AchievementRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewAchievementRepo(mysqlProvider *provider.MySQLProvider) *AchievementRepo {
+	return &AchievementRepo{mysqlProvider: mysqlProvider}
+}
+
+// counterQueries are the queries of the activity counters of a user.
+var counterQueries = map[string]string{
+	achievements.CounterWorkouts:       "SELECT COUNT(*) FROM `workouts` WHERE `id_user` = ?",
+	achievements.CounterArticles:       "SELECT COUNT(*) FROM `user_has_articles` WHERE `id_user` = ?",
+	achievements.CounterMetrics:        "SELECT COUNT(*) FROM `metrics` WHERE `id_user` = ?",
+	achievements.CounterMeals:          "SELECT COUNT(*) FROM `meals` WHERE `id_user` = ?",
+	achievements.CounterMealDays:       "SELECT COUNT(DISTINCT `date`) FROM `meals` WHERE `id_user` = ?",
+	achievements.CounterGoalStreak:     "SELECT COALESCE(MAX(`longest_streak`), 0) FROM `goals` WHERE `id_user` = ? AND `kind` = 'daily'",
+	achievements.CounterGoalsCompleted: "SELECT COUNT(*) FROM `goals` WHERE `id_user` = ? AND `kind` = 'target' AND `status` = 'completed'",
+}
+
+// CountActivity returns the values of the activity counters of the user.
+func (r *AchievementRepo) CountActivity(userId int64, counters []string) (map[string]int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]int64, len(counters))
+	for _, counter := range counters {
+		query, ok := counterQueries[counter]
+		if !ok {
+			return nil, fmt.Errorf("unknown activity counter %q", counter)
+		}
+		var value int64
+		if err := driver.Get(&value, query, userId); err != nil {
+			return nil, fmt.Errorf("error count %s: %w", counter, err)
+		}
+		values[counter] = value
+	}
+	return values, nil
+}
+
+func (r *AchievementRepo) GetEarnedAchievements(userId int64) ([]entity.EarnedAchievement, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var earned []entity.EarnedAchievement
+	err = driver.Select(&earned, "SELECT `achievement_id`, `earned_at` FROM `user_achievements` WHERE `id_user` = ?", userId)
+	if err != nil {
+		return nil, fmt.Errorf("error get achievements: %w", err)
+	}
+	return earned, nil
+}
+
+// AwardAchievement saves the achievement of the user and reports whether it is new.
+// Awarding an earned achievement again keeps the time it was earned at.
+func (r *AchievementRepo) AwardAchievement(userId int64, achievementId string, earnedAt int64) (bool, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return false, err
+	}
+
+	res, err := driver.Exec("INSERT IGNORE INTO `user_achievements` (`id_user`, `achievement_id`, `earned_at`) VALUES (?, ?, ?)",
+		userId, achievementId, earnedAt)
+	if err != nil {
+		return false, fmt.Errorf("error award achievement: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
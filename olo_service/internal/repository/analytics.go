@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+)
+
+// GetLoggedSets returns the sets of the user's workouts started in [from, to) in the order they were done.
+// Only sets of the exercise are returned if it is set, zero from and to mean no bound.
+func (r *WorkoutRepo) GetLoggedSets(userId, exerciseId int64, from, to int64) ([]entity.LoggedSet, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT s.`id`, s.`id_workout`, s.`id_exercise`, s.`reps`, s.`weight_kg`, s.`duration_sec`, s.`distance_m`, " +
+		"w.`started_at`, e.`muscle_group` FROM `workout_sets` s " +
+		"JOIN `workouts` w ON w.`id` = s.`id_workout` " +
+		"JOIN `exercises` e ON e.`id` = s.`id_exercise` " +
+		"WHERE w.`id_user` = ?"
+	args := []any{userId}
+	if exerciseId != 0 {
+		query += " AND s.`id_exercise` = ?"
+		args = append(args, exerciseId)
+	}
+	if from != 0 {
+		query += " AND w.`started_at` >= ?"
+		args = append(args, from)
+	}
+	if to != 0 {
+		query += " AND w.`started_at` < ?"
+		args = append(args, to)
+	}
+	query += " ORDER BY w.`started_at`, w.`id`, s.`position`"
+
+	var sets []entity.LoggedSet
+	if err := driver.Select(&sets, query, args...); err != nil {
+		return nil, fmt.Errorf("error get logged sets: %w", err)
+	}
+	return sets, nil
+}
+
+// GetBestSets returns the best sets of the exercises in the user's workouts started before the time,
+// the workout with the excluded id is skipped. Each returned set holds the maximum weight, duration
+// and distance of the sets with its number of reps, so it carries every personal record of the history.
+func (r *WorkoutRepo) GetBestSets(userId int64, exerciseIds []int64, before int64, excludeWorkoutId int64) ([]entity.WorkoutSet, error) {
+	if len(exerciseIds) == 0 {
+		return nil, nil
+	}
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sqlx.In("SELECT s.`id_exercise`, s.`reps`, MAX(s.`weight_kg`) AS `weight_kg`, "+
+		"MAX(s.`duration_sec`) AS `duration_sec`, MAX(s.`distance_m`) AS `distance_m` FROM `workout_sets` s "+
+		"JOIN `workouts` w ON w.`id` = s.`id_workout` "+
+		"WHERE w.`id_user` = ? AND w.`started_at` < ? AND w.`id` <> ? AND s.`id_exercise` IN (?) "+
+		"GROUP BY s.`id_exercise`, s.`reps`", userId, before, excludeWorkoutId, exerciseIds)
+	if err != nil {
+		return nil, err
+	}
+	var sets []entity.WorkoutSet
+	if err := driver.Select(&sets, query, args...); err != nil {
+		return nil, fmt.Errorf("error get best sets: %w", err)
+	}
+	return sets, nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"strings"
+)
+
+// articlePage builds a parameterized query of a page of articles.
+//
+// Pages are addressed by a cursor (keyset pagination): the next page starts
+// right after the last article of the previous one in the sort order, with the id
+// breaking ties between equal headers. It stays correct while articles are added or deleted
+// and doesn't get slower for far pages as OFFSET does.
+type articlePage struct {
+	query      entity.ArticleQuery
+	conditions []string
+	args       []any
+}
+
+// newArticlePage creates a page of the article listing starting after the cursor.
+func newArticlePage(query entity.ArticleQuery, after *entity.ArticleCursor) *articlePage {
+	p := &articlePage{query: query}
+
+	if query.HeaderFilter != "" {
+		p.where("w.header LIKE ?", "%"+escapeLike(query.HeaderFilter)+"%")
+	}
+	if query.Tag != "" {
+		p.where("w.id IN (SELECT at.id_articles FROM article_has_tags AS at JOIN tags AS t ON t.id = at.id_tag WHERE t.name = ?)", query.Tag)
+	}
+	if query.CategoryID != 0 {
+		p.where("w.category_id = ?", query.CategoryID)
+	}
+
+	if after != nil {
+		switch query.Sort {
+		case entity.ArticleSortIDDesc:
+			p.where("w.id < ?", after.ID)
+		case entity.ArticleSortHeaderAsc:
+			p.where("(w.header > ? OR (w.header = ? AND w.id > ?))", after.Header, after.Header, after.ID)
+		case entity.ArticleSortHeaderDesc:
+			p.where("(w.header < ? OR (w.header = ? AND w.id < ?))", after.Header, after.Header, after.ID)
+		default:
+			p.where("w.id > ?", after.ID)
+		}
+	}
+	return p
+}
+
+// where adds a condition with its arguments to the query.
+func (p *articlePage) where(condition string, args ...any) {
+	p.conditions = append(p.conditions, condition)
+	p.args = append(p.args, args...)
+}
+
+// build returns the query and its arguments.
+func (p *articlePage) build() (string, []any) {
+	var sb strings.Builder
+	sb.WriteString("SELECT w.id AS `id`, w.header AS `header`, COALESCE(w.body, '') AS `body`, COALESCE(w.category_id, 0) AS `category_id` FROM articles AS w")
+
+	if len(p.conditions) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(p.conditions, " AND "))
+	}
+
+	switch p.query.Sort {
+	case entity.ArticleSortIDDesc:
+		sb.WriteString(" ORDER BY w.id DESC")
+	case entity.ArticleSortHeaderAsc:
+		sb.WriteString(" ORDER BY w.header ASC, w.id ASC")
+	case entity.ArticleSortHeaderDesc:
+		sb.WriteString(" ORDER BY w.header DESC, w.id DESC")
+	default:
+		sb.WriteString(" ORDER BY w.id ASC")
+	}
+
+	args := p.args
+	if p.query.PageSize > 0 {
+		sb.WriteString(" LIMIT ?")
+		args = append(args, p.query.PageSize)
+	}
+	return sb.String(), args
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const articleColumns = "SELECT w.id AS `id`, w.header AS `header`, COALESCE(w.body, '') AS `body`, COALESCE(w.category_id, 0) AS `category_id` FROM articles AS w"
+
+const tagCondition = "w.id IN (SELECT at.id_articles FROM article_has_tags AS at JOIN tags AS t ON t.id = at.id_tag WHERE t.name = ?)"
+
+func TestArticlePage(t *testing.T) {
+	after := &entity.ArticleCursor{ID: 7, Header: "Squats"}
+
+	tests := []struct {
+		name  string
+		query entity.ArticleQuery
+		after *entity.ArticleCursor
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "first page",
+			query: entity.ArticleQuery{PageSize: 20},
+			sql:   articleColumns + " ORDER BY w.id ASC LIMIT ?",
+			args:  []any{20},
+		},
+		{
+			name:  "id ascending",
+			query: entity.ArticleQuery{PageSize: 20},
+			after: after,
+			sql:   articleColumns + " WHERE w.id > ? ORDER BY w.id ASC LIMIT ?",
+			args:  []any{int64(7), 20},
+		},
+		{
+			name:  "id descending",
+			query: entity.ArticleQuery{PageSize: 20, Sort: entity.ArticleSortIDDesc},
+			after: after,
+			sql:   articleColumns + " WHERE w.id < ? ORDER BY w.id DESC LIMIT ?",
+			args:  []any{int64(7), 20},
+		},
+		{
+			name:  "header ascending",
+			query: entity.ArticleQuery{PageSize: 20, Sort: entity.ArticleSortHeaderAsc},
+			after: after,
+			sql:   articleColumns + " WHERE (w.header > ? OR (w.header = ? AND w.id > ?)) ORDER BY w.header ASC, w.id ASC LIMIT ?",
+			args:  []any{"Squats", "Squats", int64(7), 20},
+		},
+		{
+			name:  "header descending",
+			query: entity.ArticleQuery{PageSize: 20, Sort: entity.ArticleSortHeaderDesc},
+			after: after,
+			sql:   articleColumns + " WHERE (w.header < ? OR (w.header = ? AND w.id < ?)) ORDER BY w.header DESC, w.id DESC LIMIT ?",
+			args:  []any{"Squats", "Squats", int64(7), 20},
+		},
+		{
+			name:  "filter before the cursor",
+			query: entity.ArticleQuery{PageSize: 20, HeaderFilter: "100%_"},
+			after: after,
+			sql:   articleColumns + " WHERE w.header LIKE ? AND w.id > ? ORDER BY w.id ASC LIMIT ?",
+			args:  []any{`%100\%\_%`, int64(7), 20},
+		},
+		{
+			name:  "filter without limit",
+			query: entity.ArticleQuery{HeaderFilter: "squat"},
+			sql:   articleColumns + " WHERE w.header LIKE ? ORDER BY w.id ASC",
+			args:  []any{"%squat%"},
+		},
+		{
+			name:  "filter and category before the cursor",
+			query: entity.ArticleQuery{PageSize: 20, HeaderFilter: "100%_", CategoryID: 3},
+			after: after,
+			sql:   articleColumns + " WHERE w.header LIKE ? AND w.category_id = ? AND w.id > ? ORDER BY w.id ASC LIMIT ?",
+			args:  []any{`%100\%\_%`, int64(3), int64(7), 20},
+		},
+		{
+			name:  "tag without limit",
+			query: entity.ArticleQuery{Tag: "legs"},
+			sql:   articleColumns + " WHERE " + tagCondition + " ORDER BY w.id ASC",
+			args:  []any{"legs"},
+		},
+		{
+			name:  "tag and category before the cursor",
+			query: entity.ArticleQuery{PageSize: 20, Sort: entity.ArticleSortHeaderDesc, Tag: "legs", CategoryID: 3},
+			after: after,
+			sql: articleColumns + " WHERE " + tagCondition + " AND w.category_id = ? AND (w.header < ? OR (w.header = ? AND w.id < ?))" +
+				" ORDER BY w.header DESC, w.id DESC LIMIT ?",
+			args: []any{"legs", int64(3), "Squats", "Squats", int64(7), 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := newArticlePage(tt.query, tt.after).build()
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "squat", escapeLike("squat"))
+	assert.Equal(t, `50\% off\_now \\ later`, escapeLike(`50% off_now \ later`))
+}
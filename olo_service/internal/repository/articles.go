@@ -3,15 +3,20 @@ package repository
 import (
 	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/jmoiron/sqlx"
 )
 
 type ArticleRepo struct {
 	mysqlProvider *provider.MySQLProvider
 }
 
-func (r *ArticleRepo) GetUsersArticles(userId int64) ([]entity.Article, error) {
-	return r.getArticles(fmt.Sprintf("SELECT w.id as id, w.header as `header` FROM articles as w, user_has_articles as u WHERE w.id = u.id_articles AND u.id_user = '%d'", userId))
+func (r *ArticleRepo) GetUsersArticles(userId int64, query entity.ArticleQuery, after *entity.ArticleCursor) ([]entity.Article, error) {
+	page := newArticlePage(query, after)
+	page.where("w.id IN (SELECT u.id_articles FROM user_has_articles as u WHERE u.id_user = ?)", userId)
+	return r.getArticles(page.build())
 }
 
 func (r *ArticleRepo) AddArticleForUser(articleId, userId int64) error {
@@ -47,17 +52,17 @@ func NewArticleRepo(mysqlProvider *provider.MySQLProvider) *ArticleRepo {
 	return &ArticleRepo{mysqlProvider: mysqlProvider}
 }
 
-func (r *ArticleRepo) GetAllArticles() ([]entity.Article, error) {
-	return r.getArticles(`SELECT * FROM articles`)
+func (r *ArticleRepo) GetAllArticles(query entity.ArticleQuery, after *entity.ArticleCursor) ([]entity.Article, error) {
+	return r.getArticles(newArticlePage(query, after).build())
 }
 
-func (r *ArticleRepo) getArticles(articleQuery string) ([]entity.Article, error) {
+func (r *ArticleRepo) getArticles(articleQuery string, args []any) ([]entity.Article, error) {
 	driver, err := r.mysqlProvider.Driver()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := driver.Queryx(articleQuery)
+	rows, err := driver.Queryx(articleQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -71,5 +76,164 @@ func (r *ArticleRepo) getArticles(articleQuery string) ([]entity.Article, error)
 		}
 		articles = append(articles, article)
 	}
-	return articles, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return articles, r.loadTags(articles)
+}
+
+// loadTags fills the tags of the articles.
+func (r *ArticleRepo) loadTags(articles []entity.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	index := make(map[int64]int, len(articles))
+	ids := make([]int64, len(articles))
+	for i, article := range articles {
+		index[article.ID] = i
+		ids[i] = article.ID
+	}
+
+	query, args, err := sqlx.In("SELECT at.id_articles, t.name FROM article_has_tags AS at JOIN tags AS t ON t.id = at.id_tag WHERE at.id_articles IN (?) ORDER BY t.name", ids)
+	if err != nil {
+		return err
+	}
+	rows, err := driver.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("error get tags of articles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleId int64
+		var tag string
+		if err := rows.Scan(&articleId, &tag); err != nil {
+			return err
+		}
+		i := index[articleId]
+		articles[i].Tags = append(articles[i].Tags, tag)
+	}
+	return rows.Err()
+}
+
+// setTags replaces the tags of the article, creating the tags that don't exist yet.
+func setTags(tx *sqlx.Tx, articleId int64, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM `article_has_tags` WHERE `id_articles` = ?", articleId); err != nil {
+		return fmt.Errorf("error delete tags of article: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec("INSERT IGNORE INTO `tags` (`name`) VALUES (?)", tag); err != nil {
+			return fmt.Errorf("error create tag: %w", err)
+		}
+		_, err := tx.Exec("INSERT INTO `article_has_tags` (`id_articles`, `id_tag`) SELECT ?, `id` FROM `tags` WHERE `name` = ?", articleId, tag)
+		if err != nil {
+			return fmt.Errorf("error add tag to article: %w", err)
+		}
+	}
+	return nil
+}
+
+// nullCategory returns the value of the category_id column, which is NULL for no category.
+func nullCategory(categoryId int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: categoryId, Valid: categoryId != 0}
+}
+
+func (r *ArticleRepo) GetArticle(articleId int64) (entity.Article, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Article{}, err
+	}
+
+	var article entity.Article
+	err = driver.Get(&article, "SELECT `id`, `header`, COALESCE(`body`, '') AS `body`, COALESCE(`category_id`, 0) AS `category_id` FROM `articles` WHERE `id` = ?", articleId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Article{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Article{}, err
+	}
+
+	articles := []entity.Article{article}
+	if err := r.loadTags(articles); err != nil {
+		return entity.Article{}, err
+	}
+	return articles[0], nil
+}
+
+func (r *ArticleRepo) CreateArticle(article entity.Article) (int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return 0, err
+	}
+	tx, err := driver.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("INSERT INTO `articles` (`header`, `body`, `category_id`) VALUES (?, ?, ?)",
+		article.Header, article.Body, nullCategory(article.CategoryID))
+	if err != nil {
+		return 0, fmt.Errorf("error create article: %w", err)
+	}
+	articleId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := setTags(tx, articleId, article.Tags); err != nil {
+		return 0, err
+	}
+	return articleId, tx.Commit()
+}
+
+func (r *ArticleRepo) UpdateArticle(article entity.Article) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	// The existence is checked separately, since MySQL doesn't count rows updated with the same values
+	if _, err := r.GetArticle(article.ID); err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE `articles` SET `header` = ?, `body` = ?, `category_id` = ? WHERE `id` = ?",
+		article.Header, article.Body, nullCategory(article.CategoryID), article.ID)
+	if err != nil {
+		return fmt.Errorf("error update article: %w", err)
+	}
+	if err := setTags(tx, article.ID, article.Tags); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ArticleRepo) DeleteArticle(articleId int64) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+	res, err := driver.Exec("DELETE FROM `articles` WHERE `id` = ?", articleId)
+	if err != nil {
+		return fmt.Errorf("error delete article: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
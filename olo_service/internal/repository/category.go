@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+)
+
+type CategoryRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewCategoryRepo(mysqlProvider *provider.MySQLProvider) *CategoryRepo {
+	return &CategoryRepo{mysqlProvider: mysqlProvider}
+}
+
+func (r *CategoryRepo) ListCategories() ([]entity.Category, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []entity.Category
+	err = driver.Select(&categories, "SELECT `id`, `slug`, `name` FROM `categories` ORDER BY `name`")
+	return categories, err
+}
+
+func (r *CategoryRepo) GetCategory(categoryId int64) (entity.Category, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Category{}, err
+	}
+
+	var category entity.Category
+	err = driver.Get(&category, "SELECT `id`, `slug`, `name` FROM `categories` WHERE `id` = ?", categoryId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Category{}, ErrNotFound
+	}
+	return category, err
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type GoalRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewGoalRepo(mysqlProvider *provider.MySQLProvider) *GoalRepo {
+	return &GoalRepo{mysqlProvider: mysqlProvider}
+}
+
+// goalColumns are the columns of an entity.Goal.
+const goalColumns = "`id`, `id_user`, `title`, `kind`, `metric_type`, `comparison`, `target`, `start_value`, `deadline`, " +
+	"`utc_offset`, `created_at`, `status`, `current_value`, `progress`, `current_streak`, `longest_streak`, `completed_at`, `evaluated_at`"
+
+func (r *GoalRepo) CreateGoal(goal entity.Goal) (int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := driver.NamedExec("INSERT INTO `goals` (`id_user`, `title`, `kind`, `metric_type`, `comparison`, `target`, "+
+		"`start_value`, `deadline`, `utc_offset`, `created_at`, `status`) VALUES (:id_user, :title, :kind, :metric_type, "+
+		":comparison, :target, :start_value, :deadline, :utc_offset, :created_at, :status)", goal)
+	if err != nil {
+		return 0, fmt.Errorf("error create goal: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func (r *GoalRepo) GetGoal(userId, goalId int64) (entity.Goal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Goal{}, err
+	}
+
+	var goal entity.Goal
+	err = driver.Get(&goal, "SELECT "+goalColumns+" FROM `goals` WHERE `id` = ? AND `id_user` = ?", goalId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Goal{}, ErrNotFound
+	}
+	return goal, err
+}
+
+// ListGoals returns the goals of the user, only with the status if it is set, the newest first.
+func (r *GoalRepo) ListGoals(userId int64, status string) ([]entity.Goal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + goalColumns + " FROM `goals` WHERE `id_user` = ?"
+	args := []any{userId}
+	if status != "" {
+		query += " AND `status` = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY `id` DESC"
+
+	var goals []entity.Goal
+	if err := driver.Select(&goals, query, args...); err != nil {
+		return nil, fmt.Errorf("error get goals: %w", err)
+	}
+	return goals, nil
+}
+
+// ListActiveGoals returns at most limit active goals of all users with ids greater than afterId.
+func (r *GoalRepo) ListActiveGoals(afterId int64, limit int) ([]entity.Goal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var goals []entity.Goal
+	err = driver.Select(&goals, "SELECT "+goalColumns+" FROM `goals` WHERE `status` = 'active' AND `id` > ? ORDER BY `id` LIMIT ?",
+		afterId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error get active goals: %w", err)
+	}
+	return goals, nil
+}
+
+// SaveGoalEvaluation saves the result of the evaluation of the goal.
+func (r *GoalRepo) SaveGoalEvaluation(goal entity.Goal) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	_, err = driver.NamedExec("UPDATE `goals` SET `status` = :status, `current_value` = :current_value, `progress` = :progress, "+
+		"`current_streak` = :current_streak, `longest_streak` = :longest_streak, `completed_at` = :completed_at, "+
+		"`evaluated_at` = :evaluated_at WHERE `id` = :id", goal)
+	if err != nil {
+		return fmt.Errorf("error save goal evaluation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type MetricRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewMetricRepo(mysqlProvider *provider.MySQLProvider) *MetricRepo {
+	return &MetricRepo{mysqlProvider: mysqlProvider}
+}
+
+// RecordMetrics saves the metrics of the user with one statement.
+func (r *MetricRepo) RecordMetrics(userId int64, metrics []entity.Metric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	values := make([]string, len(metrics))
+	args := make([]any, 0, len(metrics)*6)
+	for i, metric := range metrics {
+		values[i] = "(?, ?, ?, ?, ?, ?)"
+		args = append(args, userId, metric.Type, metric.RecordedAt, metric.Value, metric.Unit, metric.Source)
+	}
+
+	_, err = driver.Exec("INSERT INTO `metrics` (`id_user`, `type`, `recorded_at`, `value`, `unit`, `source`) VALUES "+
+		strings.Join(values, ", "), args...)
+	if err != nil {
+		return fmt.Errorf("error record metrics: %w", err)
+	}
+	return nil
+}
+
+// QueryMetrics returns the metric readings of the user aggregated into buckets of the given size in seconds.
+// Buckets are aligned to the anchor, which is a unix time a bucket starts at.
+func (r *MetricRepo) QueryMetrics(userId int64, query entity.MetricQuery, size, anchor int64) ([]entity.MetricBucket, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var buckets []entity.MetricBucket
+	err = driver.Select(&buckets, "SELECT FLOOR((`recorded_at` - ?) / ?) * ? + ? AS `bucket_start`, "+
+		"COUNT(*) AS `count`, AVG(`value`) AS `avg`, MIN(`value`) AS `min`, MAX(`value`) AS `max`, SUM(`value`) AS `sum` "+
+		"FROM `metrics` WHERE `id_user` = ? AND `type` = ? AND `recorded_at` >= ? AND `recorded_at` < ? "+
+		"GROUP BY `bucket_start` ORDER BY `bucket_start`",
+		anchor, size, size, anchor, userId, query.Type, query.From, query.To)
+	if err != nil {
+		return nil, fmt.Errorf("error query metrics: %w", err)
+	}
+	return buckets, nil
+}
+
+func (r *MetricRepo) GetLatestMetric(userId int64, metricType string) (entity.Metric, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Metric{}, err
+	}
+
+	var metric entity.Metric
+	err = driver.Get(&metric, "SELECT `id`, `type`, `recorded_at`, `value`, `unit`, `source` FROM `metrics` "+
+		"WHERE `id_user` = ? AND `type` = ? ORDER BY `recorded_at` DESC, `id` DESC LIMIT 1", userId, metricType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Metric{}, ErrNotFound
+	}
+	return metric, err
+}
+
+// ListMetrics returns the metric readings of the user with ids greater than afterId in the order of ids.
+func (r *MetricRepo) ListMetrics(userId, afterId int64, limit int) ([]entity.Metric, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []entity.Metric
+	err = driver.Select(&metrics, "SELECT `id`, `type`, `recorded_at`, `value`, `unit`, `source` FROM `metrics` "+
+		"WHERE `id_user` = ? AND `id` > ? ORDER BY `id` LIMIT ?", userId, afterId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error list metrics: %w", err)
+	}
+	return metrics, nil
+}
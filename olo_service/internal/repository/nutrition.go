@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type NutritionRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewNutritionRepo(mysqlProvider *provider.MySQLProvider) *NutritionRepo {
+	return &NutritionRepo{mysqlProvider: mysqlProvider}
+}
+
+// SearchFoods returns at most limit foods whose name contains the query, names starting with it first.
+func (r *NutritionRepo) SearchFoods(query string, limit int) ([]entity.Food, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := escapeLike(query)
+	var foods []entity.Food
+	err = driver.Select(&foods, "SELECT `id`, `name`, `calories`, `protein`, `fat`, `carbs` FROM `foods` "+
+		"WHERE `name` LIKE CONCAT('%', ?, '%') ORDER BY `name` LIKE CONCAT(?, '%') DESC, `name` LIMIT ?",
+		pattern, pattern, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error search foods: %w", err)
+	}
+	return foods, nil
+}
+
+func (r *NutritionRepo) GetFood(foodId int64) (entity.Food, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Food{}, err
+	}
+
+	var food entity.Food
+	err = driver.Get(&food, "SELECT `id`, `name`, `calories`, `protein`, `fat`, `carbs` FROM `foods` WHERE `id` = ?", foodId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Food{}, ErrNotFound
+	}
+	return food, err
+}
+
+func (r *NutritionRepo) AddMeal(userId int64, meal entity.Meal) (int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := driver.Exec("INSERT INTO `meals` (`id_user`, `date`, `meal_type`, `id_food`, `grams`, `created_at`) VALUES (?, ?, ?, ?, ?, ?)",
+		userId, meal.Date, meal.MealType, meal.FoodID, meal.Grams, meal.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("error add meal: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func (r *NutritionRepo) DeleteMeal(userId, mealId int64) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	res, err := driver.Exec("DELETE FROM `meals` WHERE `id` = ? AND `id_user` = ?", mealId, userId)
+	if err != nil {
+		return fmt.Errorf("error delete meal: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// mealColumns are the columns of an entity.Meal, the nutrients are of the portion.
+const mealColumns = "m.`id`, DATE_FORMAT(m.`date`, '%Y-%m-%d') AS `date`, m.`meal_type`, m.`id_food`, f.`name` AS `food_name`, " +
+	"m.`grams`, m.`created_at`, f.`calories` * m.`grams` / 100 AS `calories`, f.`protein` * m.`grams` / 100 AS `protein`, " +
+	"f.`fat` * m.`grams` / 100 AS `fat`, f.`carbs` * m.`grams` / 100 AS `carbs`"
+
+func (r *NutritionRepo) GetMeal(userId, mealId int64) (entity.Meal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Meal{}, err
+	}
+
+	var meal entity.Meal
+	err = driver.Get(&meal, "SELECT "+mealColumns+" FROM `meals` m JOIN `foods` f ON f.`id` = m.`id_food` "+
+		"WHERE m.`id` = ? AND m.`id_user` = ?", mealId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Meal{}, ErrNotFound
+	}
+	return meal, err
+}
+
+// GetMeals returns the meals of the user on the date in the order they were logged.
+func (r *NutritionRepo) GetMeals(userId int64, date string) ([]entity.Meal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var meals []entity.Meal
+	err = driver.Select(&meals, "SELECT "+mealColumns+" FROM `meals` m JOIN `foods` f ON f.`id` = m.`id_food` "+
+		"WHERE m.`id_user` = ? AND m.`date` = ? ORDER BY m.`created_at`, m.`id`", userId, date)
+	if err != nil {
+		return nil, fmt.Errorf("error get meals: %w", err)
+	}
+	return meals, nil
+}
+
+// ListMeals returns the meals of the user with ids greater than afterId in the order of ids.
+func (r *NutritionRepo) ListMeals(userId, afterId int64, limit int) ([]entity.Meal, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var meals []entity.Meal
+	err = driver.Select(&meals, "SELECT "+mealColumns+" FROM `meals` m JOIN `foods` f ON f.`id` = m.`id_food` "+
+		"WHERE m.`id_user` = ? AND m.`id` > ? ORDER BY m.`id` LIMIT ?", userId, afterId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error list meals: %w", err)
+	}
+	return meals, nil
+}
+
+// GetNutritionTotals returns the nutrients the user ate on each day from the first to the last date inclusive.
+// Days without meals are skipped.
+func (r *NutritionRepo) GetNutritionTotals(userId int64, first, last string) ([]entity.DayNutrients, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	var days []entity.DayNutrients
+	err = driver.Select(&days, "SELECT DATE_FORMAT(m.`date`, '%Y-%m-%d') AS `date`, "+
+		"SUM(f.`calories` * m.`grams` / 100) AS `calories`, SUM(f.`protein` * m.`grams` / 100) AS `protein`, "+
+		"SUM(f.`fat` * m.`grams` / 100) AS `fat`, SUM(f.`carbs` * m.`grams` / 100) AS `carbs` "+
+		"FROM `meals` m JOIN `foods` f ON f.`id` = m.`id_food` "+
+		"WHERE m.`id_user` = ? AND m.`date` BETWEEN ? AND ? GROUP BY m.`date` ORDER BY m.`date`", userId, first, last)
+	if err != nil {
+		return nil, fmt.Errorf("error get nutrition totals: %w", err)
+	}
+	return days, nil
+}
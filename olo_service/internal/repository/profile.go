@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type ProfileRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewProfileRepo(mysqlProvider *provider.MySQLProvider) *ProfileRepo {
+	return &ProfileRepo{mysqlProvider: mysqlProvider}
+}
+
+func (r *ProfileRepo) GetProfile(userId int64) (entity.Profile, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Profile{}, err
+	}
+
+	var profile entity.Profile
+	err = driver.Get(&profile, "SELECT `height_cm`, DATE_FORMAT(`birth_date`, '%Y-%m-%d') AS `birth_date`, `sex`, "+
+		"`activity_level`, `units`, `goal_weight_kg`, `updated_at` FROM `user_profiles` WHERE `id_user` = ?", userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Profile{}, ErrNotFound
+	}
+	return profile, err
+}
+
+// SaveProfile creates the profile of the user or replaces it.
+func (r *ProfileRepo) SaveProfile(userId int64, profile entity.Profile) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	_, err = driver.Exec("INSERT INTO `user_profiles` (`id_user`, `height_cm`, `birth_date`, `sex`, `activity_level`, `units`, `goal_weight_kg`, `updated_at`) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `height_cm` = VALUES(`height_cm`), `birth_date` = VALUES(`birth_date`), "+
+		"`sex` = VALUES(`sex`), `activity_level` = VALUES(`activity_level`), `units` = VALUES(`units`), "+
+		"`goal_weight_kg` = VALUES(`goal_weight_kg`), `updated_at` = VALUES(`updated_at`)",
+		userId, profile.HeightCm, profile.BirthDate, profile.Sex, profile.ActivityLevel, profile.Units, profile.GoalWeightKg, profile.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error save profile: %w", err)
+	}
+	return nil
+}
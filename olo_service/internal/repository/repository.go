@@ -7,34 +7,137 @@ package repository
 import (
 	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/repository/provider"
+	"errors"
+	"time"
 )
 
+// ErrNotFound indicates that the requested entity doesn't exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrAlreadyExists indicates that an entity with the same unique key already exists.
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrLayoutMismatch indicates that a dashboard layout doesn't list every widget of the user exactly once.
+var ErrLayoutMismatch = errors.New("layout doesn't match the widgets of the user")
+
 // Widget represents the interface for interacting with widget data.
 type Widget interface {
 	GetWidgets(userId int64) ([]entity.Widget, error)
-	AddWidget(data string, userId int64) (int64, error)
-	UpdateWidget(data string, widgetId, userId int64) error
+	GetWidget(widgetId, userId int64) (entity.Widget, error)
+	AddWidget(widget entity.Widget, userId int64) (int64, error)
+	UpdateWidget(widget entity.Widget, userId int64) error
 	DeleteWidget(widgetId int64, userId int64) error
+	ReorderWidgets(userId int64, widgets []entity.Widget) error
+	GetWidgetRevisions(widgetId, userId int64, beforeVersion, limit int) ([]entity.WidgetRevision, error)
+	GetWidgetRevision(widgetId, userId int64, version int) (entity.WidgetRevision, error)
+	PruneWidgetRevisions(widgetId, userId int64, keepVersions int, before time.Time) error
 }
 
 // Article represents the interface for interacting with article data.
 type Article interface {
 	AddArticleForUser(articleId, userId int64) error
-	GetAllArticles() ([]entity.Article, error)
-	GetUsersArticles(userid int64) ([]entity.Article, error)
+	GetAllArticles(query entity.ArticleQuery, after *entity.ArticleCursor) ([]entity.Article, error)
+	GetUsersArticles(userid int64, query entity.ArticleQuery, after *entity.ArticleCursor) ([]entity.Article, error)
 	DeleteArticleForUser(articleId int64, userId int64) error
+	GetArticle(articleId int64) (entity.Article, error)
+	CreateArticle(article entity.Article) (int64, error)
+	UpdateArticle(article entity.Article) error
+	DeleteArticle(articleId int64) error
+}
+
+// Category represents the interface for interacting with article categories.
+type Category interface {
+	ListCategories() ([]entity.Category, error)
+	GetCategory(categoryId int64) (entity.Category, error)
+}
+
+// Metric represents the interface for interacting with health metrics.
+type Metric interface {
+	RecordMetrics(userId int64, metrics []entity.Metric) error
+	QueryMetrics(userId int64, query entity.MetricQuery, size, anchor int64) ([]entity.MetricBucket, error)
+	GetLatestMetric(userId int64, metricType string) (entity.Metric, error)
+	ListMetrics(userId, afterId int64, limit int) ([]entity.Metric, error)
+}
+
+// Workout represents the interface for interacting with workouts and the exercise catalog.
+type Workout interface {
+	ListExercises(muscleGroup string) ([]entity.Exercise, error)
+	GetExercises(ids []int64) (map[int64]entity.Exercise, error)
+	CreateExercise(exercise entity.Exercise) (int64, error)
+	CreateWorkout(userId int64, workout entity.Workout) (int64, error)
+	UpdateWorkout(userId int64, workout entity.Workout) error
+	DeleteWorkout(userId, workoutId int64) error
+	GetWorkout(userId, workoutId int64) (entity.Workout, error)
+	ListWorkouts(userId int64, from, to int64, after *entity.WorkoutCursor, limit int) ([]entity.Workout, error)
+	GetLoggedSets(userId, exerciseId int64, from, to int64) ([]entity.LoggedSet, error)
+	GetBestSets(userId int64, exerciseIds []int64, before int64, excludeWorkoutId int64) ([]entity.WorkoutSet, error)
+}
+
+// Nutrition represents the interface for interacting with the food catalog and the meals of users.
+type Nutrition interface {
+	SearchFoods(query string, limit int) ([]entity.Food, error)
+	GetFood(foodId int64) (entity.Food, error)
+	AddMeal(userId int64, meal entity.Meal) (int64, error)
+	DeleteMeal(userId, mealId int64) error
+	GetMeal(userId, mealId int64) (entity.Meal, error)
+	GetMeals(userId int64, date string) ([]entity.Meal, error)
+	ListMeals(userId, afterId int64, limit int) ([]entity.Meal, error)
+	GetNutritionTotals(userId int64, first, last string) ([]entity.DayNutrients, error)
+}
+
+// Profile represents the interface for interacting with the fitness profiles of users.
+type Profile interface {
+	GetProfile(userId int64) (entity.Profile, error)
+	SaveProfile(userId int64, profile entity.Profile) error
+}
+
+// Goal represents the interface for interacting with the goals of users.
+type Goal interface {
+	CreateGoal(goal entity.Goal) (int64, error)
+	GetGoal(userId, goalId int64) (entity.Goal, error)
+	ListGoals(userId int64, status string) ([]entity.Goal, error)
+	ListActiveGoals(afterId int64, limit int) ([]entity.Goal, error)
+	SaveGoalEvaluation(goal entity.Goal) error
+}
+
+// Achievement represents the interface for interacting with the achievements of users.
+type Achievement interface {
+	CountActivity(userId int64, counters []string) (map[string]int64, error)
+	GetEarnedAchievements(userId int64) ([]entity.EarnedAchievement, error)
+	AwardAchievement(userId int64, achievementId string, earnedAt int64) (bool, error)
+}
+
+// Account represents the interface for deleting the data of deleted accounts.
+type Account interface {
+	PurgeUserData(userId int64) error
 }
 
-// Repository represents a unified interface for interacting with both widget and article data.
+// Repository represents a unified interface for interacting with the data of the OLO service.
 type Repository struct {
-	Widget  // Widget interface for widget-related operations
-	Article // Article interface for article-related operations
+	Widget      // Widget interface for widget-related operations
+	Article     // Article interface for article-related operations
+	Category    // Category interface for category-related operations
+	Metric      // Metric interface for metric-related operations
+	Workout     // Workout interface for workout-related operations
+	Nutrition   // Nutrition interface for nutrition-related operations
+	Profile     // Profile interface for profile-related operations
+	Goal        // Goal interface for goal-related operations
+	Achievement // Achievement interface for achievement-related operations
+	Account     // Account interface for account-related operations
 }
 
 // NewRepository creates a new instance of Repository with the provided MySQLProvider.
 func NewRepository(mysqlProvider *provider.MySQLProvider) *Repository {
 	return &Repository{
-		Widget:  NewWidgetRepo(mysqlProvider),
-		Article: NewArticleRepo(mysqlProvider),
+		Widget:      NewWidgetRepo(mysqlProvider),
+		Article:     NewArticleRepo(mysqlProvider),
+		Category:    NewCategoryRepo(mysqlProvider),
+		Metric:      NewMetricRepo(mysqlProvider),
+		Workout:     NewWorkoutRepo(mysqlProvider),
+		Nutrition:   NewNutritionRepo(mysqlProvider),
+		Profile:     NewProfileRepo(mysqlProvider),
+		Goal:        NewGoalRepo(mysqlProvider),
+		Achievement: NewAchievementRepo(mysqlProvider),
+		Account:     NewAccountRepo(mysqlProvider),
 	}
 }
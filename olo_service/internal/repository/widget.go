@@ -3,6 +3,8 @@ package repository
 import (
 	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
 	"fmt"
 )
 
@@ -14,17 +16,35 @@ func NewWidgetRepo(mysqlProvider *provider.MySQLProvider) *WidgetRepo {
 	return &WidgetRepo{mysqlProvider: mysqlProvider}
 }
 
+// widgetColumns are the columns of an entity.Widget.
+const widgetColumns = "id, type, data, version, position, grid_x, grid_y, width, height, visible"
+
+// GetWidgets returns the widgets of the user in the layout order.
 func (r *WidgetRepo) GetWidgets(userId int64) ([]entity.Widget, error) {
-	return r.getWidgets(fmt.Sprintf("SELECT id, data FROM widgetsUser WHERE id_user = %d", userId))
+	return r.getWidgets("SELECT "+widgetColumns+" FROM widgetsUser WHERE id_user = ? ORDER BY position, id", userId)
+}
+
+func (r *WidgetRepo) GetWidget(widgetId, userId int64) (entity.Widget, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Widget{}, err
+	}
+
+	var widget entity.Widget
+	err = driver.Get(&widget, "SELECT "+widgetColumns+" FROM widgetsUser WHERE id = ? AND id_user = ?", widgetId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Widget{}, ErrNotFound
+	}
+	return widget, err
 }
 
-func (r *WidgetRepo) getWidgets(widgetQuery string) ([]entity.Widget, error) {
+func (r *WidgetRepo) getWidgets(widgetQuery string, args ...any) ([]entity.Widget, error) {
 	driver, err := r.mysqlProvider.Driver()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := driver.Queryx(widgetQuery)
+	rows, err := driver.Queryx(widgetQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -41,34 +61,76 @@ func (r *WidgetRepo) getWidgets(widgetQuery string) ([]entity.Widget, error) {
 	return widgets, nil
 }
 
-func (r *WidgetRepo) UpdateWidget(data string, widgetId, userId int64) error {
+// UpdateWidget updates the data of the widget and saves it as a new revision.
+func (r *WidgetRepo) UpdateWidget(widget entity.Widget, userId int64) error {
 	driver, err := r.mysqlProvider.Driver()
 	if err != nil {
 		return err
 	}
 
-	_, err = driver.NamedExec("UPDATE `widgetsUser` SET `data`=:data WHERE `id`=:id_widget AND `id_user`=:id_user", map[string]interface{}{
-		"data":      data,
-		"id_widget": widgetId,
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var version int
+	err = tx.Get(&version, "SELECT `version` FROM `widgetsUser` WHERE `id` = ? AND `id_user` = ? FOR UPDATE", widget.ID, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	version++
+
+	_, err = tx.NamedExec("UPDATE `widgetsUser` SET `type`=:type, `data`=:data, `version`=:version WHERE `id`=:id_widget AND `id_user`=:id_user", map[string]interface{}{
+		"type":      widget.Type,
+		"data":      widget.Data,
+		"version":   version,
+		"id_widget": widget.ID,
 		"id_user":   userId,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	if err := addRevision(tx, widget.ID, userId, version, widget); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *WidgetRepo) AddWidget(data string, userId int64) (int64, error) {
+// AddWidget adds the widget and saves its data as the first revision.
+func (r *WidgetRepo) AddWidget(widget entity.Widget, userId int64) (int64, error) {
 	driver, err := r.mysqlProvider.Driver()
 	if err != nil {
 		return 0, err
 	}
-	res, err := driver.NamedExec("INSERT INTO `widgetsUser` (`data`, `id_user`) VALUES (:data, :id_user)", map[string]interface{}{
-		"data":    data,
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// A new widget is placed at the end of the dashboard
+	res, err := tx.NamedExec("INSERT INTO `widgetsUser` (`type`, `data`, `id_user`, `position`) "+
+		"SELECT :type, :data, :id_user, COALESCE(MAX(`position`), -1) + 1 FROM `widgetsUser` WHERE `id_user` = :id_user", map[string]interface{}{
+		"type":    widget.Type,
+		"data":    widget.Data,
 		"id_user": userId,
 	})
 	if err != nil {
 		return 0, err
 	}
 	id, err := res.LastInsertId()
-	return id, err
+	if err != nil {
+		return 0, err
+	}
+	if err := addRevision(tx, id, userId, 1, widget); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
 }
 
 func (r *WidgetRepo) DeleteWidget(widgetId int64, userId int64) error {
@@ -76,9 +138,72 @@ func (r *WidgetRepo) DeleteWidget(widgetId int64, userId int64) error {
 	if err != nil {
 		return err
 	}
-	_, err = driver.NamedExec("DELETE FROM `widgetsUser` WHERE `id` = :id_widget AND `id_user` = :id_user", map[string]interface{}{
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec("DELETE FROM `widgetsUser` WHERE `id` = :id_widget AND `id_user` = :id_user", map[string]interface{}{
 		"id_widget": widgetId,
 		"id_user":   userId,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM `widget_revisions` WHERE `id_widget` = ? AND `id_user` = ?", widgetId, userId)
+	if err != nil {
+		return fmt.Errorf("error delete widget revisions: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ReorderWidgets rewrites the layout of all widgets of the user in one transaction.
+// It returns ErrLayoutMismatch if the layouts don't list every widget of the user exactly once.
+func (r *WidgetRepo) ReorderWidgets(userId int64, widgets []entity.Widget) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var ids []int64
+	if err := tx.Select(&ids, "SELECT `id` FROM `widgetsUser` WHERE `id_user` = ? FOR UPDATE", userId); err != nil {
+		return fmt.Errorf("error lock widgets: %w", err)
+	}
+	if len(ids) != len(widgets) {
+		return ErrLayoutMismatch
+	}
+	owned := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		owned[id] = true
+	}
+
+	for _, widget := range widgets {
+		if !owned[widget.ID] {
+			return ErrLayoutMismatch
+		}
+		delete(owned, widget.ID)
+
+		_, err := tx.NamedExec("UPDATE `widgetsUser` SET `position`=:position, `grid_x`=:grid_x, `grid_y`=:grid_y, "+
+			"`width`=:width, `height`=:height, `visible`=:visible WHERE `id`=:id_widget AND `id_user`=:id_user", map[string]interface{}{
+			"position":  widget.Position,
+			"grid_x":    widget.X,
+			"grid_y":    widget.Y,
+			"width":     widget.Width,
+			"height":    widget.Height,
+			"visible":   widget.Visible,
+			"id_widget": widget.ID,
+			"id_user":   userId,
+		})
+		if err != nil {
+			return fmt.Errorf("error update widget layout: %w", err)
+		}
+	}
+	return tx.Commit()
 }
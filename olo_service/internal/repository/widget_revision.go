@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+// revisionColumns are the columns of an entity.WidgetRevision.
+const revisionColumns = "id_widget, version, type, data, UNIX_TIMESTAMP(created_at) AS created_at"
+
+// addRevision saves the data of the widget as the revision of the version.
+func addRevision(tx *sqlx.Tx, widgetId, userId int64, version int, widget entity.Widget) error {
+	_, err := tx.Exec("INSERT INTO `widget_revisions` (`id_widget`, `id_user`, `version`, `type`, `data`) VALUES (?, ?, ?, ?, ?)",
+		widgetId, userId, version, widget.Type, widget.Data)
+	if err != nil {
+		return fmt.Errorf("error add widget revision: %w", err)
+	}
+	return nil
+}
+
+// GetWidgetRevisions returns at most limit revisions of the widget older than the version, newest first.
+// A zero beforeVersion means starting from the newest revision.
+func (r *WidgetRepo) GetWidgetRevisions(widgetId, userId int64, beforeVersion, limit int) ([]entity.WidgetRevision, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + revisionColumns + " FROM `widget_revisions` WHERE `id_widget` = ? AND `id_user` = ?"
+	args := []any{widgetId, userId}
+	if beforeVersion > 0 {
+		query += " AND `version` < ?"
+		args = append(args, beforeVersion)
+	}
+	query += " ORDER BY `version` DESC LIMIT ?"
+	args = append(args, limit)
+
+	var revisions []entity.WidgetRevision
+	if err := driver.Select(&revisions, query, args...); err != nil {
+		return nil, fmt.Errorf("error get widget revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *WidgetRepo) GetWidgetRevision(widgetId, userId int64, version int) (entity.WidgetRevision, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.WidgetRevision{}, err
+	}
+
+	var revision entity.WidgetRevision
+	err = driver.Get(&revision, "SELECT "+revisionColumns+" FROM `widget_revisions` WHERE `id_widget` = ? AND `id_user` = ? AND `version` = ?",
+		widgetId, userId, version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.WidgetRevision{}, ErrNotFound
+	}
+	return revision, err
+}
+
+// PruneWidgetRevisions deletes the revisions of the widget beyond the newest keepVersions
+// and the revisions created before the time. The current version is always kept.
+func (r *WidgetRepo) PruneWidgetRevisions(widgetId, userId int64, keepVersions int, before time.Time) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	_, err = driver.Exec("DELETE rv FROM `widget_revisions` AS rv JOIN `widgetsUser` AS w ON w.id = rv.id_widget AND w.id_user = rv.id_user "+
+		"WHERE rv.id_widget = ? AND rv.id_user = ? AND rv.version < w.version AND (rv.version <= w.version - ? OR rv.created_at < FROM_UNIXTIME(?))",
+		widgetId, userId, keepVersions, before.Unix())
+	if err != nil {
+		return fmt.Errorf("error prune widget revisions: %w", err)
+	}
+	return nil
+}
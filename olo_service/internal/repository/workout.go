@@ -0,0 +1,269 @@
+package repository
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+)
+
+// mysqlDuplicateEntry is the MySQL error number of a unique key violation.
+const mysqlDuplicateEntry = 1062
+
+type WorkoutRepo struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+func NewWorkoutRepo(mysqlProvider *provider.MySQLProvider) *WorkoutRepo {
+	return &WorkoutRepo{mysqlProvider: mysqlProvider}
+}
+
+// ListExercises returns the exercises of the catalog, only of the muscle group if it is set.
+func (r *WorkoutRepo) ListExercises(muscleGroup string) ([]entity.Exercise, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT `id`, `name`, `muscle_group`, `kind` FROM `exercises`"
+	var args []any
+	if muscleGroup != "" {
+		query += " WHERE `muscle_group` = ?"
+		args = append(args, muscleGroup)
+	}
+	query += " ORDER BY `name`"
+
+	var exercises []entity.Exercise
+	if err := driver.Select(&exercises, query, args...); err != nil {
+		return nil, fmt.Errorf("error get exercises: %w", err)
+	}
+	return exercises, nil
+}
+
+// GetExercises returns the exercises with the ids, missing ones are skipped.
+func (r *WorkoutRepo) GetExercises(ids []int64) (map[int64]entity.Exercise, error) {
+	exercises := make(map[int64]entity.Exercise, len(ids))
+	if len(ids) == 0 {
+		return exercises, nil
+	}
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sqlx.In("SELECT `id`, `name`, `muscle_group`, `kind` FROM `exercises` WHERE `id` IN (?)", ids)
+	if err != nil {
+		return nil, err
+	}
+	var list []entity.Exercise
+	if err := driver.Select(&list, query, args...); err != nil {
+		return nil, fmt.Errorf("error get exercises: %w", err)
+	}
+	for _, exercise := range list {
+		exercises[exercise.ID] = exercise
+	}
+	return exercises, nil
+}
+
+func (r *WorkoutRepo) CreateExercise(exercise entity.Exercise) (int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := driver.NamedExec("INSERT INTO `exercises` (`name`, `muscle_group`, `kind`) VALUES (:name, :muscle_group, :kind)", exercise)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+			return 0, ErrAlreadyExists
+		}
+		return 0, fmt.Errorf("error create exercise: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// CreateWorkout saves the workout of the user with its sets.
+func (r *WorkoutRepo) CreateWorkout(userId int64, workout entity.Workout) (int64, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec("INSERT INTO `workouts` (`id_user`, `name`, `started_at`, `finished_at`, `notes`) VALUES (?, ?, ?, ?, ?)",
+		userId, workout.Name, workout.StartedAt, workout.FinishedAt, workout.Notes)
+	if err != nil {
+		return 0, fmt.Errorf("error create workout: %w", err)
+	}
+	workoutId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := insertSets(tx, workoutId, workout.Sets); err != nil {
+		return 0, err
+	}
+	return workoutId, tx.Commit()
+}
+
+// UpdateWorkout updates the workout of the user and replaces its sets.
+func (r *WorkoutRepo) UpdateWorkout(userId int64, workout entity.Workout) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	tx, err := driver.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.Get(&id, "SELECT `id` FROM `workouts` WHERE `id` = ? AND `id_user` = ? FOR UPDATE", workout.ID, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE `workouts` SET `name` = ?, `started_at` = ?, `finished_at` = ?, `notes` = ? WHERE `id` = ?",
+		workout.Name, workout.StartedAt, workout.FinishedAt, workout.Notes, workout.ID)
+	if err != nil {
+		return fmt.Errorf("error update workout: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM `workout_sets` WHERE `id_workout` = ?", workout.ID); err != nil {
+		return fmt.Errorf("error delete workout sets: %w", err)
+	}
+	if err := insertSets(tx, workout.ID, workout.Sets); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertSets saves the sets of the workout in their order.
+func insertSets(tx *sqlx.Tx, workoutId int64, sets []entity.WorkoutSet) error {
+	for i, set := range sets {
+		_, err := tx.Exec("INSERT INTO `workout_sets` (`id_workout`, `id_exercise`, `position`, `reps`, `weight_kg`, `duration_sec`, `distance_m`) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?)",
+			workoutId, set.ExerciseID, i, set.Reps, set.WeightKg, set.DurationSec, set.DistanceM)
+		if err != nil {
+			return fmt.Errorf("error add workout set: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *WorkoutRepo) DeleteWorkout(userId, workoutId int64) error {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return err
+	}
+
+	res, err := driver.Exec("DELETE FROM `workouts` WHERE `id` = ? AND `id_user` = ?", workoutId, userId)
+	if err != nil {
+		return fmt.Errorf("error delete workout: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// workoutColumns are the columns of an entity.Workout.
+const workoutColumns = "`id`, `name`, `started_at`, `finished_at`, `notes`"
+
+func (r *WorkoutRepo) GetWorkout(userId, workoutId int64) (entity.Workout, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return entity.Workout{}, err
+	}
+
+	var workout entity.Workout
+	err = driver.Get(&workout, "SELECT "+workoutColumns+" FROM `workouts` WHERE `id` = ? AND `id_user` = ?", workoutId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Workout{}, ErrNotFound
+	}
+	if err != nil {
+		return entity.Workout{}, err
+	}
+
+	workouts := []entity.Workout{workout}
+	if err := r.loadSets(driver, workouts); err != nil {
+		return entity.Workout{}, err
+	}
+	return workouts[0], nil
+}
+
+// ListWorkouts returns at most limit workouts of the user started in [from, to), the latest first.
+// The page starts after the cursor if it is set, zero from and to mean no bound.
+func (r *WorkoutRepo) ListWorkouts(userId int64, from, to int64, after *entity.WorkoutCursor, limit int) ([]entity.Workout, error) {
+	driver, err := r.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + workoutColumns + " FROM `workouts` WHERE `id_user` = ?"
+	args := []any{userId}
+	if from != 0 {
+		query += " AND `started_at` >= ?"
+		args = append(args, from)
+	}
+	if to != 0 {
+		query += " AND `started_at` < ?"
+		args = append(args, to)
+	}
+	if after != nil {
+		query += " AND (`started_at` < ? OR (`started_at` = ? AND `id` < ?))"
+		args = append(args, after.StartedAt, after.StartedAt, after.ID)
+	}
+	query += " ORDER BY `started_at` DESC, `id` DESC LIMIT ?"
+	args = append(args, limit)
+
+	var workouts []entity.Workout
+	if err := driver.Select(&workouts, query, args...); err != nil {
+		return nil, fmt.Errorf("error get workouts: %w", err)
+	}
+	return workouts, r.loadSets(driver, workouts)
+}
+
+// loadSets fills the sets of the workouts.
+func (r *WorkoutRepo) loadSets(driver *sqlx.DB, workouts []entity.Workout) error {
+	if len(workouts) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(workouts))
+	ids := make([]int64, len(workouts))
+	for i, workout := range workouts {
+		index[workout.ID] = i
+		ids[i] = workout.ID
+	}
+
+	query, args, err := sqlx.In("SELECT `id`, `id_workout`, `id_exercise`, `reps`, `weight_kg`, `duration_sec`, `distance_m` "+
+		"FROM `workout_sets` WHERE `id_workout` IN (?) ORDER BY `id_workout`, `position`", ids)
+	if err != nil {
+		return err
+	}
+	var sets []entity.WorkoutSet
+	if err := driver.Select(&sets, query, args...); err != nil {
+		return fmt.Errorf("error get workout sets: %w", err)
+	}
+	for _, set := range sets {
+		i := index[set.WorkoutID]
+		workouts[i].Sets = append(workouts[i].Sets, set)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package search
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"html"
+	"strings"
+)
+
+// Marks of the matched words in snippets.
+const (
+	highlightStart = "<b>"
+	highlightEnd   = "</b>"
+	ellipsis       = "…"
+)
+
+// bodySnippetLength is the maximum length of a body snippet in runes.
+const bodySnippetLength = 160
+
+// queryTerms returns the set of terms of the query.
+func queryTerms(query string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, t := range Analyze(query) {
+		terms[t] = true
+	}
+	return terms
+}
+
+// newHit creates a search hit of the article with highlighted snippets.
+func newHit(article entity.Article, score float64, terms map[string]bool) entity.ArticleSearchHit {
+	return entity.ArticleSearchHit{
+		Article:       article,
+		Score:         score,
+		HeaderSnippet: highlight(article.Header, terms, 0),
+		BodySnippet:   highlight(article.Body, terms, bodySnippetLength),
+	}
+}
+
+// highlight returns the HTML-escaped text with the words matching the terms wrapped in highlight marks.
+// If maxRunes is positive, the text is cut to a window of that length around the first match.
+func highlight(text string, terms map[string]bool, maxRunes int) string {
+	runes := []rune(text)
+	tokens := tokenize(text)
+
+	start, end := 0, len(runes)
+	if maxRunes > 0 && len(runes) > maxRunes {
+		start, end = snippetWindow(len(runes), tokens, terms, maxRunes)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(ellipsis)
+	}
+	pos := start
+	for _, t := range tokens {
+		if t.start < start || t.end > end {
+			continue
+		}
+		if t.term == "" || !terms[t.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:t.start])))
+		b.WriteString(highlightStart)
+		b.WriteString(html.EscapeString(string(runes[t.start:t.end])))
+		b.WriteString(highlightEnd)
+		pos = t.end
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString(ellipsis)
+	}
+	return b.String()
+}
+
+// snippetWindow returns the bounds of a window of at most maxRunes runes
+// starting a bit before the first match and not cutting words.
+func snippetWindow(length int, tokens []token, terms map[string]bool, maxRunes int) (int, int) {
+	start := 0
+	for _, t := range tokens {
+		if t.term != "" && terms[t.term] {
+			start = max(t.start-maxRunes/4, 0)
+			break
+		}
+	}
+	start = min(start, max(length-maxRunes, 0))
+
+	end := min(start+maxRunes, length)
+	for _, t := range tokens {
+		if start > 0 && t.start < start && t.end > start {
+			start = t.end
+		}
+		if end < length && t.start < end && t.end > end {
+			end = t.start
+		}
+	}
+	return start, end
+}
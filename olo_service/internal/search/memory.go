@@ -0,0 +1,122 @@
+package search
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"math"
+	"sort"
+	"sync"
+)
+
+// headerWeight is how much more a term in the header weighs than a term in the body.
+const headerWeight = 3
+
+// MemoryBackend is an in-process inverted index of articles.
+// The index is lost on restart, so it must be filled with IndexAll at startup.
+type MemoryBackend struct {
+	mu       sync.RWMutex
+	articles map[int64]entity.Article
+	postings map[string]map[int64]float64 // term -> article ID -> weighted term frequency
+	terms    map[int64][]string           // article ID -> terms of the article, used to remove it
+}
+
+// NewMemoryBackend creates a new empty instance of MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		articles: make(map[int64]entity.Article),
+		postings: make(map[string]map[int64]float64),
+		terms:    make(map[int64][]string),
+	}
+}
+
+// IndexAll adds the articles to the index.
+func (b *MemoryBackend) IndexAll(articles []entity.Article) error {
+	for _, article := range articles {
+		if err := b.Index(article); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBackend) Index(article entity.Article) error {
+	frequencies := make(map[string]float64)
+	for _, t := range Analyze(article.Header) {
+		frequencies[t] += headerWeight
+	}
+	for _, t := range Analyze(article.Body) {
+		frequencies[t]++
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(article.ID)
+	b.articles[article.ID] = article
+	terms := make([]string, 0, len(frequencies))
+	for t, frequency := range frequencies {
+		if b.postings[t] == nil {
+			b.postings[t] = make(map[int64]float64)
+		}
+		b.postings[t][article.ID] = frequency
+		terms = append(terms, t)
+	}
+	b.terms[article.ID] = terms
+	return nil
+}
+
+func (b *MemoryBackend) Remove(articleId int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(articleId)
+	return nil
+}
+
+// remove removes the article from the index, the caller must hold the lock.
+func (b *MemoryBackend) remove(articleId int64) {
+	for _, t := range b.terms[articleId] {
+		delete(b.postings[t], articleId)
+		if len(b.postings[t]) == 0 {
+			delete(b.postings, t)
+		}
+	}
+	delete(b.terms, articleId)
+	delete(b.articles, articleId)
+}
+
+// Search ranks the articles by TF-IDF of the query terms.
+func (b *MemoryBackend) Search(query string, limit int) ([]entity.ArticleSearchHit, error) {
+	terms := queryTerms(query)
+
+	b.mu.RLock()
+	scores := make(map[int64]float64)
+	for t := range terms {
+		postings := b.postings[t]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + float64(len(b.articles))/float64(len(postings)))
+		for articleId, frequency := range postings {
+			scores[articleId] += (1 + math.Log(frequency)) * idf
+		}
+	}
+	hits := make([]entity.ArticleSearchHit, 0, len(scores))
+	for articleId, score := range scores {
+		hits = append(hits, entity.ArticleSearchHit{Article: b.articles[articleId], Score: score})
+	}
+	b.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Article.ID < hits[j].Article.ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	for i, hit := range hits {
+		hits[i] = newHit(hit.Article, hit.Score, terms)
+	}
+	return hits, nil
+}
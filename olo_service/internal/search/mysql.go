@@ -0,0 +1,59 @@
+package search
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository/provider"
+	"fmt"
+)
+
+// MySQLBackend searches articles with the FULLTEXT index of the articles table.
+// MySQL keeps the index up to date itself, so Index and Remove do nothing.
+type MySQLBackend struct {
+	mysqlProvider *provider.MySQLProvider
+}
+
+// NewMySQLBackend creates a new instance of MySQLBackend.
+func NewMySQLBackend(mysqlProvider *provider.MySQLProvider) *MySQLBackend {
+	return &MySQLBackend{mysqlProvider: mysqlProvider}
+}
+
+func (b *MySQLBackend) Index(entity.Article) error {
+	return nil
+}
+
+func (b *MySQLBackend) Remove(int64) error {
+	return nil
+}
+
+const searchArticlesQuery = "SELECT id, header, COALESCE(body, '') AS body, COALESCE(category_id, 0) AS category_id, " +
+	"MATCH(header, body) AGAINST (? IN NATURAL LANGUAGE MODE) AS score " +
+	"FROM articles WHERE MATCH(header, body) AGAINST (? IN NATURAL LANGUAGE MODE) " +
+	"ORDER BY score DESC, id LIMIT ?"
+
+// Search ranks the articles by the relevance MySQL computes for the query.
+func (b *MySQLBackend) Search(query string, limit int) ([]entity.ArticleSearchHit, error) {
+	driver, err := b.mysqlProvider.Driver()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := driver.Queryx(searchArticlesQuery, query, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error search articles: %w", err)
+	}
+	defer rows.Close()
+
+	terms := queryTerms(query)
+	var hits []entity.ArticleSearchHit
+	for rows.Next() {
+		var row struct {
+			entity.Article
+			Score float64 `db:"score"`
+		}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, err
+		}
+		hits = append(hits, newHit(row.Article, row.Score, terms))
+	}
+	return hits, rows.Err()
+}
@@ -0,0 +1,103 @@
+// Package search provides full-text search over articles.
+//
+// The search is done by a pluggable Backend: MySQL FULLTEXT index for production
+// and an in-process inverted index for tests and small deployments. Both backends
+// share the analyzer of this package, which splits text into terms and stems
+// Russian and English words, so highlighted snippets look the same for either of them.
+package search
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"strings"
+	"unicode"
+)
+
+// Names of the backends in the service config.
+const (
+	BackendMySQL  = "mysql"
+	BackendMemory = "memory"
+)
+
+// Backend represents a full-text search index of articles.
+type Backend interface {
+	// Index adds the article to the index or replaces its indexed content.
+	Index(article entity.Article) error
+	// Remove removes the article from the index.
+	Remove(articleId int64) error
+	// Search returns at most limit articles matching the query ordered by relevance.
+	Search(query string, limit int) ([]entity.ArticleSearchHit, error)
+}
+
+// token represents a word of a text with its position in runes.
+type token struct {
+	start, end int
+	term       string
+}
+
+// tokenize splits the text into words and stems them.
+// Stop words are returned too, with an empty term.
+func tokenize(text string) []token {
+	var tokens []token
+	runes := []rune(text)
+
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, token{start: start, end: i, term: term(string(runes[start:i]))})
+			start = -1
+		}
+	}
+	return tokens
+}
+
+// Analyze returns the stemmed terms of the text without stop words.
+func Analyze(text string) []string {
+	var terms []string
+	for _, t := range tokenize(text) {
+		if t.term != "" {
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// term normalizes and stems a word, it returns an empty string for stop words.
+func term(word string) string {
+	word = strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+	if stopWords[word] {
+		return ""
+	}
+	if isCyrillic(word) {
+		return stemRussian(word)
+	}
+	return stemEnglish(word)
+}
+
+// isCyrillic reports whether the word contains cyrillic letters.
+func isCyrillic(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// stopWords are frequent words that carry no meaning for the search.
+var stopWords = map[string]bool{
+	"и": true, "в": true, "во": true, "не": true, "что": true, "он": true, "на": true, "я": true,
+	"с": true, "со": true, "как": true, "а": true, "то": true, "все": true, "она": true, "так": true,
+	"его": true, "но": true, "да": true, "ты": true, "к": true, "у": true, "же": true, "вы": true,
+	"за": true, "бы": true, "по": true, "только": true, "ее": true, "мне": true, "было": true,
+	"вот": true, "от": true, "меня": true, "о": true, "из": true, "ему": true, "для": true,
+	"это": true, "этот": true, "или": true, "при": true, "до": true, "мы": true, "их": true,
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "that": true, "the": true, "this": true, "to": true, "was": true, "with": true,
+}
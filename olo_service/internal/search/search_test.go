@@ -0,0 +1,83 @@
+package search
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		terms []string
+	}{
+		{"russian word forms", "Тренировки тренировка тренировками", []string{"тренировк", "тренировк", "тренировк"}},
+		{"russian yo", "Ёжик ежики", []string{"ежик", "ежик"}},
+		{"english word forms", "Running runs RUN", []string{"run", "run", "run"}},
+		{"stop words", "Сон и the sleep", []string{"сон", "sleep"}},
+		{"punctuation", "вода,сон;  бег!", []string{"вод", "сон", "бег"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.terms, Analyze(tt.text))
+		})
+	}
+}
+
+func TestStemEnglish(t *testing.T) {
+	for word, stem := range map[string]string{
+		"caresses":   "caress",
+		"ponies":     "poni",
+		"hopping":    "hop",
+		"relational": "relat",
+		"happy":      "happi",
+		"adjustment": "adjust",
+	} {
+		assert.Equal(t, stem, stemEnglish(word), word)
+	}
+}
+
+func TestMemoryBackendSearch(t *testing.T) {
+	backend := NewMemoryBackend()
+	require.NoError(t, backend.IndexAll([]entity.Article{
+		{ID: 1, Header: "Здоровый сон", Body: "Сон важен для восстановления после каждой тренировки."},
+		{ID: 2, Header: "Тренировки для начинающих", Body: "Начинайте тренировку с разминки."},
+		{ID: 3, Header: "Питание", Body: "Healthy eating & <b>balanced</b> diet."},
+	}))
+
+	hits, err := backend.Search("тренировка", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 2)
+	assert.Equal(t, int64(2), hits[0].Article.ID, "a match in the header ranks higher")
+	assert.Equal(t, int64(1), hits[1].Article.ID)
+	assert.Equal(t, "<b>Тренировки</b> для начинающих", hits[0].HeaderSnippet)
+	assert.Equal(t, "Начинайте <b>тренировку</b> с разминки.", hits[0].BodySnippet)
+
+	hits, err = backend.Search("diet", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "Healthy eating &amp; &lt;b&gt;balanced&lt;/b&gt; <b>diet</b>.", hits[0].BodySnippet)
+
+	require.NoError(t, backend.Index(entity.Article{ID: 2, Header: "Бег", Body: "Утренний бег."}))
+	hits, err = backend.Search("тренировка", 10)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(1), hits[0].Article.ID)
+
+	require.NoError(t, backend.Remove(1))
+	hits, err = backend.Search("тренировка", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+}
+
+func TestHighlightWindow(t *testing.T) {
+	text := "Первое предложение без совпадений. Второе предложение тоже длинное и скучное. А здесь есть слово вода, которое ищут."
+	snippet := highlight(text, queryTerms("вода"), 40)
+
+	assert.Contains(t, snippet, "<b>вода</b>")
+	assert.True(t, len([]rune(snippet)) <= 40+2+len(highlightStart)+len(highlightEnd))
+	assert.Equal(t, ellipsis, string([]rune(snippet)[0]))
+}
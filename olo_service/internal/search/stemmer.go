@@ -0,0 +1,327 @@
+package search
+
+import "strings"
+
+// Suffix groups of the Snowball stemmer for Russian.
+// Suffixes of the "a" groups must be preceded by а or я.
+var (
+	ruPerfectiveGerundA = []string{"вшись", "вши", "в"}
+	ruPerfectiveGerund  = []string{"ившись", "ывшись", "ивши", "ывши", "ив", "ыв"}
+	ruAdjective         = []string{"ими", "ыми", "его", "ого", "ему", "ому", "ее", "ие", "ые", "ое", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"}
+	ruParticipleA       = []string{"ем", "нн", "вш", "ющ", "щ"}
+	ruParticiple        = []string{"ивш", "ывш", "ующ"}
+	ruReflexive         = []string{"ся", "сь"}
+	ruVerbA             = []string{"ете", "йте", "ешь", "нно", "ла", "на", "ли", "ем", "ло", "но", "ет", "ют", "ны", "ть", "й", "л", "н"}
+	ruVerb              = []string{"ейте", "уйте", "ила", "ыла", "ена", "ите", "или", "ыли", "ило", "ыло", "ено", "ует", "уют", "ены", "ить", "ыть", "ишь", "ей", "уй", "ил", "ыл", "им", "ым", "ен", "ят", "ит", "ыт", "ую", "ю"}
+	ruNoun              = []string{"иями", "ями", "ами", "ией", "иям", "ием", "иях", "ев", "ов", "ие", "ье", "еи", "ии", "ей", "ой", "ий", "ям", "ем", "ам", "ом", "ах", "ях", "ию", "ью", "ия", "ья", "а", "е", "и", "й", "о", "у", "ы", "ь", "ю", "я"}
+	ruSuperlative       = []string{"ейше", "ейш"}
+	ruDerivational      = []string{"ость", "ост"}
+)
+
+// isRuVowel reports whether the letter is a Russian vowel.
+func isRuVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// stemRussian stems a lowercase Russian word with the Snowball algorithm.
+func stemRussian(word string) string {
+	w := []rune(word)
+
+	// RV is the region after the first vowel, R2 is the region after the
+	// first non-vowel following a vowel in R1 (which is defined the same way for the whole word).
+	rv := len(w)
+	for i, r := range w {
+		if isRuVowel(r) {
+			rv = i + 1
+			break
+		}
+	}
+	r1 := region(w, 0)
+	r2 := region(w, r1)
+
+	// Step 1
+	if s, ok := ruRemoveSuffix(w, rv, ruPerfectiveGerundA, true); ok {
+		w = s
+	} else if s, ok := ruRemoveSuffix(w, rv, ruPerfectiveGerund, false); ok {
+		w = s
+	} else {
+		if s, ok := ruRemoveSuffix(w, rv, ruReflexive, false); ok {
+			w = s
+		}
+		if s, ok := ruRemoveAdjectival(w, rv); ok {
+			w = s
+		} else if s, ok := ruRemoveSuffix(w, rv, ruVerbA, true); ok {
+			w = s
+		} else if s, ok := ruRemoveSuffix(w, rv, ruVerb, false); ok {
+			w = s
+		} else if s, ok := ruRemoveSuffix(w, rv, ruNoun, false); ok {
+			w = s
+		}
+	}
+
+	// Step 2
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3
+	if s, ok := ruRemoveSuffix(w, max(r2, rv), ruDerivational, false); ok {
+		w = s
+	}
+
+	// Step 4
+	if s, ok := ruRemoveSuffix(w, rv, ruSuperlative, false); ok {
+		w = s
+	}
+	if len(w)-2 >= rv && w[len(w)-1] == 'н' && w[len(w)-2] == 'н' {
+		w = w[:len(w)-1]
+	} else if len(w) > rv && w[len(w)-1] == 'ь' {
+		w = w[:len(w)-1]
+	}
+
+	return string(w)
+}
+
+// ruRemoveAdjectival removes an adjective ending optionally preceded by a participle one.
+func ruRemoveAdjectival(w []rune, rv int) ([]rune, bool) {
+	s, ok := ruRemoveSuffix(w, rv, ruAdjective, false)
+	if !ok {
+		return w, false
+	}
+	if p, ok := ruRemoveSuffix(s, rv, ruParticipleA, true); ok {
+		return p, true
+	}
+	if p, ok := ruRemoveSuffix(s, rv, ruParticiple, false); ok {
+		return p, true
+	}
+	return s, true
+}
+
+// ruRemoveSuffix removes the longest suffix of the group lying in the region starting at from.
+// If afterA is set, the suffix must be preceded by а or я, which are kept.
+func ruRemoveSuffix(w []rune, from int, suffixes []string, afterA bool) ([]rune, bool) {
+	for _, suffix := range suffixes {
+		sr := []rune(suffix)
+		start := len(w) - len(sr)
+		if start < from || string(w[start:]) != suffix {
+			continue
+		}
+		if afterA && (start-1 < from || (w[start-1] != 'а' && w[start-1] != 'я')) {
+			continue
+		}
+		return w[:start], true
+	}
+	return w, false
+}
+
+// region returns the start of the region after the first non-vowel following a vowel, searching from the given index.
+func region(w []rune, from int) int {
+	for i := from + 1; i < len(w); i++ {
+		if !isRuVowel(w[i]) && isRuVowel(w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// stemEnglish stems a lowercase English word with the Porter algorithm.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	s := &porter{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	return string(s.b)
+}
+
+// porter holds the word being stemmed by the Porter algorithm.
+type porter struct {
+	b []byte
+}
+
+// isConsonant reports whether the letter at i is a consonant.
+func (p *porter) isConsonant(i int) bool {
+	switch p.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !p.isConsonant(i-1)
+	}
+	return true
+}
+
+// measure returns the number of vowel-consonant sequences in the first n letters.
+func (p *porter) measure(n int) int {
+	m := 0
+	i := 0
+	for i < n && p.isConsonant(i) {
+		i++
+	}
+	for i < n {
+		for i < n && !p.isConsonant(i) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		m++
+		for i < n && p.isConsonant(i) {
+			i++
+		}
+	}
+	return m
+}
+
+// hasVowel reports whether the first n letters contain a vowel.
+func (p *porter) hasVowel(n int) bool {
+	for i := 0; i < n; i++ {
+		if !p.isConsonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsDoubleConsonant reports whether the first n letters end with a double consonant.
+func (p *porter) endsDoubleConsonant(n int) bool {
+	return n >= 2 && p.b[n-1] == p.b[n-2] && p.isConsonant(n-1)
+}
+
+// endsCVC reports whether the first n letters end with consonant-vowel-consonant,
+// where the last consonant is not w, x or y.
+func (p *porter) endsCVC(n int) bool {
+	if n < 3 || !p.isConsonant(n-1) || p.isConsonant(n-2) || !p.isConsonant(n-3) {
+		return false
+	}
+	c := p.b[n-1]
+	return c != 'w' && c != 'x' && c != 'y'
+}
+
+// ends reports whether the word ends with the suffix.
+func (p *porter) ends(suffix string) bool {
+	return strings.HasSuffix(string(p.b), suffix)
+}
+
+// replace replaces the suffix of the word if the stem before it has a measure greater than minMeasure.
+// It reports whether the suffix was found, even if it wasn't replaced.
+func (p *porter) replace(suffix, replacement string, minMeasure int) bool {
+	if !p.ends(suffix) {
+		return false
+	}
+	stem := len(p.b) - len(suffix)
+	if p.measure(stem) > minMeasure {
+		p.b = append(p.b[:stem], replacement...)
+	}
+	return true
+}
+
+func (p *porter) step1a() {
+	switch {
+	case p.ends("sses"):
+		p.b = p.b[:len(p.b)-2]
+	case p.ends("ies"):
+		p.b = p.b[:len(p.b)-2]
+	case p.ends("ss"):
+	case p.ends("s"):
+		p.b = p.b[:len(p.b)-1]
+	}
+}
+
+func (p *porter) step1b() {
+	if p.ends("eed") {
+		if p.measure(len(p.b)-3) > 0 {
+			p.b = p.b[:len(p.b)-1]
+		}
+		return
+	}
+
+	removed := false
+	for _, suffix := range []string{"ed", "ing"} {
+		if p.ends(suffix) && p.hasVowel(len(p.b)-len(suffix)) {
+			p.b = p.b[:len(p.b)-len(suffix)]
+			removed = true
+			break
+		}
+	}
+	if !removed {
+		return
+	}
+
+	n := len(p.b)
+	switch {
+	case p.ends("at"), p.ends("bl"), p.ends("iz"):
+		p.b = append(p.b, 'e')
+	case p.endsDoubleConsonant(n) && p.b[n-1] != 'l' && p.b[n-1] != 's' && p.b[n-1] != 'z':
+		p.b = p.b[:n-1]
+	case p.measure(n) == 1 && p.endsCVC(n):
+		p.b = append(p.b, 'e')
+	}
+}
+
+func (p *porter) step1c() {
+	if p.ends("y") && p.hasVowel(len(p.b)-1) {
+		p.b[len(p.b)-1] = 'i'
+	}
+}
+
+func (p *porter) step2() {
+	for _, r := range [][2]string{
+		{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+		{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+		{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+		{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	} {
+		if p.replace(r[0], r[1], 0) {
+			return
+		}
+	}
+}
+
+func (p *porter) step3() {
+	for _, r := range [][2]string{
+		{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+	} {
+		if p.replace(r[0], r[1], 0) {
+			return
+		}
+	}
+}
+
+func (p *porter) step4() {
+	for _, suffix := range []string{
+		"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+		"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+	} {
+		if !p.ends(suffix) {
+			continue
+		}
+		stem := len(p.b) - len(suffix)
+		if suffix == "ion" && (stem == 0 || (p.b[stem-1] != 's' && p.b[stem-1] != 't')) {
+			return
+		}
+		if p.measure(stem) > 1 {
+			p.b = p.b[:stem]
+		}
+		return
+	}
+}
+
+func (p *porter) step5() {
+	n := len(p.b)
+	if p.ends("e") {
+		m := p.measure(n - 1)
+		if m > 1 || (m == 1 && !p.endsCVC(n-1)) {
+			p.b = p.b[:n-1]
+			n--
+		}
+	}
+	if p.measure(n) > 1 && p.endsDoubleConsonant(n) && p.b[n-1] == 'l' {
+		p.b = p.b[:n-1]
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"fmt"
+	"log/slog"
+)
+
+// AccountService represents the service for the data of deleted accounts.
+type AccountService struct {
+	log  *slog.Logger           // Logging
+	repo *repository.Repository // Repository for OLO
+}
+
+// NewAccountService creates a new instance of AccountService with the provided logger and repository.
+func NewAccountService(log *slog.Logger, repo *repository.Repository) *AccountService {
+	return &AccountService{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// PurgeUserData deletes the widgets, articles, metrics, workouts, meals, profile,
+// goals and achievements of the user. Purging a user without data succeeds.
+func (s *AccountService) PurgeUserData(userId int64) error {
+	const op = "account.PurgeUserData"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if err := s.repo.PurgeUserData(userId); err != nil {
+		log.Error("failed purge user data", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't purge user data"))
+	}
+	log.Info("user data purged")
+	return nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// EventPublisher receives the domain events of users.
+type EventPublisher interface {
+	Publish(userId int64, event string)
+}
+
+// AchievementService represents the service for achievements of users.
+type AchievementService struct {
+	log   *slog.Logger           // Logging
+	repo  *repository.Repository // Repository for OLO
+	rules *achievements.Rules    // Rules of achievements
+}
+
+// NewAchievementService creates a new instance of AchievementService with the provided logger, repository and rules.
+func NewAchievementService(log *slog.Logger, repo *repository.Repository, rules *achievements.Rules) *AchievementService {
+	return &AchievementService{
+		log:   log,
+		repo:  repo,
+		rules: rules,
+	}
+}
+
+// Publish awards the achievements the event of the user earns. Only the counters the event
+// can change are evaluated. Failures are logged, they must not fail the action of the event.
+func (s *AchievementService) Publish(userId int64, event string) {
+	const op = "achievements.Publish"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.String("event", event))
+
+	counters := s.rules.Counters(event)
+	if len(counters) == 0 {
+		return
+	}
+	values, err := s.repo.CountActivity(userId, counters)
+	if err != nil {
+		log.Error("failed count activity", sl.Err(err))
+		return
+	}
+	if _, err := s.award(userId, values); err != nil {
+		log.Error("failed award achievements", sl.Err(err))
+	}
+}
+
+// ListAchievements returns all the achievements with the progress of the user, earned and locked.
+// Achievements of activity from before they were added to the rules are awarded here.
+func (s *AchievementService) ListAchievements(userId int64) ([]entity.Achievement, error) {
+	const op = "achievements.ListAchievements"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	values, err := s.repo.CountActivity(userId, s.rules.Counters(""))
+	if err != nil {
+		log.Error("failed count activity", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get achievements"))
+	}
+	earned, err := s.award(userId, values)
+	if err != nil {
+		log.Error("failed award achievements", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get achievements"))
+	}
+
+	rules := s.rules.All()
+	list := make([]entity.Achievement, len(rules))
+	for i, rule := range rules {
+		value := values[rule.Counter]
+		list[i] = entity.Achievement{
+			ID:          rule.ID,
+			Title:       rule.Title,
+			Description: rule.Description,
+			Counter:     rule.Counter,
+			Threshold:   rule.Threshold,
+			Value:       value,
+			Progress:    rule.Progress(value),
+			EarnedAt:    earned[rule.ID],
+		}
+		if list[i].EarnedAt != 0 {
+			list[i].Progress = 1
+		}
+	}
+	return list, nil
+}
+
+// award saves the achievements of the rules the counter values reach and returns the times
+// all the achievements of the user were earned at. Earned achievements are never awarded again.
+func (s *AchievementService) award(userId int64, values map[string]int64) (map[string]int64, error) {
+	earned, err := s.earnedAchievements(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	concurrent := false
+	for _, rule := range s.rules.Reached(values) {
+		if _, ok := earned[rule.ID]; ok {
+			continue
+		}
+		awarded, err := s.repo.AwardAchievement(userId, rule.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if !awarded {
+			// Another request awarded it first, its time is read back below.
+			concurrent = true
+			continue
+		}
+		s.log.Info("achievement earned", slog.Int64("userId", userId), slog.String("achievementId", rule.ID))
+		earned[rule.ID] = now
+	}
+
+	if concurrent {
+		return s.earnedAchievements(userId)
+	}
+	return earned, nil
+}
+
+// earnedAchievements returns the times the achievements of the user were earned at.
+func (s *AchievementService) earnedAchievements(userId int64) (map[string]int64, error) {
+	list, err := s.repo.GetEarnedAchievements(userId)
+	if err != nil {
+		return nil, err
+	}
+	earned := make(map[string]int64, len(list))
+	for _, achievement := range list {
+		earned[achievement.ID] = achievement.EarnedAt
+	}
+	return earned, nil
+}
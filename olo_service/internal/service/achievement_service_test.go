@@ -0,0 +1,69 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// achievementRepo is an in-memory repository of the achievements of one user.
+// The achievements in concurrent are awarded by another request right before this one awards them.
+type achievementRepo struct {
+	repository.Achievement
+	counters   map[string]int64
+	earned     []entity.EarnedAchievement
+	concurrent map[string]int64
+}
+
+func (r *achievementRepo) CountActivity(_ int64, counters []string) (map[string]int64, error) {
+	values := make(map[string]int64, len(counters))
+	for _, counter := range counters {
+		values[counter] = r.counters[counter]
+	}
+	return values, nil
+}
+
+func (r *achievementRepo) GetEarnedAchievements(_ int64) ([]entity.EarnedAchievement, error) {
+	return append([]entity.EarnedAchievement(nil), r.earned...), nil
+}
+
+func (r *achievementRepo) AwardAchievement(_ int64, achievementId string, earnedAt int64) (bool, error) {
+	if at, ok := r.concurrent[achievementId]; ok {
+		r.earned = append(r.earned, entity.EarnedAchievement{ID: achievementId, EarnedAt: at})
+		return false, nil
+	}
+	r.earned = append(r.earned, entity.EarnedAchievement{ID: achievementId, EarnedAt: earnedAt})
+	return true, nil
+}
+
+func TestListAchievementsEarnedAt(t *testing.T) {
+	rules, err := achievements.ParseRules([]byte("achievements:\n" +
+		"  - {id: first_workout, title: First workout, counter: workouts, threshold: 1}\n" +
+		"  - {id: ten_workouts, title: Ten workouts, counter: workouts, threshold: 10}\n" +
+		"  - {id: first_meal, title: First meal, counter: meals, threshold: 1}\n" +
+		"  - {id: first_article, title: First article, counter: articles, threshold: 1}"))
+	require.NoError(t, err)
+
+	repo := &achievementRepo{
+		counters:   map[string]int64{achievements.CounterWorkouts: 3, achievements.CounterMeals: 1, achievements.CounterArticles: 1},
+		earned:     []entity.EarnedAchievement{{ID: "first_workout", EarnedAt: 100}},
+		concurrent: map[string]int64{"first_meal": 200},
+	}
+	s := NewAchievementService(discardLogger(), &repository.Repository{Achievement: repo}, rules)
+
+	start := time.Now().Unix()
+	list, err := s.ListAchievements(1)
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	assert.Equal(t, int64(100), list[0].EarnedAt, "earned before")
+	assert.Zero(t, list[1].EarnedAt, "not reached")
+	assert.Equal(t, int64(200), list[2].EarnedAt, "awarded by another request")
+	assert.GreaterOrEqual(t, list[3].EarnedAt, start, "awarded now")
+	assert.Equal(t, float64(1), list[2].Progress)
+}
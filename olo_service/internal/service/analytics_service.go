@@ -0,0 +1,121 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/analytics"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Time ranges of exercise stats.
+const (
+	defaultStatsRange = 12 * 7 * 24 * time.Hour
+	maxStatsRange     = 53 * 7 * 24 * time.Hour
+)
+
+// AnalyticsService represents the service for training analytics of workouts.
+type AnalyticsService struct {
+	log  *slog.Logger           // Logging
+	repo *repository.Repository // Repository for OLO
+}
+
+// NewAnalyticsService creates a new instance of AnalyticsService with the provided logger and repository.
+func NewAnalyticsService(log *slog.Logger, repo *repository.Repository) *AnalyticsService {
+	return &AnalyticsService{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// DetectRecords returns the personal records the saved workout of the user sets,
+// it is compared with the workouts started before it.
+func (s *AnalyticsService) DetectRecords(userId int64, workout entity.Workout) ([]entity.PersonalRecord, error) {
+	const op = "analytics.DetectRecords"
+
+	sets := make([]entity.LoggedSet, len(workout.Sets))
+	var exerciseIds []int64
+	seen := make(map[int64]bool)
+	for i, set := range workout.Sets {
+		sets[i] = entity.LoggedSet{WorkoutSet: set, StartedAt: workout.StartedAt}
+		sets[i].WorkoutID = workout.ID
+		if !seen[set.ExerciseID] {
+			seen[set.ExerciseID] = true
+			exerciseIds = append(exerciseIds, set.ExerciseID)
+		}
+	}
+
+	bestSets, err := s.repo.GetBestSets(userId, exerciseIds, workout.StartedAt, workout.ID)
+	if err != nil {
+		return nil, sl.Wrap(op, err)
+	}
+	previous := make([]entity.LoggedSet, len(bestSets))
+	for i, set := range bestSets {
+		previous[i] = entity.LoggedSet{WorkoutSet: set}
+	}
+
+	return analytics.NewRecords(analytics.Records(previous), analytics.Records(sets)), nil
+}
+
+// GetExerciseStats returns the personal records of the user in the exercise with the progress
+// in the time range and the weekly volume of all muscle groups in it. Zero from and to mean
+// the last twelve weeks, weeks start on Monday in the user's time zone.
+func (s *AnalyticsService) GetExerciseStats(userId, exerciseId int64, from, to int64, utcOffset int, formula analytics.Formula) (entity.ExerciseStats, error) {
+	const op = "analytics.GetExerciseStats"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("exerciseId", exerciseId))
+
+	if from == 0 && to == 0 {
+		to = time.Now().Unix()
+		from = to - int64(defaultStatsRange/time.Second)
+	}
+	switch {
+	case utcOffset < -maxUTCOffset || utcOffset > maxUTCOffset:
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("%w: invalid time zone offset", ErrInvalidTimeRange))
+	case from >= to:
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("%w: from must be before to", ErrInvalidTimeRange))
+	case to-from > int64(maxStatsRange/time.Second):
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("%w: range is longer than %d weeks", ErrInvalidTimeRange, maxStatsRange/(7*24*time.Hour)))
+	}
+
+	exercises, err := s.repo.GetExercises([]int64{exerciseId})
+	if err != nil {
+		log.Error("failed get exercise", sl.Err(err))
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("can't get exercise"))
+	}
+	exercise, ok := exercises[exerciseId]
+	if !ok {
+		return entity.ExerciseStats{}, sl.Wrap(op, ErrExerciseNotFound)
+	}
+
+	history, err := s.repo.GetLoggedSets(userId, exerciseId, 0, 0)
+	if err != nil {
+		log.Error("failed get exercise sets", sl.Err(err))
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("can't get exercise stats"))
+	}
+	sets, err := s.repo.GetLoggedSets(userId, 0, from, to)
+	if err != nil {
+		log.Error("failed get sets", sl.Err(err))
+		return entity.ExerciseStats{}, sl.Wrap(op, fmt.Errorf("can't get exercise stats"))
+	}
+
+	stats := entity.ExerciseStats{
+		Exercise:     exercise,
+		Records:      analytics.Records(history),
+		WeeklyVolume: analytics.WeeklyVolume(sets, weekAnchor-int64(utcOffset)),
+	}
+	var inRange []entity.LoggedSet
+	for _, set := range history {
+		stats.OneRepMax = max(stats.OneRepMax, analytics.OneRepMax(set.WeightKg, set.Reps, formula))
+		if set.StartedAt >= from && set.StartedAt < to {
+			inRange = append(inRange, set)
+		}
+	}
+	stats.Progress = analytics.Progress(inRange, formula)
+	return stats, nil
+}
@@ -0,0 +1,39 @@
+package service
+
+import "errors"
+
+// Errors returned by the services, so handlers can tell them apart from internal failures.
+var (
+	ErrArticleNotFound  = errors.New("article not found")
+	ErrInvalidPageToken = errors.New("invalid page token")
+	ErrCategoryNotFound = errors.New("category not found")
+	ErrEmptySearchQuery = errors.New("search query has no words to search for")
+
+	ErrWidgetNotFound    = errors.New("widget not found")
+	ErrUnknownWidgetType = errors.New("unknown widget type")
+	ErrInvalidWidgetData = errors.New("invalid widget data")
+	ErrWidgetTypeChanged = errors.New("widget type can't be changed")
+	ErrInvalidLayout     = errors.New("invalid dashboard layout")
+	ErrRevisionNotFound  = errors.New("widget revision not found")
+
+	ErrInvalidMetric    = errors.New("invalid metric")
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	ErrMetricNotFound   = errors.New("metric not found")
+
+	ErrInvalidExercise  = errors.New("invalid exercise")
+	ErrExerciseExists   = errors.New("exercise already exists")
+	ErrExerciseNotFound = errors.New("exercise not found")
+	ErrInvalidWorkout   = errors.New("invalid workout")
+	ErrWorkoutNotFound  = errors.New("workout not found")
+
+	ErrInvalidDate  = errors.New("invalid date")
+	ErrInvalidMeal  = errors.New("invalid meal")
+	ErrFoodNotFound = errors.New("food not found")
+	ErrMealNotFound = errors.New("meal not found")
+
+	ErrInvalidProfile  = errors.New("invalid profile")
+	ErrProfileNotFound = errors.New("profile not found")
+
+	ErrInvalidGoal  = errors.New("invalid goal")
+	ErrGoalNotFound = errors.New("goal not found")
+)
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/export"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// exportBatch is the number of rows read from the database at once while exporting.
+const exportBatch = 1000
+
+// AccountProvider returns the account of the user a request was made by from the auth service.
+type AccountProvider interface {
+	GetAccount(ctx context.Context) (entity.Account, error)
+}
+
+// ExportService represents the service for the data export of users.
+type ExportService struct {
+	log      *slog.Logger           // Logging
+	repo     *repository.Repository // Repository for OLO
+	accounts AccountProvider        // Accounts of users
+}
+
+// NewExportService creates a new instance of ExportService with the provided logger, repository and account provider.
+func NewExportService(log *slog.Logger, repo *repository.Repository, accounts AccountProvider) *ExportService {
+	return &ExportService{
+		log:      log,
+		repo:     repo,
+		accounts: accounts,
+	}
+}
+
+// exportFile is a file of the export archive with the function writing it.
+type exportFile struct {
+	name  string
+	write func(ctx context.Context, archive *export.Archive, userId int64) error
+}
+
+// ExportUserData writes a ZIP archive of all the data of the user to w, the account info
+// is requested from the auth service on behalf of the request in the context.
+// The archive is written while the data is read, a failed export leaves it incomplete.
+func (s *ExportService) ExportUserData(ctx context.Context, userId int64, w io.Writer) error {
+	const op = "export.ExportUserData"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	files := []exportFile{
+		{"account.json", s.writeAccount},
+		{"profile.json", s.writeProfile},
+		{"widgets.json", s.writeWidgets},
+		{"articles.csv", s.writeArticles},
+		{"metrics.csv", s.writeMetrics},
+		{"workouts.csv", s.writeWorkouts},
+		{"workout_sets.csv", s.writeWorkoutSets},
+		{"meals.csv", s.writeMeals},
+		{"goals.csv", s.writeGoals},
+		{"achievements.csv", s.writeAchievements},
+	}
+
+	archive := export.NewArchive(w)
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return sl.Wrap(op, err)
+		}
+		if err := file.write(ctx, archive, userId); err != nil {
+			log.Error("failed export file", slog.String("file", file.name), sl.Err(err))
+			return sl.Wrap(op, fmt.Errorf("can't export %s", file.name))
+		}
+	}
+	if err := archive.Close(); err != nil {
+		log.Error("failed finish archive", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't export data"))
+	}
+
+	log.Info("user data exported")
+	return nil
+}
+
+func (s *ExportService) writeAccount(ctx context.Context, archive *export.Archive, _ int64) error {
+	account, err := s.accounts.GetAccount(ctx)
+	if err != nil {
+		return err
+	}
+	return archive.JSON("account.json", struct {
+		UserID       int64  `json:"user_id"`
+		Email        string `json:"email"`
+		Role         string `json:"role"`
+		DateRegister string `json:"date_register"`
+	}{account.UserID, account.Email, account.Role, account.DateRegister})
+}
+
+// writeProfile writes the fitness profile of the user, null if it wasn't filled in.
+func (s *ExportService) writeProfile(_ context.Context, archive *export.Archive, userId int64) error {
+	type exportProfile struct {
+		HeightCm      float64     `json:"height_cm"`
+		BirthDate     string      `json:"birth_date"`
+		Sex           string      `json:"sex"`
+		ActivityLevel string      `json:"activity_level"`
+		Units         string      `json:"units"`
+		GoalWeightKg  float64     `json:"goal_weight_kg,omitempty"`
+		UpdatedAt     export.Time `json:"updated_at"`
+	}
+
+	profile, err := s.repo.GetProfile(userId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return archive.JSON("profile.json", nil)
+	}
+	if err != nil {
+		return err
+	}
+	return archive.JSON("profile.json", exportProfile{
+		HeightCm:      profile.HeightCm,
+		BirthDate:     profile.BirthDate,
+		Sex:           profile.Sex,
+		ActivityLevel: profile.ActivityLevel,
+		Units:         profile.Units,
+		GoalWeightKg:  profile.GoalWeightKg,
+		UpdatedAt:     export.Time(profile.UpdatedAt),
+	})
+}
+
+func (s *ExportService) writeWidgets(_ context.Context, archive *export.Archive, userId int64) error {
+	type exportWidget struct {
+		ID       int64           `json:"id"`
+		Type     string          `json:"type"`
+		Data     json.RawMessage `json:"data"`
+		Version  int             `json:"version"`
+		Position int             `json:"position"`
+		X        int             `json:"x"`
+		Y        int             `json:"y"`
+		Width    int             `json:"width"`
+		Height   int             `json:"height"`
+		Visible  bool            `json:"visible"`
+	}
+
+	widgets, err := s.repo.GetWidgets(userId)
+	if err != nil {
+		return err
+	}
+	list := make([]exportWidget, len(widgets))
+	for i, widget := range widgets {
+		// data of widgets created before the widget types may not be JSON, it is exported as a string
+		data := json.RawMessage(widget.Data)
+		if !json.Valid(data) {
+			data, _ = json.Marshal(widget.Data)
+		}
+		list[i] = exportWidget{
+			ID:       widget.ID,
+			Type:     widget.Type,
+			Data:     data,
+			Version:  widget.Version,
+			Position: widget.Position,
+			X:        widget.X,
+			Y:        widget.Y,
+			Width:    widget.Width,
+			Height:   widget.Height,
+			Visible:  widget.Visible,
+		}
+	}
+	return archive.JSON("widgets.json", list)
+}
+
+func (s *ExportService) writeArticles(_ context.Context, archive *export.Archive, userId int64) error {
+	articles, err := s.repo.GetUsersArticles(userId, entity.ArticleQuery{}, nil)
+	if err != nil {
+		return err
+	}
+	file, err := archive.CSV("articles.csv", "id", "header", "category_id", "tags")
+	if err != nil {
+		return err
+	}
+	for _, article := range articles {
+		if err := file.Write(article.ID, article.Header, article.CategoryID, strings.Join(article.Tags, ";")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ExportService) writeMetrics(ctx context.Context, archive *export.Archive, userId int64) error {
+	file, err := archive.CSV("metrics.csv", "id", "type", "recorded_at", "value", "unit", "source")
+	if err != nil {
+		return err
+	}
+	var afterId int64
+	for ctx.Err() == nil {
+		metrics, err := s.repo.ListMetrics(userId, afterId, exportBatch)
+		if err != nil {
+			return err
+		}
+		for _, metric := range metrics {
+			err := file.Write(metric.ID, metric.Type, export.Time(metric.RecordedAt), metric.Value, metric.Unit, metric.Source)
+			if err != nil {
+				return err
+			}
+			afterId = metric.ID
+		}
+		if len(metrics) < exportBatch {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// eachWorkout calls fn with every page of the workouts of the user, newest first.
+func (s *ExportService) eachWorkout(ctx context.Context, userId int64, fn func([]entity.Workout) error) error {
+	var after *entity.WorkoutCursor
+	for ctx.Err() == nil {
+		workouts, err := s.repo.ListWorkouts(userId, 0, 0, after, exportBatch)
+		if err != nil {
+			return err
+		}
+		if err := fn(workouts); err != nil {
+			return err
+		}
+		if len(workouts) < exportBatch {
+			return nil
+		}
+		last := workouts[len(workouts)-1]
+		after = &entity.WorkoutCursor{StartedAt: last.StartedAt, ID: last.ID}
+	}
+	return ctx.Err()
+}
+
+func (s *ExportService) writeWorkouts(ctx context.Context, archive *export.Archive, userId int64) error {
+	file, err := archive.CSV("workouts.csv", "id", "name", "started_at", "finished_at", "notes")
+	if err != nil {
+		return err
+	}
+	return s.eachWorkout(ctx, userId, func(workouts []entity.Workout) error {
+		for _, workout := range workouts {
+			err := file.Write(workout.ID, workout.Name, export.Time(workout.StartedAt), export.Time(workout.FinishedAt), workout.Notes)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *ExportService) writeWorkoutSets(ctx context.Context, archive *export.Archive, userId int64) error {
+	file, err := archive.CSV("workout_sets.csv", "workout_id", "exercise_id", "exercise", "reps", "weight_kg", "duration_sec", "distance_m")
+	if err != nil {
+		return err
+	}
+	return s.eachWorkout(ctx, userId, func(workouts []entity.Workout) error {
+		var exerciseIds []int64
+		for _, workout := range workouts {
+			for _, set := range workout.Sets {
+				exerciseIds = append(exerciseIds, set.ExerciseID)
+			}
+		}
+		exercises, err := s.repo.GetExercises(exerciseIds)
+		if err != nil {
+			return err
+		}
+		for _, workout := range workouts {
+			for _, set := range workout.Sets {
+				err := file.Write(workout.ID, set.ExerciseID, exercises[set.ExerciseID].Name,
+					set.Reps, set.WeightKg, set.DurationSec, set.DistanceM)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *ExportService) writeMeals(ctx context.Context, archive *export.Archive, userId int64) error {
+	file, err := archive.CSV("meals.csv", "id", "date", "meal_type", "food_id", "food", "grams",
+		"calories", "protein", "fat", "carbs", "created_at")
+	if err != nil {
+		return err
+	}
+	var afterId int64
+	for ctx.Err() == nil {
+		meals, err := s.repo.ListMeals(userId, afterId, exportBatch)
+		if err != nil {
+			return err
+		}
+		for _, meal := range meals {
+			err := file.Write(meal.ID, meal.Date, meal.MealType, meal.FoodID, meal.FoodName, meal.Grams,
+				meal.Calories, meal.Protein, meal.Fat, meal.Carbs, export.Time(meal.CreatedAt))
+			if err != nil {
+				return err
+			}
+			afterId = meal.ID
+		}
+		if len(meals) < exportBatch {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (s *ExportService) writeGoals(_ context.Context, archive *export.Archive, userId int64) error {
+	goals, err := s.repo.ListGoals(userId, "")
+	if err != nil {
+		return err
+	}
+	file, err := archive.CSV("goals.csv", "id", "title", "kind", "metric_type", "comparison", "target", "start_value",
+		"deadline", "status", "current_value", "progress", "current_streak", "longest_streak", "created_at", "completed_at")
+	if err != nil {
+		return err
+	}
+	for _, goal := range goals {
+		err := file.Write(goal.ID, goal.Title, goal.Kind, goal.MetricType, goal.Comparison, goal.Target, goal.StartValue,
+			goal.Deadline, goal.Status, goal.CurrentValue, goal.Progress, goal.CurrentStreak, goal.LongestStreak,
+			export.Time(goal.CreatedAt), export.Time(goal.CompletedAt))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ExportService) writeAchievements(_ context.Context, archive *export.Archive, userId int64) error {
+	achievements, err := s.repo.GetEarnedAchievements(userId)
+	if err != nil {
+		return err
+	}
+	file, err := archive.CSV("achievements.csv", "id", "earned_at")
+	if err != nil {
+		return err
+	}
+	for _, achievement := range achievements {
+		if err := file.Write(achievement.ID, export.Time(achievement.EarnedAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/goals"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+// Kinds and statuses of goals.
+const (
+	goalDaily  = "daily"
+	goalTarget = "target"
+
+	goalActive    = "active"
+	goalCompleted = "completed"
+	goalFailed    = "failed"
+)
+
+var goalStatuses = []string{goalActive, goalCompleted, goalFailed}
+
+// dailyGoalAggregates are the values of a day of the metrics daily goals can track.
+// Weight can't be summed over a day, it is tracked by target goals only.
+var dailyGoalAggregates = map[string]func(entity.MetricBucket) float64{
+	"steps":      bucketSum,
+	"water":      bucketSum,
+	"calories":   bucketSum,
+	"distance":   bucketSum,
+	"sleep":      bucketSum,
+	"heart_rate": bucketAvg,
+}
+
+func bucketSum(bucket entity.MetricBucket) float64 { return bucket.Sum }
+func bucketAvg(bucket entity.MetricBucket) float64 { return bucket.Avg }
+
+// Limits of goals.
+const (
+	maxGoalTitleLength     = 100
+	defaultGoalHistoryDays = 30
+	maxGoalHistoryDays     = 366
+	goalEvaluationBatch    = 100
+	secondsPerDay          = 24 * 60 * 60
+
+	defaultEvaluateInterval = 24 * time.Hour
+)
+
+// GoalService represents the service for goals of users.
+type GoalService struct {
+	log    *slog.Logger           // Logging
+	repo   *repository.Repository // Repository for OLO
+	events EventPublisher         // Domain events of users
+}
+
+// NewGoalService creates a new instance of GoalService with the provided logger, repository and event publisher.
+func NewGoalService(log *slog.Logger, repo *repository.Repository, events EventPublisher) *GoalService {
+	return &GoalService{
+		log:    log,
+		repo:   repo,
+		events: events,
+	}
+}
+
+// CreateGoal creates a goal of the user and returns it evaluated.
+// A target goal starts at the latest value of its metric unless the start value is set,
+// its comparison follows from whether the target is below or above the start.
+func (s *GoalService) CreateGoal(userId int64, goal entity.Goal) (entity.Goal, error) {
+	const op = "goals.CreateGoal"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	goal.UserID = userId
+	goal.CreatedAt = time.Now().Unix()
+	goal.Status = goalActive
+
+	if goal.Kind == goalTarget && goal.StartValue == 0 {
+		latest, err := s.repo.GetLatestMetric(userId, goal.MetricType)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			log.Error("failed get latest metric", sl.Err(err))
+			return entity.Goal{}, sl.Wrap(op, fmt.Errorf("can't create goal"))
+		}
+		goal.StartValue = latest.Value
+	}
+	if err := normalizeGoal(&goal); err != nil {
+		return entity.Goal{}, sl.Wrap(op, err)
+	}
+
+	goalId, err := s.repo.CreateGoal(goal)
+	if err != nil {
+		log.Error("failed create goal", sl.Err(err))
+		return entity.Goal{}, sl.Wrap(op, fmt.Errorf("can't create goal"))
+	}
+	goal.ID = goalId
+
+	if _, err := s.evaluate(&goal, time.Now()); err != nil {
+		log.Error("failed evaluate goal", sl.Err(err))
+		return entity.Goal{}, sl.Wrap(op, fmt.Errorf("can't evaluate goal"))
+	}
+	if err := s.repo.SaveGoalEvaluation(goal); err != nil {
+		log.Error("failed save goal evaluation", sl.Err(err))
+		return entity.Goal{}, sl.Wrap(op, fmt.Errorf("can't evaluate goal"))
+	}
+	s.events.Publish(userId, achievements.EventGoalEvaluated)
+
+	log.Info("goal created", slog.Int64("goalId", goalId))
+	return goal, nil
+}
+
+// normalizeGoal validates the goal and sets its default comparison,
+// invalid goals are reported with ErrInvalidGoal.
+func normalizeGoal(goal *entity.Goal) error {
+	switch {
+	case goal.Title == "" || utf8.RuneCountInString(goal.Title) > maxGoalTitleLength:
+		return fmt.Errorf("%w: title must be from 1 to %d characters", ErrInvalidGoal, maxGoalTitleLength)
+	case goal.UTCOffset < -maxUTCOffset || goal.UTCOffset > maxUTCOffset:
+		return fmt.Errorf("%w: invalid time zone offset", ErrInvalidGoal)
+	case metricUnits[goal.MetricType] == "":
+		return fmt.Errorf("%w: unknown metric type %q", ErrInvalidGoal, goal.MetricType)
+	case math.IsNaN(goal.Target) || math.IsInf(goal.Target, 0) || goal.Target < 0:
+		return fmt.Errorf("%w: target must be a non-negative number", ErrInvalidGoal)
+	}
+
+	switch goal.Kind {
+	case goalDaily:
+		if goal.Comparison == "" {
+			goal.Comparison = goals.AtLeast
+		}
+		switch {
+		case dailyGoalAggregates[goal.MetricType] == nil:
+			return fmt.Errorf("%w: %s can't be tracked by a daily goal", ErrInvalidGoal, goal.MetricType)
+		case goal.Comparison != goals.AtLeast && goal.Comparison != goals.AtMost:
+			return fmt.Errorf("%w: comparison must be %s or %s", ErrInvalidGoal, goals.AtLeast, goals.AtMost)
+		case goal.Target == 0:
+			return fmt.Errorf("%w: target must be positive", ErrInvalidGoal)
+		case goal.Deadline != "":
+			return fmt.Errorf("%w: daily goals have no deadline", ErrInvalidGoal)
+		}
+		goal.StartValue = 0
+	case goalTarget:
+		switch {
+		case goal.StartValue == 0:
+			return fmt.Errorf("%w: record the %s metric or set the start value", ErrInvalidGoal, goal.MetricType)
+		case math.IsNaN(goal.StartValue) || math.IsInf(goal.StartValue, 0) || goal.StartValue < 0:
+			return fmt.Errorf("%w: start value must be a non-negative number", ErrInvalidGoal)
+		case goal.Target == goal.StartValue:
+			return fmt.Errorf("%w: target must differ from the start value", ErrInvalidGoal)
+		}
+		goal.Comparison = goals.AtLeast
+		if goal.Target < goal.StartValue {
+			goal.Comparison = goals.AtMost
+		}
+		if goal.Deadline != "" {
+			if _, err := time.Parse(time.DateOnly, goal.Deadline); err != nil {
+				return fmt.Errorf("%w: deadline must be in the YYYY-MM-DD format", ErrInvalidGoal)
+			}
+			if deadlineEnd(goal.Deadline, int64(goal.UTCOffset)) <= goal.CreatedAt {
+				return fmt.Errorf("%w: deadline must not be in the past", ErrInvalidGoal)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: kind must be %s or %s", ErrInvalidGoal, goalDaily, goalTarget)
+	}
+	return nil
+}
+
+// ListGoals returns the goals of the user as of their latest evaluation, only with the status if it is set.
+func (s *GoalService) ListGoals(userId int64, status string) ([]entity.Goal, error) {
+	const op = "goals.ListGoals"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if status != "" && !slices.Contains(goalStatuses, status) {
+		return nil, sl.Wrap(op, fmt.Errorf("%w: status must be one of %v", ErrInvalidGoal, goalStatuses))
+	}
+
+	list, err := s.repo.ListGoals(userId, status)
+	if err != nil {
+		log.Error("failed get goals", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get goals"))
+	}
+	return list, nil
+}
+
+// GetGoalProgress evaluates a goal of the user and returns it with the values of its last days, oldest first.
+func (s *GoalService) GetGoalProgress(userId, goalId int64, days int) (entity.Goal, []entity.GoalDay, error) {
+	const op = "goals.GetGoalProgress"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("goalId", goalId))
+
+	if days <= 0 {
+		days = defaultGoalHistoryDays
+	}
+	days = min(days, maxGoalHistoryDays)
+
+	goal, err := s.repo.GetGoal(userId, goalId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Goal{}, nil, sl.Wrap(op, ErrGoalNotFound)
+		}
+		log.Error("failed get goal", sl.Err(err))
+		return entity.Goal{}, nil, sl.Wrap(op, fmt.Errorf("can't get goal"))
+	}
+
+	history, err := s.evaluate(&goal, time.Now())
+	if err != nil {
+		log.Error("failed evaluate goal", sl.Err(err))
+		return entity.Goal{}, nil, sl.Wrap(op, fmt.Errorf("can't evaluate goal"))
+	}
+	if err := s.repo.SaveGoalEvaluation(goal); err != nil {
+		log.Error("failed save goal evaluation", sl.Err(err))
+		return entity.Goal{}, nil, sl.Wrap(op, fmt.Errorf("can't evaluate goal"))
+	}
+	s.events.Publish(userId, achievements.EventGoalEvaluated)
+
+	if len(history) > days {
+		history = history[len(history)-days:]
+	}
+	return goal, history, nil
+}
+
+// EvaluateGoals evaluates all active goals and saves the results. A goal that fails
+// to be evaluated is logged and skipped, it is evaluated again on the next run.
+func (s *GoalService) EvaluateGoals(ctx context.Context) {
+	const op = "goals.EvaluateGoals"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	var afterId int64
+	var evaluated, failed int
+	for ctx.Err() == nil {
+		batch, err := s.repo.ListActiveGoals(afterId, goalEvaluationBatch)
+		if err != nil {
+			log.Error("failed get active goals", sl.Err(err))
+			return
+		}
+		for _, goal := range batch {
+			if ctx.Err() != nil {
+				break
+			}
+			afterId = goal.ID
+			if _, err := s.evaluate(&goal, time.Now()); err != nil {
+				log.Error("failed evaluate goal", slog.Int64("goalId", goal.ID), sl.Err(err))
+				failed++
+				continue
+			}
+			if err := s.repo.SaveGoalEvaluation(goal); err != nil {
+				log.Error("failed save goal evaluation", slog.Int64("goalId", goal.ID), sl.Err(err))
+				failed++
+				continue
+			}
+			s.events.Publish(goal.UserID, achievements.EventGoalEvaluated)
+			evaluated++
+		}
+		if len(batch) < goalEvaluationBatch {
+			break
+		}
+	}
+	log.Info("goals evaluated", slog.Int("evaluated", evaluated), slog.Int("failed", failed))
+}
+
+// RunEvaluator evaluates the active goals right away and then every interval until the context is done.
+// A non-positive interval falls back to once a day.
+func (s *GoalService) RunEvaluator(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		s.log.Warn("invalid goal evaluation interval, using the default",
+			slog.Duration("interval", interval), slog.Duration("default", defaultEvaluateInterval))
+		interval = defaultEvaluateInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.EvaluateGoals(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate updates the progress, streaks and status of the goal at the time and returns
+// the values of all its days since it was created. Completed and failed goals keep their status.
+func (s *GoalService) evaluate(goal *entity.Goal, now time.Time) ([]entity.GoalDay, error) {
+	offset := int64(goal.UTCOffset)
+	today := dayStart(now.Unix(), offset)
+	first := dayStart(goal.CreatedAt, offset)
+
+	query := entity.MetricQuery{Type: goal.MetricType, From: first, To: today + secondsPerDay}
+	buckets, err := s.repo.QueryMetrics(goal.UserID, query, secondsPerDay, -offset)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregate, ok := dailyGoalAggregates[goal.MetricType]
+	if !ok || goal.Kind == goalTarget {
+		aggregate = bucketAvg
+	}
+	values := make(map[int64]float64, len(buckets))
+	for _, bucket := range buckets {
+		values[bucket.Start] = aggregate(bucket)
+	}
+
+	history := make([]entity.GoalDay, (today-first)/secondsPerDay+1)
+	met := make([]bool, len(history))
+	for i := range history {
+		start := first + int64(i)*secondsPerDay
+		value, ok := values[start]
+		met[i] = ok && goals.Met(value, goal.Target, goal.Comparison)
+		history[i] = entity.GoalDay{
+			Date:  time.Unix(start+offset, 0).UTC().Format(time.DateOnly),
+			Value: value,
+			Met:   met[i],
+		}
+	}
+
+	goal.EvaluatedAt = now.Unix()
+	if goal.Kind == goalDaily {
+		goal.CurrentValue = history[len(history)-1].Value
+		goal.Progress = min(goal.CurrentValue/goal.Target, 1)
+		goal.CurrentStreak, goal.LongestStreak = goals.Streaks(met)
+		return history, nil
+	}
+
+	latest, err := s.repo.GetLatestMetric(goal.UserID, goal.MetricType)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		goal.CurrentValue = goal.StartValue
+	case err != nil:
+		return nil, err
+	default:
+		goal.CurrentValue = latest.Value
+	}
+	goal.Progress = goals.Progress(goal.StartValue, goal.CurrentValue, goal.Target)
+
+	if goal.Status == goalActive {
+		switch {
+		case goals.Met(goal.CurrentValue, goal.Target, goal.Comparison):
+			goal.Status, goal.CompletedAt = goalCompleted, now.Unix()
+		case goal.Deadline != "" && now.Unix() >= deadlineEnd(goal.Deadline, offset):
+			goal.Status = goalFailed
+		}
+	}
+	return history, nil
+}
+
+// dayStart returns the start of the day of the time in the time zone with the offset.
+func dayStart(t, offset int64) int64 {
+	local := t + offset
+	start := local - local%secondsPerDay
+	if local%secondsPerDay < 0 {
+		start -= secondsPerDay
+	}
+	return start - offset
+}
+
+// deadlineEnd returns the end of the deadline day in the time zone with the offset.
+func deadlineEnd(deadline string, offset int64) int64 {
+	t, err := time.Parse(time.DateOnly, deadline)
+	if err != nil {
+		return math.MaxInt64
+	}
+	return t.Unix() - offset + secondsPerDay
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// Metric types a user can record with their units.
+var metricUnits = map[string]string{
+	"steps":      "count",
+	"weight":     "kg",
+	"water":      "ml",
+	"sleep":      "h",
+	"calories":   "kcal",
+	"heart_rate": "bpm",
+	"distance":   "m",
+}
+
+// Limits of metric requests.
+const (
+	maxMetricsInBatch  = 500
+	maxMetricBuckets   = 1000
+	maxMetricSourceLen = 64
+	maxUTCOffset       = 14 * 60 * 60
+	// metricClockSkew is how far in the future a reading may be, so devices with a wrong clock don't break the history
+	metricClockSkew = 24 * time.Hour
+)
+
+// weekAnchor is the unix time of Monday, 5 January 1970, weeks start on Monday.
+const weekAnchor = 4 * 24 * 60 * 60
+
+// MetricService represents the service for health metrics.
+type MetricService struct {
+	log    *slog.Logger           // Logging
+	repo   *repository.Repository // Repository for OLO
+	events EventPublisher         // Domain events of users
+}
+
+// NewMetricService creates a new instance of MetricService with the provided logger, repository and event publisher.
+func NewMetricService(log *slog.Logger, repo *repository.Repository, events EventPublisher) *MetricService {
+	return &MetricService{
+		log:    log,
+		repo:   repo,
+		events: events,
+	}
+}
+
+// RecordMetrics saves a batch of metric readings of the user.
+// A reading without a unit gets the unit of its type.
+func (s *MetricService) RecordMetrics(userId int64, metrics []entity.Metric) error {
+	const op = "metrics.RecordMetrics"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if len(metrics) == 0 || len(metrics) > maxMetricsInBatch {
+		return sl.Wrap(op, fmt.Errorf("%w: batch must have from 1 to %d metrics", ErrInvalidMetric, maxMetricsInBatch))
+	}
+	for i := range metrics {
+		if err := normalizeMetric(&metrics[i]); err != nil {
+			return sl.Wrap(op, fmt.Errorf("%w: metric %d: %s", ErrInvalidMetric, i, err))
+		}
+	}
+
+	if err := s.repo.RecordMetrics(userId, metrics); err != nil {
+		log.Error("failed record metrics", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't record metrics"))
+	}
+	s.events.Publish(userId, achievements.EventMetricRecorded)
+	return nil
+}
+
+// normalizeMetric validates the metric and sets the default unit.
+func normalizeMetric(metric *entity.Metric) error {
+	unit, ok := metricUnits[metric.Type]
+	if !ok {
+		return fmt.Errorf("unknown metric type %q", metric.Type)
+	}
+	if metric.Unit == "" {
+		metric.Unit = unit
+	}
+	if metric.Unit != unit {
+		return fmt.Errorf("unit of %s must be %s", metric.Type, unit)
+	}
+	if math.IsNaN(metric.Value) || math.IsInf(metric.Value, 0) || metric.Value < 0 {
+		return errors.New("value must be a non-negative number")
+	}
+	if metric.Type == "weight" && !validWeight(metric.Value) {
+		return fmt.Errorf("weight must be from %d to %d kg", minWeightKg, maxWeightKg)
+	}
+	if metric.RecordedAt <= 0 || metric.RecordedAt > time.Now().Add(metricClockSkew).Unix() {
+		return fmt.Errorf("recorded_at must be a unix time at most %d hours in the future", metricClockSkew/time.Hour)
+	}
+	if utf8.RuneCountInString(metric.Source) > maxMetricSourceLen {
+		return fmt.Errorf("source must be at most %d characters", maxMetricSourceLen)
+	}
+	return nil
+}
+
+// QueryMetrics returns the readings of a metric of the user in the time range downsampled to buckets.
+// Day and week buckets start at midnight of the user's time zone, weeks start on Monday.
+func (s *MetricService) QueryMetrics(userId int64, query entity.MetricQuery) ([]entity.MetricBucket, string, error) {
+	const op = "metrics.QueryMetrics"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.String("type", query.Type))
+
+	unit, ok := metricUnits[query.Type]
+	if !ok {
+		return nil, "", sl.Wrap(op, fmt.Errorf("%w: unknown metric type %q", ErrInvalidMetric, query.Type))
+	}
+
+	var size, anchor int64
+	switch query.Bucket {
+	case entity.MetricBucketHour:
+		size = int64(time.Hour / time.Second)
+	case entity.MetricBucketWeek:
+		size, anchor = int64(7*24*time.Hour/time.Second), weekAnchor
+	default:
+		size = int64(24 * time.Hour / time.Second)
+	}
+	anchor -= int64(query.UTCOffset)
+
+	switch {
+	case query.UTCOffset < -maxUTCOffset || query.UTCOffset > maxUTCOffset:
+		return nil, "", sl.Wrap(op, fmt.Errorf("%w: invalid time zone offset", ErrInvalidTimeRange))
+	case query.From >= query.To:
+		return nil, "", sl.Wrap(op, fmt.Errorf("%w: from must be before to", ErrInvalidTimeRange))
+	case (query.To-query.From)/size > maxMetricBuckets:
+		return nil, "", sl.Wrap(op, fmt.Errorf("%w: range is longer than %d buckets", ErrInvalidTimeRange, maxMetricBuckets))
+	}
+
+	buckets, err := s.repo.QueryMetrics(userId, query, size, anchor)
+	if err != nil {
+		log.Error("failed query metrics", sl.Err(err))
+		return nil, "", sl.Wrap(op, fmt.Errorf("can't query metrics"))
+	}
+	return buckets, unit, nil
+}
+
+// GetLatestMetric returns the latest reading of a metric of the user.
+func (s *MetricService) GetLatestMetric(userId int64, metricType string) (entity.Metric, error) {
+	const op = "metrics.GetLatestMetric"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.String("type", metricType))
+
+	if _, ok := metricUnits[metricType]; !ok {
+		return entity.Metric{}, sl.Wrap(op, fmt.Errorf("%w: unknown metric type %q", ErrInvalidMetric, metricType))
+	}
+
+	metric, err := s.repo.GetLatestMetric(userId, metricType)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Metric{}, sl.Wrap(op, ErrMetricNotFound)
+		}
+		log.Error("failed get latest metric", sl.Err(err))
+		return entity.Metric{}, sl.Wrap(op, fmt.Errorf("can't get latest metric"))
+	}
+	return metric, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricRepo is an in-memory repository of the metrics of one user.
+// It groups the readings into buckets the same way the SQL query does.
+type metricRepo struct {
+	repository.Metric
+	readings []entity.Metric
+}
+
+func (r *metricRepo) QueryMetrics(_ int64, query entity.MetricQuery, size, anchor int64) ([]entity.MetricBucket, error) {
+	buckets := make(map[int64]*entity.MetricBucket)
+	for _, reading := range r.readings {
+		if reading.Type != query.Type || reading.RecordedAt < query.From || reading.RecordedAt >= query.To {
+			continue
+		}
+		start := int64(math.Floor(float64(reading.RecordedAt-anchor)/float64(size)))*size + anchor
+		if buckets[start] == nil {
+			buckets[start] = &entity.MetricBucket{Start: start}
+		}
+		buckets[start].Count++
+		buckets[start].Sum += reading.Value
+	}
+
+	var result []entity.MetricBucket
+	for _, bucket := range buckets {
+		result = append(result, *bucket)
+	}
+	slices.SortFunc(result, func(a, b entity.MetricBucket) int { return int(a.Start - b.Start) })
+	return result, nil
+}
+
+// unix returns the unix time of the time in the time zone with the offset in hours.
+func unix(t *testing.T, value string, offsetHours int) int64 {
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.FixedZone("", offsetHours*60*60))
+	require.NoError(t, err)
+	return parsed.Unix()
+}
+
+func TestQueryMetricsBuckets(t *testing.T) {
+	// 1 May 2024 is a Wednesday.
+	tests := []struct {
+		name     string
+		bucket   entity.MetricBucketSize
+		offset   int // hours
+		readings []string
+		want     map[string]int64 // local start of a bucket to the number of its readings
+	}{
+		{
+			name:     "days in utc",
+			bucket:   entity.MetricBucketDay,
+			readings: []string{"2024-05-01 00:00", "2024-05-01 23:59", "2024-05-02 00:00"},
+			want:     map[string]int64{"2024-05-01 00:00": 2, "2024-05-02 00:00": 1},
+		},
+		{
+			name:     "days east of utc",
+			bucket:   entity.MetricBucketDay,
+			offset:   3,
+			readings: []string{"2024-05-01 01:30", "2024-05-01 23:30", "2024-05-02 02:00"},
+			want:     map[string]int64{"2024-05-01 00:00": 2, "2024-05-02 00:00": 1},
+		},
+		{
+			name:     "days west of utc",
+			bucket:   entity.MetricBucketDay,
+			offset:   -8,
+			readings: []string{"2024-05-01 20:00", "2024-05-02 07:00"},
+			want:     map[string]int64{"2024-05-01 00:00": 1, "2024-05-02 00:00": 1},
+		},
+		{
+			name:     "weeks start on monday",
+			bucket:   entity.MetricBucketWeek,
+			readings: []string{"2024-04-29 00:00", "2024-05-01 12:00", "2024-05-05 23:59", "2024-05-06 00:00"},
+			want:     map[string]int64{"2024-04-29 00:00": 3, "2024-05-06 00:00": 1},
+		},
+		{
+			name:     "weeks start on the local monday",
+			bucket:   entity.MetricBucketWeek,
+			offset:   10,
+			readings: []string{"2024-05-05 23:00", "2024-05-06 01:00"},
+			want:     map[string]int64{"2024-04-29 00:00": 1, "2024-05-06 00:00": 1},
+		},
+		{
+			name:     "hours",
+			bucket:   entity.MetricBucketHour,
+			offset:   5,
+			readings: []string{"2024-05-01 10:00", "2024-05-01 10:59", "2024-05-01 11:00"},
+			want:     map[string]int64{"2024-05-01 10:00": 2, "2024-05-01 11:00": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &metricRepo{}
+			for _, reading := range tt.readings {
+				repo.readings = append(repo.readings, entity.Metric{Type: "steps", RecordedAt: unix(t, reading, tt.offset), Value: 1})
+			}
+			s := NewMetricService(discardLogger(), &repository.Repository{Metric: repo}, nil)
+
+			query := entity.MetricQuery{
+				Type:      "steps",
+				From:      unix(t, "2024-04-20 00:00", tt.offset),
+				To:        unix(t, "2024-05-20 00:00", tt.offset),
+				Bucket:    tt.bucket,
+				UTCOffset: tt.offset * 60 * 60,
+			}
+			buckets, unit, err := s.QueryMetrics(1, query)
+			require.NoError(t, err)
+			assert.Equal(t, "count", unit)
+
+			got := make(map[string]int64)
+			for _, bucket := range buckets {
+				local := time.Unix(bucket.Start, 0).In(time.FixedZone("", query.UTCOffset))
+				got[local.Format("2006-01-02 15:04")] = bucket.Count
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQueryMetricsRange(t *testing.T) {
+	s := NewMetricService(discardLogger(), &repository.Repository{Metric: &metricRepo{}}, nil)
+	day := int64(24 * 60 * 60)
+
+	tests := []struct {
+		name    string
+		query   entity.MetricQuery
+		wantErr error
+	}{
+		{name: "valid", query: entity.MetricQuery{Type: "weight", From: 0, To: day}},
+		{name: "unknown type", query: entity.MetricQuery{Type: "mood", From: 0, To: day}, wantErr: ErrInvalidMetric},
+		{name: "empty range", query: entity.MetricQuery{Type: "weight", From: day, To: day}, wantErr: ErrInvalidTimeRange},
+		{name: "offset too far east", query: entity.MetricQuery{Type: "weight", To: day, UTCOffset: maxUTCOffset + 1}, wantErr: ErrInvalidTimeRange},
+		{name: "offset too far west", query: entity.MetricQuery{Type: "weight", To: day, UTCOffset: -maxUTCOffset - 1}, wantErr: ErrInvalidTimeRange},
+		{name: "most days", query: entity.MetricQuery{Type: "weight", To: maxMetricBuckets * day}},
+		{name: "too many days", query: entity.MetricQuery{Type: "weight", To: (maxMetricBuckets + 1) * day}, wantErr: ErrInvalidTimeRange},
+		{name: "weeks of the same range", query: entity.MetricQuery{Type: "weight", To: (maxMetricBuckets + 1) * day, Bucket: entity.MetricBucketWeek}},
+		{name: "too many hours", query: entity.MetricQuery{Type: "weight", To: 42 * day, Bucket: entity.MetricBucketHour}, wantErr: ErrInvalidTimeRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.QueryMetrics(1, tt.query)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeMetric(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name     string
+		metric   entity.Metric
+		wantUnit string
+		wantErr  bool
+	}{
+		{name: "default unit", metric: entity.Metric{Type: "steps", RecordedAt: now, Value: 100}, wantUnit: "count"},
+		{name: "explicit unit", metric: entity.Metric{Type: "water", RecordedAt: now, Value: 250, Unit: "ml"}, wantUnit: "ml"},
+		{name: "unknown type", metric: entity.Metric{Type: "mood", RecordedAt: now}, wantErr: true},
+		{name: "wrong unit", metric: entity.Metric{Type: "weight", RecordedAt: now, Value: 70, Unit: "lb"}, wantErr: true},
+		{name: "negative value", metric: entity.Metric{Type: "steps", RecordedAt: now, Value: -1}, wantErr: true},
+		{name: "not a number", metric: entity.Metric{Type: "steps", RecordedAt: now, Value: math.NaN()}, wantErr: true},
+		{name: "infinite", metric: entity.Metric{Type: "steps", RecordedAt: now, Value: math.Inf(1)}, wantErr: true},
+		{name: "weight too low", metric: entity.Metric{Type: "weight", RecordedAt: now, Value: 0}, wantErr: true},
+		{name: "weight too high", metric: entity.Metric{Type: "weight", RecordedAt: now, Value: maxWeightKg + 1}, wantErr: true},
+		{name: "no time", metric: entity.Metric{Type: "steps", Value: 1}, wantErr: true},
+		{name: "within clock skew", metric: entity.Metric{Type: "steps", RecordedAt: now + 3600, Value: 1}, wantUnit: "count"},
+		{name: "past clock skew", metric: entity.Metric{Type: "steps", RecordedAt: now + int64(metricClockSkew/time.Second) + 3600, Value: 1}, wantErr: true},
+		{name: "long source", metric: entity.Metric{Type: "steps", RecordedAt: now, Value: 1, Source: strings.Repeat("a", maxMetricSourceLen+1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := tt.metric
+			err := normalizeMetric(&metric)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUnit, metric.Unit)
+		})
+	}
+}
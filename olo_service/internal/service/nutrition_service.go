@@ -0,0 +1,210 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Types of meals in the nutrition diary.
+var mealTypes = []string{"breakfast", "lunch", "dinner", "snack"}
+
+// Limits of the nutrition diary.
+const (
+	maxMealGrams          = 5000
+	defaultFoodSearchSize = 20
+	maxFoodSearchSize     = 50
+)
+
+// NutritionService represents the service for the food catalog and the nutrition diary.
+type NutritionService struct {
+	log    *slog.Logger           // Logging
+	repo   *repository.Repository // Repository for OLO
+	events EventPublisher         // Domain events of users
+}
+
+// NewNutritionService creates a new instance of NutritionService with the provided logger, repository and event publisher.
+func NewNutritionService(log *slog.Logger, repo *repository.Repository, events EventPublisher) *NutritionService {
+	return &NutritionService{
+		log:    log,
+		repo:   repo,
+		events: events,
+	}
+}
+
+// parseDate parses a local date of a user in the YYYY-MM-DD format.
+// Dates far in the future are rejected, the local date of a user is at most a day ahead of UTC.
+func parseDate(date string) (time.Time, error) {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be in the YYYY-MM-DD format", ErrInvalidDate)
+	}
+	if t.After(time.Now().UTC().Add(24 * time.Hour)) {
+		return time.Time{}, fmt.Errorf("%w: date must not be in the future", ErrInvalidDate)
+	}
+	return t, nil
+}
+
+// SearchFoods returns the foods of the catalog whose name contains the query.
+func (s *NutritionService) SearchFoods(query string, limit int) ([]entity.Food, error) {
+	const op = "nutrition.SearchFoods"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, sl.Wrap(op, ErrEmptySearchQuery)
+	}
+	if limit <= 0 {
+		limit = defaultFoodSearchSize
+	}
+	limit = min(limit, maxFoodSearchSize)
+
+	foods, err := s.repo.SearchFoods(query, limit)
+	if err != nil {
+		log.Error("failed search foods", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't search foods"))
+	}
+	return foods, nil
+}
+
+// LogMeal adds a portion of food to the nutrition diary of the user and returns it with its nutrients.
+func (s *NutritionService) LogMeal(userId int64, meal entity.Meal) (entity.Meal, error) {
+	const op = "nutrition.LogMeal"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if _, err := parseDate(meal.Date); err != nil {
+		return entity.Meal{}, sl.Wrap(op, err)
+	}
+	switch {
+	case !slices.Contains(mealTypes, meal.MealType):
+		return entity.Meal{}, sl.Wrap(op, fmt.Errorf("%w: meal type must be one of %v", ErrInvalidMeal, mealTypes))
+	case math.IsNaN(meal.Grams) || meal.Grams <= 0 || meal.Grams > maxMealGrams:
+		return entity.Meal{}, sl.Wrap(op, fmt.Errorf("%w: grams must be more than 0 and at most %d", ErrInvalidMeal, maxMealGrams))
+	}
+
+	if _, err := s.repo.GetFood(meal.FoodID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Meal{}, sl.Wrap(op, ErrFoodNotFound)
+		}
+		log.Error("failed get food", sl.Err(err))
+		return entity.Meal{}, sl.Wrap(op, fmt.Errorf("can't log meal"))
+	}
+
+	meal.CreatedAt = time.Now().Unix()
+	mealId, err := s.repo.AddMeal(userId, meal)
+	if err != nil {
+		log.Error("failed add meal", sl.Err(err))
+		return entity.Meal{}, sl.Wrap(op, fmt.Errorf("can't log meal"))
+	}
+	s.events.Publish(userId, achievements.EventMealLogged)
+
+	meal, err = s.repo.GetMeal(userId, mealId)
+	if err != nil {
+		log.Error("failed get meal", sl.Err(err))
+		return entity.Meal{}, sl.Wrap(op, fmt.Errorf("can't get meal"))
+	}
+	return meal, nil
+}
+
+// DeleteMeal deletes a meal from the nutrition diary of the user.
+func (s *NutritionService) DeleteMeal(userId, mealId int64) error {
+	const op = "nutrition.DeleteMeal"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("mealId", mealId))
+
+	err := s.repo.DeleteMeal(userId, mealId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrMealNotFound)
+		}
+		log.Error("failed delete meal", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't delete meal"))
+	}
+	return nil
+}
+
+// GetDailyNutrition returns the meals of the user on the date with their totals.
+func (s *NutritionService) GetDailyNutrition(userId int64, date string) (entity.DailyNutrition, error) {
+	const op = "nutrition.GetDailyNutrition"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if _, err := parseDate(date); err != nil {
+		return entity.DailyNutrition{}, sl.Wrap(op, err)
+	}
+
+	meals, err := s.repo.GetMeals(userId, date)
+	if err != nil {
+		log.Error("failed get meals", sl.Err(err))
+		return entity.DailyNutrition{}, sl.Wrap(op, fmt.Errorf("can't get meals"))
+	}
+
+	nutrition := entity.DailyNutrition{Date: date, Meals: meals}
+	for _, meal := range meals {
+		nutrition.Totals = nutrition.Totals.Add(meal.Nutrients)
+	}
+	return nutrition, nil
+}
+
+// GetWeeklyNutrition returns the daily totals of the user during the week of the date, weeks start on Monday.
+func (s *NutritionService) GetWeeklyNutrition(userId int64, date string) (entity.WeeklyNutrition, error) {
+	const op = "nutrition.GetWeeklyNutrition"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	day, err := parseDate(date)
+	if err != nil {
+		return entity.WeeklyNutrition{}, sl.Wrap(op, err)
+	}
+	monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+
+	days := make([]entity.DayNutrients, 7)
+	for i := range days {
+		days[i].Date = monday.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+
+	totals, err := s.repo.GetNutritionTotals(userId, days[0].Date, days[6].Date)
+	if err != nil {
+		log.Error("failed get nutrition totals", sl.Err(err))
+		return entity.WeeklyNutrition{}, sl.Wrap(op, fmt.Errorf("can't get nutrition totals"))
+	}
+
+	week := entity.WeeklyNutrition{WeekStart: days[0].Date, Days: days}
+	for _, total := range totals {
+		i := slices.IndexFunc(days, func(d entity.DayNutrients) bool { return d.Date == total.Date })
+		if i < 0 {
+			continue
+		}
+		days[i].Nutrients = total.Nutrients
+		week.Totals = week.Totals.Add(total.Nutrients)
+	}
+	if n := float64(len(totals)); n > 0 {
+		week.Average = entity.Nutrients{
+			Calories: week.Totals.Calories / n,
+			Protein:  week.Totals.Protein / n,
+			Fat:      week.Totals.Fat / n,
+			Carbs:    week.Totals.Carbs / n,
+		}
+	}
+	return week, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nutritionRepo is an in-memory food catalog and nutrition diary of one user.
+type nutritionRepo struct {
+	repository.Nutrition
+	foods map[int64]entity.Food
+	meals []entity.Meal
+
+	searchLimit int // limit of the last food search
+}
+
+func (r *nutritionRepo) SearchFoods(_ string, limit int) ([]entity.Food, error) {
+	r.searchLimit = limit
+	return nil, nil
+}
+
+func (r *nutritionRepo) GetFood(foodId int64) (entity.Food, error) {
+	food, ok := r.foods[foodId]
+	if !ok {
+		return entity.Food{}, repository.ErrNotFound
+	}
+	return food, nil
+}
+
+func (r *nutritionRepo) AddMeal(_ int64, meal entity.Meal) (int64, error) {
+	food := r.foods[meal.FoodID]
+	portion := meal.Grams / 100
+	meal.ID = int64(len(r.meals) + 1)
+	meal.FoodName = food.Name
+	meal.Nutrients = entity.Nutrients{
+		Calories: food.Calories * portion,
+		Protein:  food.Protein * portion,
+		Fat:      food.Fat * portion,
+		Carbs:    food.Carbs * portion,
+	}
+	r.meals = append(r.meals, meal)
+	return meal.ID, nil
+}
+
+func (r *nutritionRepo) GetMeal(_, mealId int64) (entity.Meal, error) {
+	i := slices.IndexFunc(r.meals, func(meal entity.Meal) bool { return meal.ID == mealId })
+	if i < 0 {
+		return entity.Meal{}, repository.ErrNotFound
+	}
+	return r.meals[i], nil
+}
+
+func (r *nutritionRepo) GetMeals(_ int64, date string) ([]entity.Meal, error) {
+	var meals []entity.Meal
+	for _, meal := range r.meals {
+		if meal.Date == date {
+			meals = append(meals, meal)
+		}
+	}
+	return meals, nil
+}
+
+func (r *nutritionRepo) GetNutritionTotals(_ int64, first, last string) ([]entity.DayNutrients, error) {
+	var days []entity.DayNutrients
+	for _, meal := range r.meals {
+		if meal.Date < first || meal.Date > last {
+			continue
+		}
+		i := slices.IndexFunc(days, func(day entity.DayNutrients) bool { return day.Date == meal.Date })
+		if i < 0 {
+			days = append(days, entity.DayNutrients{Date: meal.Date})
+			i = len(days) - 1
+		}
+		days[i].Nutrients = days[i].Nutrients.Add(meal.Nutrients)
+	}
+	return days, nil
+}
+
+// eventRecorder is an EventPublisher that records the published events.
+type eventRecorder struct {
+	events []string
+}
+
+func (r *eventRecorder) Publish(_ int64, event string) {
+	r.events = append(r.events, event)
+}
+
+// newNutritionService returns a service with a catalog of oats and a banana.
+func newNutritionService() (*NutritionService, *nutritionRepo, *eventRecorder) {
+	repo := &nutritionRepo{foods: map[int64]entity.Food{
+		1: {ID: 1, Name: "Oats", Nutrients: entity.Nutrients{Calories: 380, Protein: 13, Fat: 7, Carbs: 60}},
+		2: {ID: 2, Name: "Banana", Nutrients: entity.Nutrients{Calories: 90, Protein: 1, Fat: 0.5, Carbs: 20}},
+	}}
+	events := &eventRecorder{}
+	return NewNutritionService(discardLogger(), &repository.Repository{Nutrition: repo}, events), repo, events
+}
+
+func TestParseDate(t *testing.T) {
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly)
+	later := time.Now().UTC().AddDate(0, 0, 3).Format(time.DateOnly)
+
+	tests := []struct {
+		date    string
+		wantErr bool
+	}{
+		{date: "2024-05-01"},
+		{date: tomorrow},
+		{date: later, wantErr: true},
+		{date: "01.05.2024", wantErr: true},
+		{date: "2024-02-30", wantErr: true},
+		{date: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			_, err := parseDate(tt.date)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidDate)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogMeal(t *testing.T) {
+	tests := []struct {
+		name    string
+		meal    entity.Meal
+		wantErr error
+	}{
+		{name: "valid", meal: entity.Meal{Date: "2024-05-01", MealType: "breakfast", FoodID: 1, Grams: 50}},
+		{name: "invalid date", meal: entity.Meal{Date: "2024/05/01", MealType: "breakfast", FoodID: 1, Grams: 50}, wantErr: ErrInvalidDate},
+		{name: "unknown meal type", meal: entity.Meal{Date: "2024-05-01", MealType: "brunch", FoodID: 1, Grams: 50}, wantErr: ErrInvalidMeal},
+		{name: "no grams", meal: entity.Meal{Date: "2024-05-01", MealType: "lunch", FoodID: 1}, wantErr: ErrInvalidMeal},
+		{name: "grams not a number", meal: entity.Meal{Date: "2024-05-01", MealType: "lunch", FoodID: 1, Grams: math.NaN()}, wantErr: ErrInvalidMeal},
+		{name: "too many grams", meal: entity.Meal{Date: "2024-05-01", MealType: "lunch", FoodID: 1, Grams: maxMealGrams + 1}, wantErr: ErrInvalidMeal},
+		{name: "unknown food", meal: entity.Meal{Date: "2024-05-01", MealType: "snack", FoodID: 3, Grams: 50}, wantErr: ErrFoodNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, events := newNutritionService()
+
+			meal, err := s.LogMeal(1, tt.meal)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, repo.meals)
+				assert.Empty(t, events.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Oats", meal.FoodName)
+			assert.Equal(t, entity.Nutrients{Calories: 190, Protein: 6.5, Fat: 3.5, Carbs: 30}, meal.Nutrients)
+			assert.NotZero(t, meal.CreatedAt)
+			assert.Equal(t, []string{achievements.EventMealLogged}, events.events)
+		})
+	}
+}
+
+func TestSearchFoodsLimit(t *testing.T) {
+	s, repo, _ := newNutritionService()
+
+	_, err := s.SearchFoods("  ", 10)
+	assert.ErrorIs(t, err, ErrEmptySearchQuery)
+
+	for limit, want := range map[int]int{0: defaultFoodSearchSize, -1: defaultFoodSearchSize, 5: 5, maxFoodSearchSize + 1: maxFoodSearchSize} {
+		_, err := s.SearchFoods("oat", limit)
+		require.NoError(t, err)
+		assert.Equal(t, want, repo.searchLimit, "limit %d", limit)
+	}
+}
+
+func TestNutritionTotals(t *testing.T) {
+	s, _, _ := newNutritionService()
+	// 1 May 2024 is a Wednesday.
+	for _, meal := range []entity.Meal{
+		{Date: "2024-04-28", MealType: "dinner", FoodID: 2, Grams: 100}, // the Sunday of the previous week
+		{Date: "2024-04-29", MealType: "breakfast", FoodID: 1, Grams: 100},
+		{Date: "2024-05-01", MealType: "breakfast", FoodID: 1, Grams: 50},
+		{Date: "2024-05-01", MealType: "snack", FoodID: 2, Grams: 200},
+		{Date: "2024-05-05", MealType: "lunch", FoodID: 2, Grams: 100},
+	} {
+		_, err := s.LogMeal(1, meal)
+		require.NoError(t, err)
+	}
+
+	daily, err := s.GetDailyNutrition(1, "2024-05-01")
+	require.NoError(t, err)
+	assert.Len(t, daily.Meals, 2)
+	assert.Equal(t, entity.Nutrients{Calories: 370, Protein: 8.5, Fat: 4.5, Carbs: 70}, daily.Totals)
+
+	empty, err := s.GetDailyNutrition(1, "2024-05-02")
+	require.NoError(t, err)
+	assert.Empty(t, empty.Meals)
+	assert.Zero(t, empty.Totals)
+
+	for _, date := range []string{"2024-04-29", "2024-05-01", "2024-05-05"} {
+		week, err := s.GetWeeklyNutrition(1, date)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-04-29", week.WeekStart, date)
+		require.Len(t, week.Days, 7)
+		assert.Equal(t, "2024-05-05", week.Days[6].Date)
+		assert.Equal(t, 380.0, week.Days[0].Calories)
+		assert.Zero(t, week.Days[1].Nutrients)
+		assert.Equal(t, 370.0, week.Days[2].Calories)
+		assert.Equal(t, 90.0, week.Days[6].Calories)
+		assert.Equal(t, 840.0, week.Totals.Calories)
+		// Only the three days with meals count towards the average.
+		assert.Equal(t, 280.0, week.Average.Calories)
+	}
+
+	week, err := s.GetWeeklyNutrition(1, "2024-05-06")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-05-06", week.WeekStart)
+	assert.Zero(t, week.Totals)
+	assert.Zero(t, week.Average)
+}
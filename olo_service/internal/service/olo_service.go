@@ -4,75 +4,305 @@
 //   - GetAllWidgets: Retrieve all widgets from the repository.
 //   - GetUserWidgets: Retrieve widgets associated with a specific user from the repository.
 //   - AddWidgetForUser: Add a widget for a specific user.
+//   - GetWidgetHistory, RestoreWidgetVersion: Browse and restore the revisions of a widget.
+//   - ReorderWidgets: Rewrite the dashboard layout of a user.
+//   - ListWidgetTypes: Retrieve the widget types with the schemas of their data.
 //   - GetAllArticles: Retrieve all articles from the repository.
 //   - GetUsersArticles: Retrieve articles associated with a specific user from the repository.
 //   - AddArticleForUser: Add an article for a specific user.
+//   - GetArticle, CreateArticle, UpdateArticle, DeleteArticle: Manage articles.
+//   - SearchArticles: Search articles by keywords.
+//   - ListCategories: Retrieve the categories of articles.
 package service
 
 import (
+	"OLO-backend/olo_service/internal/achievements"
 	"OLO-backend/olo_service/internal/entity"
 	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/olo_service/internal/search"
+	"OLO-backend/olo_service/internal/widgettype"
 	"OLO-backend/pkg/utils/logger/sl"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
+	"time"
 )
 
 // OloService represents the service for OLO operations.
 type OloService struct {
-	log  *slog.Logger           // Logging
-	repo *repository.Repository // Repository for OLO
+	log         *slog.Logger           // Logging
+	repo        *repository.Repository // Repository for OLO
+	search      search.Backend         // Full-text search of articles
+	widgetTypes *widgettype.Registry   // Types of widgets with the schemas of their data
+	events      EventPublisher         // Domain events of users
+
+	historyVersions int           // Number of the newest widget revisions kept
+	historyMaxAge   time.Duration // Age after which widget revisions are deleted
 }
 
-// NewOloService creates a new instance of OloService with the provided logger and repository.
-func NewOloService(log *slog.Logger, repo *repository.Repository) *OloService {
+// Default retention of widget revisions.
+const (
+	defaultHistoryVersions = 50
+	defaultHistoryMaxAge   = 90 * 24 * time.Hour
+)
+
+// NewOloService creates a new instance of OloService with the provided logger, repository,
+// search backend, widget types and event publisher.
+func NewOloService(log *slog.Logger, repo *repository.Repository, search search.Backend, widgetTypes *widgettype.Registry, events EventPublisher) *OloService {
 	return &OloService{
-		repo: repo,
-		log:  log,
+		repo:        repo,
+		search:      search,
+		widgetTypes: widgetTypes,
+		events:      events,
+		log:         log,
+
+		historyVersions: defaultHistoryVersions,
+		historyMaxAge:   defaultHistoryMaxAge,
+	}
+}
+
+// SetWidgetHistoryRetention sets how many revisions of a widget are kept and for how long.
+// The current version of a widget is kept regardless of the limits, non-positive limits keep the defaults.
+func (s *OloService) SetWidgetHistoryRetention(maxVersions int, maxAge time.Duration) {
+	if maxVersions > 0 {
+		s.historyVersions = maxVersions
+	}
+	if maxAge > 0 {
+		s.historyMaxAge = maxAge
 	}
 }
 
-// GetWidgets retrieves widgets associated with a specific user from the repository.
-func (s *OloService) GetWidgets(userId int64) ([]entity.Widget, error) {
+// GetWidgets retrieves widgets associated with a specific user from the repository in the layout order.
+// Calorie counters get the totals of the nutrition diary for the date of their data,
+// or for the local date of the user if their data has none, today in UTC if it is empty.
+func (s *OloService) GetWidgets(userId int64, date string) ([]entity.Widget, error) {
 	const op = "olo.GetWidgets"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("userId", userId))
 
+	if date == "" {
+		date = time.Now().UTC().Format(time.DateOnly)
+	}
+	if _, err := parseDate(date); err != nil {
+		return nil, sl.Wrap(op, err)
+	}
+
 	widgets, err := s.repo.GetWidgets(userId)
 	if err != nil {
 		log.Error("failed get widgets", sl.Err(err))
 		return nil, sl.Wrap(op, fmt.Errorf("can't get widgets"))
 	}
+	if err := s.fillNutrition(userId, date, widgets); err != nil {
+		log.Error("failed get nutrition totals", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get widgets"))
+	}
+	if err := s.fillDefaults(userId, widgets); err != nil {
+		log.Error("failed get widget defaults", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get widgets"))
+	}
 	return widgets, nil
 }
 
-// UpdateWidget adds a widget for a specific user.
-func (s *OloService) UpdateWidget(data string, widgetId, userId int64) error {
+// fillDefaults sets the defaults derived from the fitness profile of the user: the calorie and macro
+// targets of calorie counters and the goal weight of weight trackers. Users without a profile get no defaults.
+func (s *OloService) fillDefaults(userId int64, widgets []entity.Widget) error {
+	hasDefaults := slices.ContainsFunc(widgets, func(widget entity.Widget) bool {
+		return widget.Type == widgettype.CalorieCounter || widget.Type == widgettype.WeightTracker
+	})
+	if !hasDefaults {
+		return nil
+	}
+
+	profile, err := s.repo.GetProfile(userId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	calculations, err := calculateProfile(s.repo, userId, profile)
+	if err != nil {
+		return err
+	}
+
+	for i := range widgets {
+		switch {
+		case widgets[i].Type == widgettype.CalorieCounter && calculations != nil:
+			targets := calculations.Targets
+			widgets[i].Defaults = &entity.WidgetDefaults{
+				GoalKcal: math.Round(targets.Calories),
+				Macros:   &targets,
+			}
+		case widgets[i].Type == widgettype.WeightTracker && profile.GoalWeightKg != 0:
+			widgets[i].Defaults = &entity.WidgetDefaults{TargetKg: profile.GoalWeightKg}
+		}
+	}
+	return nil
+}
+
+// fillNutrition sets the nutrition totals of the calorie counters, the totals of each date are loaded once.
+func (s *OloService) fillNutrition(userId int64, date string, widgets []entity.Widget) error {
+	totals := make(map[string]entity.Nutrients)
+	for i := range widgets {
+		if widgets[i].Type != widgettype.CalorieCounter {
+			continue
+		}
+
+		// The data is validated against the schema on save, so the date is either empty or valid
+		var data struct {
+			Date string `json:"date"`
+		}
+		_ = json.Unmarshal([]byte(widgets[i].Data), &data)
+		if data.Date == "" {
+			data.Date = date
+		}
+
+		nutrients, ok := totals[data.Date]
+		if !ok {
+			days, err := s.repo.GetNutritionTotals(userId, data.Date, data.Date)
+			if err != nil {
+				return err
+			}
+			if len(days) > 0 {
+				nutrients = days[0].Nutrients
+			}
+			totals[data.Date] = nutrients
+		}
+		widgets[i].Nutrition = &nutrients
+	}
+	return nil
+}
+
+// UpdateWidget updates the data of a widget of a specific user.
+// The data is validated against the schema of the widget type. The type of a widget can't be changed,
+// except for widgets created before the widget types were introduced, which get the type on their first update.
+func (s *OloService) UpdateWidget(widget entity.Widget, userId int64) error {
 	const op = "olo.UpdateWidget"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("userId", userId),
-		slog.Int64("widgetId", widgetId))
+		slog.Int64("widgetId", widget.ID))
 
-	err := s.repo.UpdateWidget(data, widgetId, userId)
+	stored, err := s.repo.GetWidget(widget.ID, userId)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrWidgetNotFound)
+		}
+		log.Error("failed get widget", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't update widget"))
+	}
+	if widget.Type == "" {
+		widget.Type = stored.Type
+	}
+	if stored.Type != "" && widget.Type != stored.Type {
+		return sl.Wrap(op, ErrWidgetTypeChanged)
+	}
+	if err := s.validateWidget(widget); err != nil {
+		return sl.Wrap(op, err)
+	}
+
+	err = s.repo.UpdateWidget(widget, userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrWidgetNotFound)
+		}
 		log.Error("failed update widget", sl.Err(err))
 		return sl.Wrap(op, fmt.Errorf("can't update widget"))
 	}
+
+	// The update is already saved, so a failed cleanup of old revisions is only logged
+	err = s.repo.PruneWidgetRevisions(widget.ID, userId, s.historyVersions, time.Now().Add(-s.historyMaxAge))
+	if err != nil {
+		log.Error("failed prune widget revisions", sl.Err(err))
+	}
 	return nil
 }
 
+// GetWidgetHistory retrieves a page of revisions of a widget older than the version, newest first.
+// A zero beforeVersion means starting from the current version.
+func (s *OloService) GetWidgetHistory(widgetId, userId int64, beforeVersion, size int) ([]entity.WidgetRevision, error) {
+	const op = "olo.GetWidgetHistory"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("widgetId", widgetId))
+
+	if _, err := s.repo.GetWidget(widgetId, userId); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, sl.Wrap(op, ErrWidgetNotFound)
+		}
+		log.Error("failed get widget", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get widget history"))
+	}
+
+	revisions, err := s.repo.GetWidgetRevisions(widgetId, userId, beforeVersion, pageSize(size))
+	if err != nil {
+		log.Error("failed get widget revisions", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get widget history"))
+	}
+	return revisions, nil
+}
+
+// RestoreWidgetVersion restores the data of a widget from a revision and returns the widget.
+// The restored data is saved as a new version, so the history stays intact.
+func (s *OloService) RestoreWidgetVersion(widgetId int64, version int, userId int64) (entity.Widget, error) {
+	const op = "olo.RestoreWidgetVersion"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("widgetId", widgetId),
+		slog.Int("version", version))
+
+	revision, err := s.repo.GetWidgetRevision(widgetId, userId, version)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Widget{}, sl.Wrap(op, ErrRevisionNotFound)
+		}
+		log.Error("failed get widget revision", sl.Err(err))
+		return entity.Widget{}, sl.Wrap(op, fmt.Errorf("can't restore widget"))
+	}
+
+	widget := entity.Widget{
+		ID:   widgetId,
+		Type: revision.Type,
+		Data: revision.Data,
+	}
+	if err := s.UpdateWidget(widget, userId); err != nil {
+		return entity.Widget{}, sl.Wrap(op, err)
+	}
+
+	widget, err = s.repo.GetWidget(widgetId, userId)
+	if err != nil {
+		log.Error("failed get widget", sl.Err(err))
+		return entity.Widget{}, sl.Wrap(op, fmt.Errorf("can't get widget"))
+	}
+
+	log.Info("widget restored", slog.Int("newVersion", widget.Version))
+	return widget, nil
+}
+
 // AddWidget adds a widget for a specific user.
-func (s *OloService) AddWidget(data string, userId int64) (int64, error) {
+// The data is validated against the schema of the widget type.
+func (s *OloService) AddWidget(widget entity.Widget, userId int64) (int64, error) {
 	const op = "olo.AddWidget"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("userId", userId))
 
-	widgetId, err := s.repo.AddWidget(data, userId)
+	if err := s.validateWidget(widget); err != nil {
+		return 0, sl.Wrap(op, err)
+	}
+
+	widgetId, err := s.repo.AddWidget(widget, userId)
 	if err != nil {
 		log.Error("failed add widget", sl.Err(err))
 		return 0, sl.Wrap(op, fmt.Errorf("can't add widget"))
@@ -80,6 +310,89 @@ func (s *OloService) AddWidget(data string, userId int64) (int64, error) {
 	return widgetId, nil
 }
 
+// Bounds of the dashboard grid.
+const (
+	gridColumns     = 12
+	maxWidgetHeight = 12
+)
+
+// ReorderWidgets rewrites the dashboard layout of the user and returns the widgets in the new order.
+// The widgets must list every widget of the user exactly once, their order in the slice becomes their position.
+// The widgets must fit into the grid and must not overlap.
+func (s *OloService) ReorderWidgets(userId int64, widgets []entity.Widget) ([]entity.Widget, error) {
+	const op = "olo.ReorderWidgets"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	for i := range widgets {
+		if err := validateLayout(widgets[i].WidgetLayout); err != nil {
+			return nil, sl.Wrap(op, fmt.Errorf("%w: widget %d: %s", ErrInvalidLayout, widgets[i].ID, err))
+		}
+		widgets[i].Position = i
+	}
+	for i := range widgets {
+		for j := i + 1; j < len(widgets); j++ {
+			if overlaps(widgets[i].WidgetLayout, widgets[j].WidgetLayout) {
+				return nil, sl.Wrap(op, fmt.Errorf("%w: widgets %d and %d overlap", ErrInvalidLayout, widgets[i].ID, widgets[j].ID))
+			}
+		}
+	}
+
+	err := s.repo.ReorderWidgets(userId, widgets)
+	if err != nil {
+		if errors.Is(err, repository.ErrLayoutMismatch) {
+			return nil, sl.Wrap(op, fmt.Errorf("%w: %s", ErrInvalidLayout, err))
+		}
+		log.Error("failed reorder widgets", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't reorder widgets"))
+	}
+
+	result, err := s.repo.GetWidgets(userId)
+	if err != nil {
+		log.Error("failed get widgets", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get widgets"))
+	}
+	return result, nil
+}
+
+// validateLayout checks that a widget fits into the dashboard grid.
+func validateLayout(layout entity.WidgetLayout) error {
+	switch {
+	case layout.X < 0 || layout.Y < 0:
+		return errors.New("grid position must not be negative")
+	case layout.Width < 1 || layout.X+layout.Width > gridColumns:
+		return fmt.Errorf("widget must fit into %d grid columns", gridColumns)
+	case layout.Height < 1 || layout.Height > maxWidgetHeight:
+		return fmt.Errorf("height must be from 1 to %d", maxWidgetHeight)
+	}
+	return nil
+}
+
+// overlaps reports whether the grid rectangles of two widgets share a cell.
+func overlaps(a, b entity.WidgetLayout) bool {
+	return a.X < b.X+b.Width && b.X < a.X+a.Width &&
+		a.Y < b.Y+b.Height && b.Y < a.Y+a.Height
+}
+
+// validateWidget checks the data of a widget against the schema of its type.
+func (s *OloService) validateWidget(widget entity.Widget) error {
+	err := s.widgetTypes.Validate(widget.Type, widget.Data)
+	if errors.Is(err, widgettype.ErrUnknownType) {
+		return fmt.Errorf("%w: %q", ErrUnknownWidgetType, widget.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidWidgetData, err)
+	}
+	return nil
+}
+
+// ListWidgetTypes returns the widget types a user can add.
+func (s *OloService) ListWidgetTypes() []widgettype.Type {
+	return s.widgetTypes.Types()
+}
+
 // DeleteWidgetForUser delete a widget for a specific user.
 func (s *OloService) DeleteWidgetForUser(widgetId, userId int64) error {
 	const op = "olo.DeleteWidgetForUser"
@@ -96,35 +409,69 @@ func (s *OloService) DeleteWidgetForUser(widgetId, userId int64) error {
 	return nil
 }
 
-// GetAllArticles retrieves all articles from the repository.
-func (s *OloService) GetAllArticles() ([]entity.Article, error) {
+// GetAllArticles retrieves a page of all articles from the repository.
+// It returns the token of the next page, which is empty on the last page.
+func (s *OloService) GetAllArticles(query entity.ArticleQuery) ([]entity.Article, string, error) {
 	const op = "olo.GetAllArticles"
 
 	log := s.log.With(
 		slog.String("op", op))
 
-	articles, err := s.repo.GetAllArticles()
+	articles, nextPageToken, err := pageArticles(query, s.repo.GetAllArticles)
 	if err != nil {
+		if errors.Is(err, ErrInvalidPageToken) {
+			return nil, "", sl.Wrap(op, err)
+		}
 		log.Error("failed get articles", sl.Err(err))
-		return nil, sl.Wrap(op, fmt.Errorf("can't get all articles"))
+		return nil, "", sl.Wrap(op, fmt.Errorf("can't get all articles"))
 	}
-	return articles, nil
+	return articles, nextPageToken, nil
 }
 
-// GetUsersArticles retrieves articles associated with a specific user from the repository.
-func (s *OloService) GetUsersArticles(userId int64) ([]entity.Article, error) {
+// GetUsersArticles retrieves a page of articles associated with a specific user from the repository.
+// It returns the token of the next page, which is empty on the last page.
+func (s *OloService) GetUsersArticles(userId int64, query entity.ArticleQuery) ([]entity.Article, string, error) {
 	const op = "olo.GetUsersArticles"
 
 	log := s.log.With(
 		slog.String("op", op),
 		slog.Int64("userId", userId))
 
-	articles, err := s.repo.GetUsersArticles(userId)
+	articles, nextPageToken, err := pageArticles(query, func(query entity.ArticleQuery, after *entity.ArticleCursor) ([]entity.Article, error) {
+		return s.repo.GetUsersArticles(userId, query, after)
+	})
 	if err != nil {
+		if errors.Is(err, ErrInvalidPageToken) {
+			return nil, "", sl.Wrap(op, err)
+		}
 		log.Error("failed get articles of user", sl.Err(err))
-		return nil, sl.Wrap(op, fmt.Errorf("can't get articles of user"))
+		return nil, "", sl.Wrap(op, fmt.Errorf("can't get articles of user"))
+	}
+	return articles, nextPageToken, nil
+}
+
+// pageArticles fetches a page of articles and the token of the next page.
+// One article more than the page size is fetched to find out whether the next page exists.
+func pageArticles(query entity.ArticleQuery, fetch func(entity.ArticleQuery, *entity.ArticleCursor) ([]entity.Article, error)) ([]entity.Article, string, error) {
+	after, err := decodeArticlePageToken(query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	size := pageSize(query.PageSize)
+	fetchQuery := query
+	fetchQuery.PageSize = size + 1
+
+	articles, err := fetch(fetchQuery, after)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(articles) <= size {
+		return articles, "", nil
 	}
-	return articles, nil
+	articles = articles[:size]
+	return articles, encodeArticlePageToken(query, articles[size-1]), nil
 }
 
 // AddArticleForUser adds an article for a specific user.
@@ -141,6 +488,7 @@ func (s *OloService) AddArticleForUser(articleId, userId int64) error {
 		log.Error("failed add article of user", sl.Err(err))
 		return sl.Wrap(op, fmt.Errorf("can't add article for user"))
 	}
+	s.events.Publish(userId, achievements.EventArticleSaved)
 	return nil
 }
 
@@ -159,3 +507,153 @@ func (s *OloService) DeleteArticleForUser(articleId int64, userId int64) error {
 	}
 	return nil
 }
+
+// GetArticle retrieves an article by its ID.
+func (s *OloService) GetArticle(articleId int64) (entity.Article, error) {
+	const op = "olo.GetArticle"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("articleId", articleId))
+
+	article, err := s.repo.GetArticle(articleId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Article{}, sl.Wrap(op, ErrArticleNotFound)
+		}
+		log.Error("failed get article", sl.Err(err))
+		return entity.Article{}, sl.Wrap(op, fmt.Errorf("can't get article"))
+	}
+	return article, nil
+}
+
+// CreateArticle creates a new article and returns it.
+func (s *OloService) CreateArticle(article entity.Article) (entity.Article, error) {
+	const op = "olo.CreateArticle"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	if err := s.checkCategory(article.CategoryID); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return entity.Article{}, sl.Wrap(op, err)
+		}
+		log.Error("failed get category", sl.Err(err))
+		return entity.Article{}, sl.Wrap(op, fmt.Errorf("can't create article"))
+	}
+
+	articleId, err := s.repo.CreateArticle(article)
+	if err != nil {
+		log.Error("failed create article", sl.Err(err))
+		return entity.Article{}, sl.Wrap(op, fmt.Errorf("can't create article"))
+	}
+	article.ID = articleId
+	if err := s.search.Index(article); err != nil {
+		log.Error("failed index article", sl.Err(err))
+	}
+
+	log.Info("article created", slog.Int64("articleId", articleId))
+	return article, nil
+}
+
+// UpdateArticle updates the header, body, category and tags of an article.
+func (s *OloService) UpdateArticle(article entity.Article) error {
+	const op = "olo.UpdateArticle"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("articleId", article.ID))
+
+	if err := s.checkCategory(article.CategoryID); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return sl.Wrap(op, err)
+		}
+		log.Error("failed get category", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't update article"))
+	}
+
+	err := s.repo.UpdateArticle(article)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrArticleNotFound)
+		}
+		log.Error("failed update article", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't update article"))
+	}
+	if err := s.search.Index(article); err != nil {
+		log.Error("failed index article", sl.Err(err))
+	}
+
+	log.Info("article updated")
+	return nil
+}
+
+// DeleteArticle deletes an article, it is removed from the saved lists of all users as well.
+func (s *OloService) DeleteArticle(articleId int64) error {
+	const op = "olo.DeleteArticle"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("articleId", articleId))
+
+	err := s.repo.DeleteArticle(articleId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrArticleNotFound)
+		}
+		log.Error("failed delete article", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't delete article"))
+	}
+	if err := s.search.Remove(articleId); err != nil {
+		log.Error("failed remove article from search index", sl.Err(err))
+	}
+
+	log.Info("article deleted")
+	return nil
+}
+
+// SearchArticles returns the articles matching the query ordered by relevance.
+func (s *OloService) SearchArticles(query string, limit int) ([]entity.ArticleSearchHit, error) {
+	const op = "olo.SearchArticles"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	if len(search.Analyze(query)) == 0 {
+		return nil, sl.Wrap(op, ErrEmptySearchQuery)
+	}
+
+	hits, err := s.search.Search(query, pageSize(limit))
+	if err != nil {
+		log.Error("failed search articles", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't search articles"))
+	}
+	return hits, nil
+}
+
+// ListCategories retrieves all categories of articles.
+func (s *OloService) ListCategories() ([]entity.Category, error) {
+	const op = "olo.ListCategories"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	categories, err := s.repo.ListCategories()
+	if err != nil {
+		log.Error("failed get categories", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get categories"))
+	}
+	return categories, nil
+}
+
+// checkCategory checks that the category of an article exists, 0 means no category.
+func (s *OloService) checkCategory(categoryId int64) error {
+	if categoryId == 0 {
+		return nil
+	}
+	_, err := s.repo.GetCategory(categoryId)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrCategoryNotFound
+	}
+	return err
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/config"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/olo_service/internal/search"
+	"OLO-backend/olo_service/internal/widgettype"
+	"OLO-backend/pkg/model"
+	"OLO-backend/pkg/utils/policy"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// discardLogger returns a logger that drops the records.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// widgetRepo is an in-memory repository of the widgets of one user that records the pruning of revisions.
+type widgetRepo struct {
+	repository.Widget
+	widgets map[int64]entity.Widget
+
+	pruned       int // number of prunes
+	keepVersions int
+	before       time.Time
+}
+
+func (r *widgetRepo) GetWidget(widgetId, _ int64) (entity.Widget, error) {
+	widget, ok := r.widgets[widgetId]
+	if !ok {
+		return entity.Widget{}, repository.ErrNotFound
+	}
+	return widget, nil
+}
+
+func (r *widgetRepo) UpdateWidget(widget entity.Widget, _ int64) error {
+	r.widgets[widget.ID] = widget
+	return nil
+}
+
+func (r *widgetRepo) PruneWidgetRevisions(_, _ int64, keepVersions int, before time.Time) error {
+	r.pruned++
+	r.keepVersions, r.before = keepVersions, before
+	return nil
+}
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  entity.WidgetLayout
+		wantErr bool
+	}{
+		{name: "single cell", layout: entity.WidgetLayout{Width: 1, Height: 1}},
+		{name: "full row", layout: entity.WidgetLayout{Width: gridColumns, Height: 2}},
+		{name: "last column", layout: entity.WidgetLayout{X: gridColumns - 1, Y: 40, Width: 1, Height: maxWidgetHeight}},
+		{name: "negative x", layout: entity.WidgetLayout{X: -1, Width: 1, Height: 1}, wantErr: true},
+		{name: "negative y", layout: entity.WidgetLayout{Y: -1, Width: 1, Height: 1}, wantErr: true},
+		{name: "zero width", layout: entity.WidgetLayout{Height: 1}, wantErr: true},
+		{name: "wider than the grid", layout: entity.WidgetLayout{Width: gridColumns + 1, Height: 1}, wantErr: true},
+		{name: "past the last column", layout: entity.WidgetLayout{X: 8, Width: 5, Height: 1}, wantErr: true},
+		{name: "zero height", layout: entity.WidgetLayout{Width: 1}, wantErr: true},
+		{name: "too high", layout: entity.WidgetLayout{Width: 1, Height: maxWidgetHeight + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateLayout(tt.layout)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReorderWidgetsInvalidLayout(t *testing.T) {
+	s := &OloService{log: discardLogger()}
+
+	// The layouts are checked before the widgets of the user are locked.
+	_, err := s.ReorderWidgets(1, []entity.Widget{
+		{ID: 1, WidgetLayout: entity.WidgetLayout{Width: 6, Height: 1}},
+		{ID: 2, WidgetLayout: entity.WidgetLayout{X: 6, Width: 7, Height: 1}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidLayout)
+}
+
+func TestOverlaps(t *testing.T) {
+	widget := entity.WidgetLayout{X: 2, Y: 2, Width: 4, Height: 2}
+
+	tests := []struct {
+		name  string
+		other entity.WidgetLayout
+		want  bool
+	}{
+		{name: "same cells", other: widget, want: true},
+		{name: "inside", other: entity.WidgetLayout{X: 3, Y: 3, Width: 1, Height: 1}, want: true},
+		{name: "corner", other: entity.WidgetLayout{X: 5, Y: 3, Width: 2, Height: 2}, want: true},
+		{name: "left", other: entity.WidgetLayout{X: 0, Y: 2, Width: 2, Height: 2}},
+		{name: "right", other: entity.WidgetLayout{X: 6, Y: 2, Width: 2, Height: 2}},
+		{name: "above", other: entity.WidgetLayout{X: 2, Y: 0, Width: 4, Height: 2}},
+		{name: "below", other: entity.WidgetLayout{X: 2, Y: 4, Width: 4, Height: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, overlaps(widget, tt.other))
+			assert.Equal(t, tt.want, overlaps(tt.other, widget))
+		})
+	}
+}
+
+func TestReorderWidgetsOverlap(t *testing.T) {
+	s := &OloService{log: discardLogger()}
+
+	_, err := s.ReorderWidgets(1, []entity.Widget{
+		{ID: 1, WidgetLayout: entity.WidgetLayout{Width: 6, Height: 2}},
+		{ID: 2, WidgetLayout: entity.WidgetLayout{X: 6, Width: 6, Height: 1}},
+		{ID: 3, WidgetLayout: entity.WidgetLayout{X: 4, Y: 1, Width: 4, Height: 1}},
+	})
+	assert.ErrorIs(t, err, ErrInvalidLayout)
+}
+
+func TestWidgetHistoryRetention(t *testing.T) {
+	widgetTypes, err := widgettype.NewRegistry()
+	require.NoError(t, err)
+	widget := entity.Widget{ID: 1, Type: widgettype.WaterIntake, Data: `{"goal_ml": 2000}`}
+
+	tests := []struct {
+		name        string
+		maxVersions int
+		maxAge      time.Duration
+		wantKeep    int
+		wantAge     time.Duration
+	}{
+		{name: "defaults", wantKeep: defaultHistoryVersions, wantAge: defaultHistoryMaxAge},
+		{name: "configured", maxVersions: 5, maxAge: time.Hour, wantKeep: 5, wantAge: time.Hour},
+		{name: "negative limits keep the defaults", maxVersions: -1, maxAge: -time.Hour, wantKeep: defaultHistoryVersions, wantAge: defaultHistoryMaxAge},
+		{name: "only versions", maxVersions: 3, wantKeep: 3, wantAge: defaultHistoryMaxAge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &widgetRepo{widgets: map[int64]entity.Widget{widget.ID: widget}}
+			s := NewOloService(discardLogger(), &repository.Repository{Widget: repo}, nil, widgetTypes, nil)
+			s.SetWidgetHistoryRetention(tt.maxVersions, tt.maxAge)
+
+			start := time.Now()
+			require.NoError(t, s.UpdateWidget(entity.Widget{ID: widget.ID, Data: `{"goal_ml": 2500}`}, 1))
+			assert.Equal(t, 1, repo.pruned)
+			assert.Equal(t, tt.wantKeep, repo.keepVersions)
+			assert.WithinRange(t, repo.before, start.Add(-tt.wantAge), time.Now().Add(-tt.wantAge))
+		})
+	}
+}
+
+func TestUpdateWidgetNotPrunedOnError(t *testing.T) {
+	widgetTypes, err := widgettype.NewRegistry()
+	require.NoError(t, err)
+	repo := &widgetRepo{widgets: map[int64]entity.Widget{
+		1: {ID: 1, Type: widgettype.WaterIntake, Data: `{"goal_ml": 2000}`},
+	}}
+	s := NewOloService(discardLogger(), &repository.Repository{Widget: repo}, nil, widgetTypes, nil)
+
+	err = s.UpdateWidget(entity.Widget{ID: 1, Data: `{"goal_ml": 0}`}, 1)
+	assert.ErrorIs(t, err, ErrInvalidWidgetData)
+	err = s.UpdateWidget(entity.Widget{ID: 1, Type: widgettype.StepCounter, Data: `{"goal": 100}`}, 1)
+	assert.ErrorIs(t, err, ErrWidgetTypeChanged)
+	err = s.UpdateWidget(entity.Widget{ID: 2, Data: `{"goal_ml": 2000}`}, 1)
+	assert.ErrorIs(t, err, ErrWidgetNotFound)
+	assert.Zero(t, repo.pruned)
+}
+
+// articleRepo is an in-memory repository of the articles and their categories.
+type articleRepo struct {
+	repository.Article
+	repository.Category
+	articles   map[int64]entity.Article
+	categories map[int64]entity.Category
+	nextId     int64
+}
+
+func newArticleRepo() *articleRepo {
+	return &articleRepo{
+		articles:   map[int64]entity.Article{1: {ID: 1, Header: "Squats", Body: "Keep the back straight"}},
+		categories: map[int64]entity.Category{3: {ID: 3, Name: "Strength"}},
+		nextId:     2,
+	}
+}
+
+func (r *articleRepo) GetCategory(categoryId int64) (entity.Category, error) {
+	category, ok := r.categories[categoryId]
+	if !ok {
+		return entity.Category{}, repository.ErrNotFound
+	}
+	return category, nil
+}
+
+func (r *articleRepo) CreateArticle(article entity.Article) (int64, error) {
+	article.ID = r.nextId
+	r.nextId++
+	r.articles[article.ID] = article
+	return article.ID, nil
+}
+
+func (r *articleRepo) UpdateArticle(article entity.Article) error {
+	if _, ok := r.articles[article.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.articles[article.ID] = article
+	return nil
+}
+
+func (r *articleRepo) DeleteArticle(articleId int64) error {
+	if _, ok := r.articles[articleId]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.articles, articleId)
+	return nil
+}
+
+func TestCreateArticle(t *testing.T) {
+	tests := []struct {
+		name    string
+		article entity.Article
+		wantErr error
+	}{
+		{name: "without category", article: entity.Article{Header: "Deadlifts"}},
+		{name: "with category", article: entity.Article{Header: "Deadlifts", CategoryID: 3, Tags: []string{"back"}}},
+		{name: "unknown category", article: entity.Article{Header: "Deadlifts", CategoryID: 4}, wantErr: ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newArticleRepo()
+			index := search.NewMemoryBackend()
+			s := NewOloService(discardLogger(), &repository.Repository{Article: repo, Category: repo}, index, nil, nil)
+
+			article, err := s.CreateArticle(tt.article)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Len(t, repo.articles, 1)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), article.ID)
+			stored := tt.article
+			stored.ID = 2
+			assert.Equal(t, stored, repo.articles[2])
+
+			hits, err := index.Search("deadlifts", 10)
+			require.NoError(t, err)
+			require.Len(t, hits, 1)
+			assert.Equal(t, int64(2), hits[0].Article.ID)
+		})
+	}
+}
+
+func TestUpdateArticle(t *testing.T) {
+	tests := []struct {
+		name    string
+		article entity.Article
+		wantErr error
+	}{
+		{name: "existing", article: entity.Article{ID: 1, Header: "Front squats", CategoryID: 3}},
+		{name: "missing", article: entity.Article{ID: 5, Header: "Front squats"}, wantErr: ErrArticleNotFound},
+		{name: "unknown category", article: entity.Article{ID: 1, Header: "Front squats", CategoryID: 4}, wantErr: ErrCategoryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newArticleRepo()
+			s := NewOloService(discardLogger(), &repository.Repository{Article: repo, Category: repo}, search.NewMemoryBackend(), nil, nil)
+
+			err := s.UpdateArticle(tt.article)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, "Squats", repo.articles[1].Header)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.article, repo.articles[1])
+		})
+	}
+}
+
+func TestDeleteArticle(t *testing.T) {
+	repo := newArticleRepo()
+	index := search.NewMemoryBackend()
+	require.NoError(t, index.Index(repo.articles[1]))
+	s := NewOloService(discardLogger(), &repository.Repository{Article: repo, Category: repo}, index, nil, nil)
+
+	require.NoError(t, s.DeleteArticle(1))
+	assert.Empty(t, repo.articles)
+	hits, err := index.Search("squats", 10)
+	require.NoError(t, err)
+	assert.Empty(t, hits)
+
+	assert.ErrorIs(t, s.DeleteArticle(1), ErrArticleNotFound)
+}
+
+func TestArticlePolicy(t *testing.T) {
+	methods := []string{"/proto.OLO/CreateArticle", "/proto.OLO/UpdateArticle", "/proto.OLO/DeleteArticle"}
+
+	for _, path := range []string{"../../resourse/dev/config.yml", "../../resourse/local/config.yml"} {
+		t.Run(path, func(t *testing.T) {
+			var cfg config.Config
+			require.NoError(t, cleanenv.ReadConfig(path, &cfg))
+			enforcer, err := policy.NewEnforcer(cfg.Policy)
+			require.NoError(t, err)
+
+			for _, method := range methods {
+				assert.NoError(t, enforcer.Authorize(method, model.RoleAdmin), method)
+				assert.ErrorIs(t, enforcer.Authorize(method, model.RoleUser), policy.ErrPermissionDenied, method)
+				assert.ErrorIs(t, enforcer.Authorize(method, model.RoleService), policy.ErrPermissionDenied, method)
+			}
+		})
+	}
+}
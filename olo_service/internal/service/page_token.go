@@ -0,0 +1,121 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// Page sizes of listings.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// articlePageToken represents the content of an opaque page token of an article listing.
+// It remembers the sort order and filters of the listing, so a token can't be reused with other ones.
+type articlePageToken struct {
+	Sort         entity.ArticleSort   `json:"s"`
+	HeaderFilter string               `json:"f,omitempty"`
+	Tag          string               `json:"t,omitempty"`
+	CategoryID   int64                `json:"c,omitempty"`
+	After        entity.ArticleCursor `json:"a"`
+}
+
+// pageSize returns the page size of the listing limited by maxPageSize.
+func pageSize(size int) int {
+	if size <= 0 {
+		return defaultPageSize
+	}
+	if size > maxPageSize {
+		return maxPageSize
+	}
+	return size
+}
+
+// encodeArticlePageToken returns the page token of the page starting after the article.
+func encodeArticlePageToken(query entity.ArticleQuery, last entity.Article) string {
+	token := articlePageToken{
+		Sort:         query.Sort,
+		HeaderFilter: query.HeaderFilter,
+		Tag:          query.Tag,
+		CategoryID:   query.CategoryID,
+		After: entity.ArticleCursor{
+			ID: last.ID,
+		},
+	}
+	if query.Sort == entity.ArticleSortHeaderAsc || query.Sort == entity.ArticleSortHeaderDesc {
+		token.After.Header = last.Header
+	}
+
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeArticlePageToken returns the cursor the page starts after.
+// An empty token means the first page.
+func decodeArticlePageToken(query entity.ArticleQuery) (*entity.ArticleCursor, error) {
+	if query.PageToken == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(query.PageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	var token articlePageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	if token.Sort != query.Sort || token.HeaderFilter != query.HeaderFilter ||
+		token.Tag != query.Tag || token.CategoryID != query.CategoryID {
+		return nil, ErrInvalidPageToken
+	}
+	return &token.After, nil
+}
+
+// workoutPageToken represents the content of an opaque page token of the workout history.
+// It remembers the time range of the listing, so a token can't be reused with another one.
+type workoutPageToken struct {
+	From  int64                `json:"f,omitempty"`
+	To    int64                `json:"t,omitempty"`
+	After entity.WorkoutCursor `json:"a"`
+}
+
+// encodeWorkoutPageToken returns the page token of the page starting after the workout.
+func encodeWorkoutPageToken(from, to int64, last entity.Workout) string {
+	token := workoutPageToken{
+		From: from,
+		To:   to,
+		After: entity.WorkoutCursor{
+			StartedAt: last.StartedAt,
+			ID:        last.ID,
+		},
+	}
+
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeWorkoutPageToken returns the cursor the page starts after.
+// An empty token means the first page.
+func decodeWorkoutPageToken(pageToken string, from, to int64) (*entity.WorkoutCursor, error) {
+	if pageToken == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	var token workoutPageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	if token.From != from || token.To != to {
+		return nil, ErrInvalidPageToken
+	}
+	return &token.After, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageSize(t *testing.T) {
+	assert.Equal(t, defaultPageSize, pageSize(0))
+	assert.Equal(t, defaultPageSize, pageSize(-1))
+	assert.Equal(t, 5, pageSize(5))
+	assert.Equal(t, maxPageSize, pageSize(maxPageSize+1))
+}
+
+func TestArticlePageToken(t *testing.T) {
+	query := entity.ArticleQuery{Sort: entity.ArticleSortHeaderAsc, HeaderFilter: "squat", Tag: "legs", CategoryID: 3}
+	token := encodeArticlePageToken(query, entity.Article{ID: 7, Header: "Squats"})
+
+	tests := []struct {
+		name    string
+		change  func(q *entity.ArticleQuery)
+		wantErr bool
+	}{
+		{name: "same listing", change: func(q *entity.ArticleQuery) {}},
+		{name: "page size changed", change: func(q *entity.ArticleQuery) { q.PageSize = 50 }},
+		{name: "sort changed", change: func(q *entity.ArticleQuery) { q.Sort = entity.ArticleSortHeaderDesc }, wantErr: true},
+		{name: "filter changed", change: func(q *entity.ArticleQuery) { q.HeaderFilter = "press" }, wantErr: true},
+		{name: "tag changed", change: func(q *entity.ArticleQuery) { q.Tag = "" }, wantErr: true},
+		{name: "category changed", change: func(q *entity.ArticleQuery) { q.CategoryID = 4 }, wantErr: true},
+		{name: "malformed token", change: func(q *entity.ArticleQuery) { q.PageToken = "not a token" }, wantErr: true},
+		{name: "not json", change: func(q *entity.ArticleQuery) { q.PageToken = "bm90IGpzb24" }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := query
+			q.PageToken = token
+			tt.change(&q)
+
+			after, err := decodeArticlePageToken(q)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPageToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &entity.ArticleCursor{ID: 7, Header: "Squats"}, after)
+		})
+	}
+}
+
+func TestArticlePageTokenCursor(t *testing.T) {
+	// Listings sorted by id don't need the header in the cursor.
+	query := entity.ArticleQuery{Sort: entity.ArticleSortIDDesc}
+	query.PageToken = encodeArticlePageToken(query, entity.Article{ID: 7, Header: "Squats"})
+	after, err := decodeArticlePageToken(query)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.ArticleCursor{ID: 7}, after)
+
+	after, err = decodeArticlePageToken(entity.ArticleQuery{})
+	require.NoError(t, err)
+	assert.Nil(t, after)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/calculator"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+)
+
+// Values of the profile fields.
+var (
+	sexes       = []string{calculator.SexMale, calculator.SexFemale}
+	unitSystems = []string{"metric", "imperial"}
+)
+
+// Limits of the profile fields, the weights match the limits of the weight tracker widget.
+const (
+	minHeightCm  = 50
+	maxHeightCm  = 272
+	minAge       = 13
+	maxAge       = 120
+	minWeightKg  = 20
+	maxWeightKg  = 500
+	defaultUnits = "metric"
+)
+
+// ProfileService represents the service for fitness profiles of users.
+type ProfileService struct {
+	log  *slog.Logger           // Logging
+	repo *repository.Repository // Repository for OLO
+}
+
+// NewProfileService creates a new instance of ProfileService with the provided logger and repository.
+func NewProfileService(log *slog.Logger, repo *repository.Repository) *ProfileService {
+	return &ProfileService{
+		log:  log,
+		repo: repo,
+	}
+}
+
+// GetProfile returns the fitness profile of the user with its calculations.
+// The calculations are nil until the user records a weight metric.
+func (s *ProfileService) GetProfile(userId int64) (entity.Profile, *entity.ProfileCalculations, error) {
+	const op = "profile.GetProfile"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	profile, err := s.repo.GetProfile(userId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Profile{}, nil, sl.Wrap(op, ErrProfileNotFound)
+		}
+		log.Error("failed get profile", sl.Err(err))
+		return entity.Profile{}, nil, sl.Wrap(op, fmt.Errorf("can't get profile"))
+	}
+
+	calculations, err := calculateProfile(s.repo, userId, profile)
+	if err != nil {
+		log.Error("failed calculate profile", sl.Err(err))
+		return entity.Profile{}, nil, sl.Wrap(op, fmt.Errorf("can't calculate profile"))
+	}
+	return profile, calculations, nil
+}
+
+// UpdateProfile creates or replaces the fitness profile of the user and returns it with its calculations.
+func (s *ProfileService) UpdateProfile(userId int64, profile entity.Profile) (entity.Profile, *entity.ProfileCalculations, error) {
+	const op = "profile.UpdateProfile"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if profile.Units == "" {
+		profile.Units = defaultUnits
+	}
+	if err := validateProfile(profile); err != nil {
+		return entity.Profile{}, nil, sl.Wrap(op, err)
+	}
+
+	profile.UpdatedAt = time.Now().Unix()
+	if err := s.repo.SaveProfile(userId, profile); err != nil {
+		log.Error("failed save profile", sl.Err(err))
+		return entity.Profile{}, nil, sl.Wrap(op, fmt.Errorf("can't save profile"))
+	}
+
+	calculations, err := calculateProfile(s.repo, userId, profile)
+	if err != nil {
+		log.Error("failed calculate profile", sl.Err(err))
+		return entity.Profile{}, nil, sl.Wrap(op, fmt.Errorf("can't calculate profile"))
+	}
+	return profile, calculations, nil
+}
+
+// validateProfile checks the profile fields, invalid profiles are reported with ErrInvalidProfile.
+func validateProfile(profile entity.Profile) error {
+	birthDate, err := time.Parse(time.DateOnly, profile.BirthDate)
+	if err != nil {
+		return fmt.Errorf("%w: birth date must be in the YYYY-MM-DD format", ErrInvalidProfile)
+	}
+	age := calculator.Age(birthDate, time.Now().UTC())
+
+	switch {
+	case math.IsNaN(profile.HeightCm) || profile.HeightCm < minHeightCm || profile.HeightCm > maxHeightCm:
+		return fmt.Errorf("%w: height must be from %d to %d cm", ErrInvalidProfile, minHeightCm, maxHeightCm)
+	case age < minAge || age > maxAge:
+		return fmt.Errorf("%w: age must be from %d to %d years", ErrInvalidProfile, minAge, maxAge)
+	case !slices.Contains(sexes, profile.Sex):
+		return fmt.Errorf("%w: sex must be one of %v", ErrInvalidProfile, sexes)
+	case calculator.ActivityFactors[profile.ActivityLevel] == 0:
+		return fmt.Errorf("%w: unknown activity level %q", ErrInvalidProfile, profile.ActivityLevel)
+	case !slices.Contains(unitSystems, profile.Units):
+		return fmt.Errorf("%w: units must be one of %v", ErrInvalidProfile, unitSystems)
+	case math.IsNaN(profile.GoalWeightKg) || profile.GoalWeightKg != 0 && (profile.GoalWeightKg < minWeightKg || profile.GoalWeightKg > maxWeightKg):
+		return fmt.Errorf("%w: goal weight must be from %d to %d kg", ErrInvalidProfile, minWeightKg, maxWeightKg)
+	}
+	return nil
+}
+
+// validWeight reports whether the weight in kg is in the range the calculations accept.
+func validWeight(kg float64) bool {
+	return kg >= minWeightKg && kg <= maxWeightKg
+}
+
+// calculateProfile derives BMI, BMR, TDEE and macro targets from the profile and the latest weight metric of the user.
+// It returns nil if the user has no weight metric or its value is out of the valid range.
+func calculateProfile(repo *repository.Repository, userId int64, profile entity.Profile) (*entity.ProfileCalculations, error) {
+	weight, err := repo.GetLatestMetric(userId, "weight")
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !validWeight(weight.Value) {
+		return nil, nil
+	}
+	birthDate, err := time.Parse(time.DateOnly, profile.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+
+	age := calculator.Age(birthDate, time.Now().UTC())
+	bmi := calculator.BMI(weight.Value, profile.HeightCm)
+	bmr := calculator.BMR(weight.Value, profile.HeightCm, age, profile.Sex)
+	tdee := calculator.TDEE(bmr, profile.ActivityLevel)
+	return &entity.ProfileCalculations{
+		WeightKg:    weight.Value,
+		BMI:         bmi,
+		BMICategory: calculator.BMICategory(bmi),
+		BMR:         bmr,
+		TDEE:        tdee,
+		Targets:     calculator.Targets(tdee, weight.Value, profile.GoalWeightKg, profile.Sex),
+	}, nil
+}
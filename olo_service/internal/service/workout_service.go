@@ -0,0 +1,302 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/achievements"
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"OLO-backend/pkg/utils/logger/sl"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+// Muscle groups and kinds of exercises in the catalog.
+var (
+	muscleGroups  = []string{"chest", "back", "legs", "shoulders", "arms", "core", "cardio"}
+	exerciseKinds = []string{"strength", "cardio"}
+)
+
+// Limits of workouts, the lengths are the sizes of the table columns.
+const (
+	maxWorkoutNameLength  = 100
+	maxWorkoutNotesLength = 1000
+	maxExerciseNameLength = 100
+	maxSetsInWorkout      = 100
+	maxReps               = 1000
+	maxSetWeightKg        = 1000
+	maxSetDurationSec     = 24 * 60 * 60
+	maxSetDistanceM       = 1_000_000
+)
+
+// WorkoutService represents the service for workouts and the exercise catalog.
+type WorkoutService struct {
+	log       *slog.Logger           // Logging
+	repo      *repository.Repository // Repository for OLO
+	analytics *AnalyticsService      // Personal records of saved workouts
+	events    EventPublisher         // Domain events of users
+}
+
+// NewWorkoutService creates a new instance of WorkoutService with the provided logger, repository, analytics and event publisher.
+func NewWorkoutService(log *slog.Logger, repo *repository.Repository, analytics *AnalyticsService, events EventPublisher) *WorkoutService {
+	return &WorkoutService{
+		log:       log,
+		repo:      repo,
+		analytics: analytics,
+		events:    events,
+	}
+}
+
+// ListExercises retrieves the exercises of the catalog, only of the muscle group if it is set.
+func (s *WorkoutService) ListExercises(muscleGroup string) ([]entity.Exercise, error) {
+	const op = "workouts.ListExercises"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	if muscleGroup != "" && !slices.Contains(muscleGroups, muscleGroup) {
+		return nil, sl.Wrap(op, fmt.Errorf("%w: unknown muscle group %q", ErrInvalidExercise, muscleGroup))
+	}
+
+	exercises, err := s.repo.ListExercises(muscleGroup)
+	if err != nil {
+		log.Error("failed get exercises", sl.Err(err))
+		return nil, sl.Wrap(op, fmt.Errorf("can't get exercises"))
+	}
+	return exercises, nil
+}
+
+// CreateExercise adds an exercise to the catalog and returns it.
+func (s *WorkoutService) CreateExercise(exercise entity.Exercise) (entity.Exercise, error) {
+	const op = "workouts.CreateExercise"
+
+	log := s.log.With(
+		slog.String("op", op))
+
+	switch {
+	case exercise.Name == "" || utf8.RuneCountInString(exercise.Name) > maxExerciseNameLength:
+		return entity.Exercise{}, sl.Wrap(op, fmt.Errorf("%w: name must be from 1 to %d characters", ErrInvalidExercise, maxExerciseNameLength))
+	case !slices.Contains(muscleGroups, exercise.MuscleGroup):
+		return entity.Exercise{}, sl.Wrap(op, fmt.Errorf("%w: muscle group must be one of %v", ErrInvalidExercise, muscleGroups))
+	case !slices.Contains(exerciseKinds, exercise.Kind):
+		return entity.Exercise{}, sl.Wrap(op, fmt.Errorf("%w: kind must be one of %v", ErrInvalidExercise, exerciseKinds))
+	}
+
+	exerciseId, err := s.repo.CreateExercise(exercise)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return entity.Exercise{}, sl.Wrap(op, ErrExerciseExists)
+		}
+		log.Error("failed create exercise", sl.Err(err))
+		return entity.Exercise{}, sl.Wrap(op, fmt.Errorf("can't create exercise"))
+	}
+	exercise.ID = exerciseId
+
+	log.Info("exercise created", slog.Int64("exerciseId", exerciseId))
+	return exercise, nil
+}
+
+// CreateWorkout saves a workout of the user and returns it with the personal records it sets.
+func (s *WorkoutService) CreateWorkout(userId int64, workout entity.Workout) (entity.Workout, []entity.PersonalRecord, error) {
+	const op = "workouts.CreateWorkout"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	if err := s.validateWorkout(workout); err != nil {
+		if errors.Is(err, ErrInvalidWorkout) {
+			return entity.Workout{}, nil, sl.Wrap(op, err)
+		}
+		log.Error("failed validate workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't create workout"))
+	}
+
+	workoutId, err := s.repo.CreateWorkout(userId, workout)
+	if err != nil {
+		log.Error("failed create workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't create workout"))
+	}
+
+	workout, err = s.repo.GetWorkout(userId, workoutId)
+	if err != nil {
+		log.Error("failed get workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't get workout"))
+	}
+	s.events.Publish(userId, achievements.EventWorkoutLogged)
+	return workout, s.detectRecords(log, userId, workout), nil
+}
+
+// UpdateWorkout updates a workout of the user, its sets are replaced, and returns it with the personal records it sets.
+func (s *WorkoutService) UpdateWorkout(userId int64, workout entity.Workout) (entity.Workout, []entity.PersonalRecord, error) {
+	const op = "workouts.UpdateWorkout"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("workoutId", workout.ID))
+
+	if err := s.validateWorkout(workout); err != nil {
+		if errors.Is(err, ErrInvalidWorkout) {
+			return entity.Workout{}, nil, sl.Wrap(op, err)
+		}
+		log.Error("failed validate workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't update workout"))
+	}
+
+	err := s.repo.UpdateWorkout(userId, workout)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Workout{}, nil, sl.Wrap(op, ErrWorkoutNotFound)
+		}
+		log.Error("failed update workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't update workout"))
+	}
+
+	workout, err = s.repo.GetWorkout(userId, workout.ID)
+	if err != nil {
+		log.Error("failed get workout", sl.Err(err))
+		return entity.Workout{}, nil, sl.Wrap(op, fmt.Errorf("can't get workout"))
+	}
+	return workout, s.detectRecords(log, userId, workout), nil
+}
+
+// detectRecords returns the personal records the saved workout sets.
+// The workout is already saved, so a failure is only logged and no records are returned.
+func (s *WorkoutService) detectRecords(log *slog.Logger, userId int64, workout entity.Workout) []entity.PersonalRecord {
+	records, err := s.analytics.DetectRecords(userId, workout)
+	if err != nil {
+		log.Error("failed detect personal records", sl.Err(err))
+		return nil
+	}
+	if len(records) > 0 {
+		log.Info("personal records set", slog.Int64("workoutId", workout.ID), slog.Int("records", len(records)))
+	}
+	return records
+}
+
+// validateWorkout checks the workout fields and that the exercises of its sets exist.
+// Invalid workouts are reported with ErrInvalidWorkout.
+func (s *WorkoutService) validateWorkout(workout entity.Workout) error {
+	switch {
+	case workout.Name == "" || utf8.RuneCountInString(workout.Name) > maxWorkoutNameLength:
+		return fmt.Errorf("%w: name must be from 1 to %d characters", ErrInvalidWorkout, maxWorkoutNameLength)
+	case utf8.RuneCountInString(workout.Notes) > maxWorkoutNotesLength:
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidWorkout, maxWorkoutNotesLength)
+	case workout.StartedAt <= 0:
+		return fmt.Errorf("%w: started_at is required", ErrInvalidWorkout)
+	case workout.FinishedAt != 0 && workout.FinishedAt < workout.StartedAt:
+		return fmt.Errorf("%w: finished_at must not be before started_at", ErrInvalidWorkout)
+	case len(workout.Sets) > maxSetsInWorkout:
+		return fmt.Errorf("%w: workout can have at most %d sets", ErrInvalidWorkout, maxSetsInWorkout)
+	}
+
+	var exerciseIds []int64
+	for i, set := range workout.Sets {
+		if err := validateSet(set); err != nil {
+			return fmt.Errorf("%w: set %d: %s", ErrInvalidWorkout, i, err)
+		}
+		if !slices.Contains(exerciseIds, set.ExerciseID) {
+			exerciseIds = append(exerciseIds, set.ExerciseID)
+		}
+	}
+
+	exercises, err := s.repo.GetExercises(exerciseIds)
+	if err != nil {
+		return err
+	}
+	for _, id := range exerciseIds {
+		if _, ok := exercises[id]; !ok {
+			return fmt.Errorf("%w: exercise %d not found", ErrInvalidWorkout, id)
+		}
+	}
+	return nil
+}
+
+// validateSet checks the values of a set, a set must have reps, a duration or a distance.
+func validateSet(set entity.WorkoutSet) error {
+	switch {
+	case set.Reps < 0 || set.Reps > maxReps:
+		return fmt.Errorf("reps must be from 0 to %d", maxReps)
+	case math.IsNaN(set.WeightKg) || set.WeightKg < 0 || set.WeightKg > maxSetWeightKg:
+		return fmt.Errorf("weight must be from 0 to %d kg", maxSetWeightKg)
+	case set.DurationSec < 0 || set.DurationSec > maxSetDurationSec:
+		return fmt.Errorf("duration must be from 0 to %d seconds", maxSetDurationSec)
+	case math.IsNaN(set.DistanceM) || set.DistanceM < 0 || set.DistanceM > maxSetDistanceM:
+		return fmt.Errorf("distance must be from 0 to %d meters", maxSetDistanceM)
+	case set.Reps == 0 && set.DurationSec == 0 && set.DistanceM == 0:
+		return errors.New("set must have reps, a duration or a distance")
+	}
+	return nil
+}
+
+// GetWorkout retrieves a workout of the user with its sets.
+func (s *WorkoutService) GetWorkout(userId, workoutId int64) (entity.Workout, error) {
+	const op = "workouts.GetWorkout"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("workoutId", workoutId))
+
+	workout, err := s.repo.GetWorkout(userId, workoutId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return entity.Workout{}, sl.Wrap(op, ErrWorkoutNotFound)
+		}
+		log.Error("failed get workout", sl.Err(err))
+		return entity.Workout{}, sl.Wrap(op, fmt.Errorf("can't get workout"))
+	}
+	return workout, nil
+}
+
+// DeleteWorkout deletes a workout of the user.
+func (s *WorkoutService) DeleteWorkout(userId, workoutId int64) error {
+	const op = "workouts.DeleteWorkout"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId),
+		slog.Int64("workoutId", workoutId))
+
+	err := s.repo.DeleteWorkout(userId, workoutId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return sl.Wrap(op, ErrWorkoutNotFound)
+		}
+		log.Error("failed delete workout", sl.Err(err))
+		return sl.Wrap(op, fmt.Errorf("can't delete workout"))
+	}
+	return nil
+}
+
+// ListWorkouts retrieves a page of the workout history of the user started in [from, to), the latest first.
+// Zero from and to mean no bound. It returns the token of the next page, which is empty on the last page.
+func (s *WorkoutService) ListWorkouts(userId int64, from, to int64, size int, pageToken string) ([]entity.Workout, string, error) {
+	const op = "workouts.ListWorkouts"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("userId", userId))
+
+	after, err := decodeWorkoutPageToken(pageToken, from, to)
+	if err != nil {
+		return nil, "", sl.Wrap(op, err)
+	}
+
+	// One workout more than the page size is fetched to find out whether the next page exists
+	size = pageSize(size)
+	workouts, err := s.repo.ListWorkouts(userId, from, to, after, size+1)
+	if err != nil {
+		log.Error("failed get workouts", sl.Err(err))
+		return nil, "", sl.Wrap(op, fmt.Errorf("can't get workouts"))
+	}
+
+	if len(workouts) <= size {
+		return workouts, "", nil
+	}
+	workouts = workouts[:size]
+	return workouts, encodeWorkoutPageToken(from, to, workouts[size-1]), nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"OLO-backend/olo_service/internal/entity"
+	"OLO-backend/olo_service/internal/repository"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workoutRepo is an in-memory repository of the exercise catalog and the workouts of one user.
+type workoutRepo struct {
+	repository.Workout
+	exercises map[int64]entity.Exercise
+	workouts  []entity.Workout // the latest first
+}
+
+func (r *workoutRepo) GetExercises(ids []int64) (map[int64]entity.Exercise, error) {
+	found := make(map[int64]entity.Exercise)
+	for _, id := range ids {
+		if exercise, ok := r.exercises[id]; ok {
+			found[id] = exercise
+		}
+	}
+	return found, nil
+}
+
+func (r *workoutRepo) ListWorkouts(_ int64, from, to int64, after *entity.WorkoutCursor, limit int) ([]entity.Workout, error) {
+	var page []entity.Workout
+	for _, workout := range r.workouts {
+		switch {
+		case from != 0 && workout.StartedAt < from, to != 0 && workout.StartedAt >= to:
+			continue
+		case after != nil && (workout.StartedAt > after.StartedAt || workout.StartedAt == after.StartedAt && workout.ID >= after.ID):
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, workout)
+	}
+	return page, nil
+}
+
+func TestValidateSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     entity.WorkoutSet
+		wantErr bool
+	}{
+		{name: "reps", set: entity.WorkoutSet{Reps: 10, WeightKg: 60}},
+		{name: "bodyweight reps", set: entity.WorkoutSet{Reps: 20}},
+		{name: "duration", set: entity.WorkoutSet{DurationSec: 60}},
+		{name: "distance", set: entity.WorkoutSet{DistanceM: 5000}},
+		{name: "empty", set: entity.WorkoutSet{WeightKg: 60}, wantErr: true},
+		{name: "negative reps", set: entity.WorkoutSet{Reps: -1}, wantErr: true},
+		{name: "too many reps", set: entity.WorkoutSet{Reps: maxReps + 1}, wantErr: true},
+		{name: "negative weight", set: entity.WorkoutSet{Reps: 1, WeightKg: -5}, wantErr: true},
+		{name: "weight not a number", set: entity.WorkoutSet{Reps: 1, WeightKg: math.NaN()}, wantErr: true},
+		{name: "too heavy", set: entity.WorkoutSet{Reps: 1, WeightKg: maxSetWeightKg + 1}, wantErr: true},
+		{name: "too long", set: entity.WorkoutSet{DurationSec: maxSetDurationSec + 1}, wantErr: true},
+		{name: "distance not a number", set: entity.WorkoutSet{DistanceM: math.NaN()}, wantErr: true},
+		{name: "too far", set: entity.WorkoutSet{DistanceM: maxSetDistanceM + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSet(tt.set)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkout(t *testing.T) {
+	repo := &workoutRepo{exercises: map[int64]entity.Exercise{
+		1: {ID: 1, Name: "Bench press", MuscleGroup: "chest", Kind: "strength"},
+		2: {ID: 2, Name: "Running", MuscleGroup: "cardio", Kind: "cardio"},
+	}}
+	s := NewWorkoutService(discardLogger(), &repository.Repository{Workout: repo}, nil, nil)
+
+	valid := entity.Workout{
+		Name:      "Push day",
+		StartedAt: 1000,
+		Sets: []entity.WorkoutSet{
+			{ExerciseID: 1, Reps: 5, WeightKg: 100},
+			{ExerciseID: 1, Reps: 5, WeightKg: 100},
+			{ExerciseID: 2, DistanceM: 3000},
+		},
+	}
+	tests := []struct {
+		name    string
+		change  func(w *entity.Workout)
+		wantErr bool
+	}{
+		{name: "valid", change: func(w *entity.Workout) {}},
+		{name: "finished", change: func(w *entity.Workout) { w.FinishedAt = w.StartedAt + 3600 }},
+		{name: "without sets", change: func(w *entity.Workout) { w.Sets = nil }},
+		{name: "no name", change: func(w *entity.Workout) { w.Name = "" }, wantErr: true},
+		{name: "long name", change: func(w *entity.Workout) { w.Name = strings.Repeat("a", maxWorkoutNameLength+1) }, wantErr: true},
+		{name: "long notes", change: func(w *entity.Workout) { w.Notes = strings.Repeat("a", maxWorkoutNotesLength+1) }, wantErr: true},
+		{name: "not started", change: func(w *entity.Workout) { w.StartedAt = 0 }, wantErr: true},
+		{name: "finished before start", change: func(w *entity.Workout) { w.FinishedAt = w.StartedAt - 1 }, wantErr: true},
+		{name: "too many sets", change: func(w *entity.Workout) {
+			w.Sets = make([]entity.WorkoutSet, maxSetsInWorkout+1)
+			for i := range w.Sets {
+				w.Sets[i] = entity.WorkoutSet{ExerciseID: 1, Reps: 1}
+			}
+		}, wantErr: true},
+		{name: "invalid set", change: func(w *entity.Workout) { w.Sets = []entity.WorkoutSet{{ExerciseID: 1}} }, wantErr: true},
+		{name: "unknown exercise", change: func(w *entity.Workout) { w.Sets = []entity.WorkoutSet{{ExerciseID: 3, Reps: 1}} }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workout := valid
+			workout.Sets = append([]entity.WorkoutSet(nil), valid.Sets...)
+			tt.change(&workout)
+
+			err := s.validateWorkout(workout)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidWorkout)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListWorkoutsPages(t *testing.T) {
+	// Workouts 3 and 4 started at the same time, the id breaks the tie.
+	repo := &workoutRepo{workouts: []entity.Workout{
+		{ID: 6, StartedAt: 500},
+		{ID: 4, StartedAt: 400},
+		{ID: 3, StartedAt: 400},
+		{ID: 5, StartedAt: 300},
+		{ID: 2, StartedAt: 200},
+		{ID: 1, StartedAt: 100},
+	}}
+	s := NewWorkoutService(discardLogger(), &repository.Repository{Workout: repo}, nil, nil)
+
+	tests := []struct {
+		name     string
+		from, to int64
+		want     [][]int64 // ids of the workouts of each page
+	}{
+		{name: "all", want: [][]int64{{6, 4}, {3, 5}, {2, 1}}},
+		{name: "range", from: 200, to: 500, want: [][]int64{{4, 3}, {5, 2}}},
+		{name: "single page", from: 450, want: [][]int64{{6}}},
+		{name: "empty", to: 100, want: [][]int64{{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pages [][]int64
+			token := ""
+			for {
+				workouts, next, err := s.ListWorkouts(1, tt.from, tt.to, 2, token)
+				require.NoError(t, err)
+				ids := []int64{}
+				for _, workout := range workouts {
+					ids = append(ids, workout.ID)
+				}
+				pages = append(pages, ids)
+				if next == "" {
+					break
+				}
+				require.Less(t, len(pages), 10, "paging doesn't end")
+				token = next
+			}
+			assert.Equal(t, tt.want, pages)
+		})
+	}
+}
+
+func TestWorkoutPageToken(t *testing.T) {
+	token := encodeWorkoutPageToken(100, 200, entity.Workout{ID: 3, StartedAt: 150})
+
+	after, err := decodeWorkoutPageToken(token, 100, 200)
+	require.NoError(t, err)
+	assert.Equal(t, &entity.WorkoutCursor{StartedAt: 150, ID: 3}, after)
+
+	_, err = decodeWorkoutPageToken(token, 0, 200)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	_, err = decodeWorkoutPageToken(token, 100, 0)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+	_, err = decodeWorkoutPageToken("not a token", 100, 200)
+	assert.ErrorIs(t, err, ErrInvalidPageToken)
+
+	after, err = decodeWorkoutPageToken("", 100, 200)
+	require.NoError(t, err)
+	assert.Nil(t, after)
+}
@@ -0,0 +1,91 @@
+// Package widgettype provides the registry of widget types.
+//
+// Each widget type has a JSON Schema of its data, the schemas are embedded
+// into the binary from the schemas directory. The data of a widget is validated
+// against the schema of its type before it is saved.
+package widgettype
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Names of the widget types.
+const (
+	StepCounter    = "step_counter"
+	WaterIntake    = "water_intake"
+	WeightTracker  = "weight_tracker"
+	SleepTracker   = "sleep_tracker"
+	CalorieCounter = "calorie_counter"
+)
+
+// ErrUnknownType indicates that the widget type isn't registered.
+var ErrUnknownType = errors.New("unknown widget type")
+
+//go:embed schemas/*.json
+var schemas embed.FS
+
+// Type represents a widget type.
+type Type struct {
+	Name   string
+	Title  string
+	Schema *Schema
+	Raw    json.RawMessage // schema as it is declared, returned to clients
+}
+
+// Registry holds the known widget types.
+type Registry struct {
+	types []Type
+	index map[string]int
+}
+
+// NewRegistry creates a registry of the built-in widget types.
+func NewRegistry() (*Registry, error) {
+	r := &Registry{index: make(map[string]int)}
+	for _, name := range []string{StepCounter, WaterIntake, WeightTracker, SleepTracker, CalorieCounter} {
+		raw, err := schemas.ReadFile("schemas/" + name + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("widget type %s: %w", name, err)
+		}
+
+		var schema Schema
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			return nil, fmt.Errorf("widget type %s: invalid schema: %w", name, err)
+		}
+
+		r.index[name] = len(r.types)
+		r.types = append(r.types, Type{
+			Name:   name,
+			Title:  schema.Title,
+			Schema: &schema,
+			Raw:    raw,
+		})
+	}
+	return r, nil
+}
+
+// Types returns all registered widget types.
+func (r *Registry) Types() []Type {
+	return r.types
+}
+
+// Lookup returns the widget type by its name.
+func (r *Registry) Lookup(name string) (Type, bool) {
+	i, ok := r.index[name]
+	if !ok {
+		return Type{}, false
+	}
+	return r.types[i], true
+}
+
+// Validate checks the data of a widget against the schema of its type.
+// It returns ErrUnknownType or a *ValidationError.
+func (r *Registry) Validate(name string, data string) error {
+	t, ok := r.Lookup(name)
+	if !ok {
+		return ErrUnknownType
+	}
+	return t.Schema.Validate([]byte(data))
+}
@@ -0,0 +1,47 @@
+package widgettype
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryValidate(t *testing.T) {
+	registry, err := NewRegistry()
+	require.NoError(t, err)
+	require.Len(t, registry.Types(), 5)
+
+	tests := []struct {
+		name     string
+		typeName string
+		data     string
+		path     string // path of the expected validation error, empty if the data is valid
+	}{
+		{"valid steps", StepCounter, `{"goal": 10000, "steps": 4500, "date": "2024-05-01"}`, ""},
+		{"missing required", StepCounter, `{"steps": 4500}`, "$.goal"},
+		{"not integer", StepCounter, `{"goal": 100.5}`, "$.goal"},
+		{"below minimum", WaterIntake, `{"goal_ml": 0}`, "$.goal_ml"},
+		{"unknown property", WaterIntake, `{"goal_ml": 2000, "color": "red"}`, "$.color"},
+		{"invalid date", CalorieCounter, `{"goal_kcal": 2000, "date": "01.05.2024"}`, "$.date"},
+		{"valid entries", SleepTracker, `{"goal_hours": 8, "entries": [{"date": "2024-05-01", "hours": 7.5, "quality": 4}]}`, ""},
+		{"invalid entry", WeightTracker, `{"target_kg": 70, "entries": [{"date": "2024-05-01", "weight_kg": 71}, {"date": "2024-05-02"}]}`, "$.entries[1].weight_kg"},
+		{"not object", WeightTracker, `[1, 2]`, "$"},
+		{"invalid json", WeightTracker, `{"target_kg": `, "$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := registry.Validate(tt.typeName, tt.data)
+			if tt.path == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, tt.path, validationErr.Path)
+		})
+	}
+
+	assert.ErrorIs(t, registry.Validate("heart_rate", `{}`), ErrUnknownType)
+}
@@ -0,0 +1,175 @@
+package widgettype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema used by the widget types.
+//
+// Supported keywords: type, properties, required, additionalProperties (boolean only),
+// items, maxItems, minimum, maximum, minLength, maxLength, enum and format "date".
+type Schema struct {
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Format               string             `json:"format,omitempty"`
+}
+
+// ValidationError describes why the data doesn't match the schema.
+type ValidationError struct {
+	Path    string // path of the invalid value, e.g. $.entries[0].date
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks that the JSON data matches the schema.
+func (s *Schema) Validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return &ValidationError{Path: "$", Message: "invalid JSON: " + err.Error()}
+	}
+	if decoder.More() {
+		return &ValidationError{Path: "$", Message: "invalid JSON: unexpected data after the value"}
+	}
+	return s.validate("$", value)
+}
+
+func (s *Schema) validate(path string, value any) error {
+	fail := func(format string, args ...any) error {
+		return &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if len(s.Enum) > 0 && !s.inEnum(value) {
+		return fail("must be one of %v", s.Enum)
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fail("must be an object")
+		}
+		return s.validateObject(path, object)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return fail("must be an array")
+		}
+		if s.MaxItems != nil && len(array) > *s.MaxItems {
+			return fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fail("must be a string")
+		}
+		length := utf8.RuneCountInString(str)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fail("must be at most %d characters", *s.MaxLength)
+		}
+		if s.Format == "date" {
+			if _, err := time.Parse(time.DateOnly, str); err != nil {
+				return fail("must be a date in the YYYY-MM-DD format")
+			}
+		}
+	case "number", "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return fail("must be a %s", s.Type)
+		}
+		if s.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return fail("must be an integer")
+			}
+		}
+		f, err := number.Float64()
+		if err != nil {
+			return fail("must be a number")
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fail("must be at most %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(path string, object map[string]any) error {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			return &ValidationError{Path: path + "." + name, Message: "is required"}
+		}
+	}
+
+	// The properties are checked in a stable order, so the same data always gives the same error
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				return &ValidationError{Path: path + "." + name, Message: "is not allowed"}
+			}
+			continue
+		}
+		if err := property.validate(path+"."+name, object[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inEnum reports whether the value is one of the enum values.
+// Numbers are compared by their JSON representation.
+func (s *Schema) inEnum(value any) bool {
+	if number, ok := value.(json.Number); ok {
+		value = number.String()
+	}
+	return slices.ContainsFunc(s.Enum, func(e any) bool {
+		if f, ok := e.(float64); ok {
+			return fmt.Sprint(f) == value
+		}
+		return e == value
+	})
+}
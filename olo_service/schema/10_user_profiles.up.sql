@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_profiles (
+    `id_user`        BIGINT NOT NULL PRIMARY KEY,
+    `height_cm`      DOUBLE NOT NULL,
+    `birth_date`     DATE NOT NULL,
+    `sex`            VARCHAR(8) NOT NULL,
+    `activity_level` VARCHAR(16) NOT NULL,
+    `units`          VARCHAR(8) NOT NULL DEFAULT 'metric',
+    `goal_weight_kg` DOUBLE NOT NULL DEFAULT 0,
+    `updated_at`     BIGINT NOT NULL
+);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS goals (
+    `id`             BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `id_user`        BIGINT NOT NULL,
+    `title`          VARCHAR(100) NOT NULL,
+    `kind`           VARCHAR(16) NOT NULL,
+    `metric_type`    VARCHAR(32) NOT NULL,
+    `comparison`     VARCHAR(16) NOT NULL,
+    `target`         DOUBLE NOT NULL,
+    `start_value`    DOUBLE NOT NULL DEFAULT 0,
+    `deadline`       VARCHAR(10) NOT NULL DEFAULT '',
+    `utc_offset`     INT NOT NULL DEFAULT 0,
+    `status`         VARCHAR(16) NOT NULL DEFAULT 'active',
+    `current_value`  DOUBLE NOT NULL DEFAULT 0,
+    `progress`       DOUBLE NOT NULL DEFAULT 0,
+    `current_streak` INT NOT NULL DEFAULT 0,
+    `longest_streak` INT NOT NULL DEFAULT 0,
+    `completed_at`   BIGINT NOT NULL DEFAULT 0,
+    `evaluated_at`   BIGINT NOT NULL DEFAULT 0,
+    `created_at`     BIGINT NOT NULL,
+    INDEX `idx_goals_user` (`id_user`),
+    INDEX `idx_goals_status` (`status`, `id`)
+);
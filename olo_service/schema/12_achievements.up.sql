@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS user_achievements (
+    `id_user`        BIGINT NOT NULL,
+    `achievement_id` VARCHAR(64) NOT NULL,
+    `earned_at`      BIGINT NOT NULL,
+    PRIMARY KEY (`id_user`, `achievement_id`)
+);
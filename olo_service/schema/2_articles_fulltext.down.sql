@@ -0,0 +1 @@
+ALTER TABLE articles DROP INDEX articles_fulltext;
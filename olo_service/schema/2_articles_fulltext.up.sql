@@ -0,0 +1 @@
+ALTER TABLE articles ADD FULLTEXT INDEX articles_fulltext (header, body);
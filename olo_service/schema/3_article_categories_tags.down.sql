@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS article_has_tags;
+DROP TABLE IF EXISTS tags;
+ALTER TABLE articles DROP FOREIGN KEY `fk_articles_category`, DROP COLUMN `category_id`;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS categories (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `slug` VARCHAR(50) NOT NULL UNIQUE,
+    `name` VARCHAR(100) NOT NULL
+);
+
+ALTER TABLE articles
+    ADD COLUMN `category_id` INT NULL,
+    ADD CONSTRAINT `fk_articles_category` FOREIGN KEY (`category_id`) REFERENCES categories (`id`) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS tags (
+    `id` INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `name` VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS article_has_tags (
+    `id_articles` INT NOT NULL,
+    `id_tag`      INT NOT NULL,
+    PRIMARY KEY (`id_articles`, `id_tag`),
+    FOREIGN KEY (`id_articles`) REFERENCES articles (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`id_tag`) REFERENCES tags (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO categories (slug, name) VALUES ('nutrition', 'Питание');
+INSERT INTO categories (slug, name) VALUES ('strength', 'Силовые тренировки');
+INSERT INTO categories (slug, name) VALUES ('cardio', 'Кардио');
+INSERT INTO categories (slug, name) VALUES ('sleep', 'Сон');
@@ -0,0 +1 @@
+ALTER TABLE widgetsUser DROP COLUMN `type`;
@@ -0,0 +1 @@
+ALTER TABLE widgetsUser ADD COLUMN `type` VARCHAR(32) NOT NULL DEFAULT '';
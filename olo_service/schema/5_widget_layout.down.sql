@@ -0,0 +1,7 @@
+ALTER TABLE widgetsUser
+    DROP COLUMN `position`,
+    DROP COLUMN `grid_x`,
+    DROP COLUMN `grid_y`,
+    DROP COLUMN `width`,
+    DROP COLUMN `height`,
+    DROP COLUMN `visible`;
@@ -0,0 +1,9 @@
+ALTER TABLE widgetsUser
+    ADD COLUMN `position` INT NOT NULL DEFAULT 0,
+    ADD COLUMN `grid_x` INT NOT NULL DEFAULT 0,
+    ADD COLUMN `grid_y` INT NOT NULL DEFAULT 0,
+    ADD COLUMN `width` INT NOT NULL DEFAULT 1,
+    ADD COLUMN `height` INT NOT NULL DEFAULT 1,
+    ADD COLUMN `visible` BOOLEAN NOT NULL DEFAULT TRUE;
+
+UPDATE widgetsUser SET `position` = `id`;
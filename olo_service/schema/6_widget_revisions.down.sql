@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS widget_revisions;
+ALTER TABLE widgetsUser DROP COLUMN `version`;
@@ -0,0 +1,14 @@
+ALTER TABLE widgetsUser ADD COLUMN `version` INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS widget_revisions (
+    `id_widget`  INT NOT NULL,
+    `id_user`    BIGINT NOT NULL,
+    `version`    INT NOT NULL,
+    `type`       VARCHAR(32) NOT NULL,
+    `data`       TEXT NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id_widget`, `id_user`, `version`)
+);
+
+INSERT INTO widget_revisions (id_widget, id_user, version, type, data)
+SELECT id, id_user, version, type, data FROM widgetsUser;
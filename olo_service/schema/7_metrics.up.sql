@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS metrics (
+    `id`          BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `id_user`     BIGINT NOT NULL,
+    `type`        VARCHAR(32) NOT NULL,
+    `recorded_at` BIGINT NOT NULL,
+    `value`       DOUBLE NOT NULL,
+    `unit`        VARCHAR(16) NOT NULL,
+    `source`      VARCHAR(64) NOT NULL DEFAULT '',
+    INDEX `idx_metrics_user_type_time` (`id_user`, `type`, `recorded_at`)
+);
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS exercises (
+    `id`           INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `name`         VARCHAR(100) NOT NULL UNIQUE,
+    `muscle_group` VARCHAR(32) NOT NULL,
+    `kind`         VARCHAR(16) NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS workouts (
+    `id`          BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `id_user`     BIGINT NOT NULL,
+    `name`        VARCHAR(100) NOT NULL,
+    `started_at`  BIGINT NOT NULL,
+    `finished_at` BIGINT NOT NULL DEFAULT 0,
+    `notes`       VARCHAR(1000) NOT NULL DEFAULT '',
+    INDEX `idx_workouts_user_time` (`id_user`, `started_at`)
+);
+
+CREATE TABLE IF NOT EXISTS workout_sets (
+    `id`           BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `id_workout`   BIGINT NOT NULL,
+    `id_exercise`  INT NOT NULL,
+    `position`     INT NOT NULL,
+    `reps`         INT NOT NULL DEFAULT 0,
+    `weight_kg`    DOUBLE NOT NULL DEFAULT 0,
+    `duration_sec` INT NOT NULL DEFAULT 0,
+    `distance_m`   DOUBLE NOT NULL DEFAULT 0,
+    FOREIGN KEY (`id_workout`) REFERENCES workouts (`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`id_exercise`) REFERENCES exercises (`id`)
+);
+
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Жим штанги лёжа', 'chest', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Отжимания', 'chest', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Становая тяга', 'back', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Подтягивания', 'back', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Тяга штанги в наклоне', 'back', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Приседания со штангой', 'legs', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Выпады', 'legs', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Жим штанги стоя', 'shoulders', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Подъём штанги на бицепс', 'arms', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Планка', 'core', 'strength');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Бег', 'cardio', 'cardio');
+INSERT INTO exercises (name, muscle_group, kind) VALUES ('Велосипед', 'cardio', 'cardio');
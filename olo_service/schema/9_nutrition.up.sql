@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS foods (
+    `id`       INT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `name`     VARCHAR(100) NOT NULL UNIQUE,
+    `calories` DOUBLE NOT NULL,
+    `protein`  DOUBLE NOT NULL,
+    `fat`      DOUBLE NOT NULL,
+    `carbs`    DOUBLE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS meals (
+    `id`         BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    `id_user`    BIGINT NOT NULL,
+    `date`       DATE NOT NULL,
+    `meal_type`  VARCHAR(16) NOT NULL,
+    `id_food`    INT NOT NULL,
+    `grams`      DOUBLE NOT NULL,
+    `created_at` BIGINT NOT NULL,
+    INDEX `idx_meals_user_date` (`id_user`, `date`),
+    FOREIGN KEY (`id_food`) REFERENCES foods (`id`)
+);
+
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Куриная грудка', 113, 23.6, 1.9, 0.4);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Говядина', 187, 18.9, 12.4, 0);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Лосось', 153, 20, 8.1, 0);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Яйцо куриное', 157, 12.7, 11.5, 0.7);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Творог 5%', 121, 17.2, 5, 1.8);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Молоко 2,5%', 52, 2.8, 2.5, 4.7);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Кефир 1%', 40, 3, 1, 4);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Гречка варёная', 110, 4.2, 1.1, 21.3);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Рис варёный', 116, 2.2, 0.5, 24.9);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Овсяная каша на воде', 88, 3, 1.7, 15);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Макароны варёные', 112, 3.5, 0.4, 23.2);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Хлеб ржаной', 174, 6.6, 1.2, 34.2);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Картофель варёный', 82, 2, 0.4, 16.7);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Банан', 96, 1.5, 0.5, 21);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Яблоко', 47, 0.4, 0.4, 9.8);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Огурец', 15, 0.8, 0.1, 2.8);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Помидор', 20, 1.1, 0.2, 3.7);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Брокколи', 34, 2.8, 0.4, 6.6);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Грецкий орех', 654, 15.2, 65.2, 7);
+INSERT INTO foods (name, calories, protein, fat, carbs) VALUES ('Масло оливковое', 898, 0, 99.8, 0);
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidClaims indicates that a validly signed token carries malformed claims.
+var ErrInvalidClaims = errors.New("invalid token claims")
+
+// Claims represents the claims of an access token issued by the auth service.
+type Claims struct {
+	jwt.RegisteredClaims
+	UID   int64  `json:"uid"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	AppID int    `json:"app,omitempty"`
+}
+
+// Validate checks the custom claims, it is called by the jwt parser
+// after the registered claims were validated. The jti is required
+// because tokens are revoked by it.
+func (c *Claims) Validate() error {
+	if c.ID == "" || c.UID <= 0 || c.Email == "" || !IsKnownRole(c.Role) {
+		return ErrInvalidClaims
+	}
+	return nil
+}
+
+// TokenUser returns the user the token was issued for.
+func (c *Claims) TokenUser() (TokenUser, error) {
+	if err := c.Validate(); err != nil {
+		return TokenUser{}, err
+	}
+	return TokenUser{
+		ID:    c.UID,
+		Email: c.Email,
+		Role:  c.Role,
+	}, nil
+}
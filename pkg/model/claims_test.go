@@ -0,0 +1,95 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaimsParse(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	valid := jwt.MapClaims{
+		"jti":   "f3a1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"uid":   7,
+		"email": "user@example.com",
+		"role":  RoleUser,
+	}
+	tests := []struct {
+		name    string
+		change  func(c jwt.MapClaims)
+		wantErr bool
+	}{
+		{name: "valid", change: func(c jwt.MapClaims) {}},
+		{name: "service", change: func(c jwt.MapClaims) { c["role"] = RoleService }},
+		{name: "missing uid", change: func(c jwt.MapClaims) { delete(c, "uid") }, wantErr: true},
+		{name: "non-numeric uid", change: func(c jwt.MapClaims) { c["uid"] = "abc" }, wantErr: true},
+		{name: "negative uid", change: func(c jwt.MapClaims) { c["uid"] = -1 }, wantErr: true},
+		{name: "missing email", change: func(c jwt.MapClaims) { delete(c, "email") }, wantErr: true},
+		{name: "missing role", change: func(c jwt.MapClaims) { delete(c, "role") }, wantErr: true},
+		{name: "unknown role", change: func(c jwt.MapClaims) { c["role"] = "ROOT" }, wantErr: true},
+		{name: "missing jti", change: func(c jwt.MapClaims) { delete(c, "jti") }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{}
+			for k, v := range valid {
+				claims[k] = v
+			}
+			tt.change(claims)
+			tokenString, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(priv)
+			require.NoError(t, err)
+
+			parsed := &Claims{}
+			_, err = jwt.ParseWithClaims(tokenString, parsed, func(*jwt.Token) (interface{}, error) { return pub, nil })
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			user, err := parsed.TokenUser()
+			require.NoError(t, err)
+			assert.Equal(t, TokenUser{ID: 7, Email: "user@example.com", Role: claims["role"].(string)}, user)
+		})
+	}
+}
+
+func TestClaimsTokenUser(t *testing.T) {
+	valid := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{ID: "f3a1"},
+		UID:              7,
+		Email:            "user@example.com",
+		Role:             RoleAdmin,
+	}
+	tests := []struct {
+		name   string
+		change func(c *Claims)
+	}{
+		{name: "missing uid", change: func(c *Claims) { c.UID = 0 }},
+		{name: "missing email", change: func(c *Claims) { c.Email = "" }},
+		{name: "unknown role", change: func(c *Claims) { c.Role = "ROOT" }},
+		{name: "missing jti", change: func(c *Claims) { c.ID = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := valid
+			tt.change(&claims)
+
+			_, err := claims.TokenUser()
+			assert.ErrorIs(t, err, ErrInvalidClaims)
+		})
+	}
+
+	user, err := valid.TokenUser()
+	require.NoError(t, err)
+	assert.Equal(t, TokenUser{ID: 7, Email: "user@example.com", Role: RoleAdmin}, user)
+}
@@ -0,0 +1,20 @@
+package model
+
+// Roles a user can have.
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+)
+
+// RoleService is the role of the tokens the services issue to call each other.
+// It isn't assigned to users.
+const RoleService = "SERVICE"
+
+// IsKnownRole reports whether the role is one of the roles a token can carry.
+func IsKnownRole(role string) bool {
+	switch role {
+	case RoleUser, RoleAdmin, RoleService:
+		return true
+	}
+	return false
+}
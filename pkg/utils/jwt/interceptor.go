@@ -0,0 +1,113 @@
+// Package jwt provides functionality for working with JSON Web Tokens (JWT).
+//
+// This package includes gRPC server interceptors that authenticate requests
+// by the token passed in the Authorization metadata.
+package jwt
+
+import (
+	"OLO-backend/pkg/model"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationHeader is the metadata key the access token is passed in.
+const AuthorizationHeader = "Authorization"
+
+// claimsKey is the context key the claims of an authenticated request are stored under.
+type claimsKey struct{}
+
+// ContextWithClaims returns a copy of the context carrying the token claims.
+func ContextWithClaims(ctx context.Context, claims *model.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the token claims stored in the context by the interceptor.
+func ClaimsFromContext(ctx context.Context) (*model.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*model.Claims)
+	return claims, ok
+}
+
+// UserFromContext returns the user of the token stored in the context by the interceptor.
+func UserFromContext(ctx context.Context) (model.TokenUser, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return model.TokenUser{}, false
+	}
+	user, err := claims.TokenUser()
+	if err != nil {
+		return model.TokenUser{}, false
+	}
+	return user, true
+}
+
+// UnaryServerInterceptor returns a unary interceptor that validates the token of every request
+// and stores its claims in the context. Methods listed in publicMethods by their full name
+// (for example "/proto.Auth/Login") are passed through without a token.
+func (v *Validator) UnaryServerInterceptor(publicMethods ...string) grpc.UnaryServerInterceptor {
+	public := methodSet(publicMethods)
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if public[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		ctx, err := v.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream interceptor that validates the token of every stream
+// and stores its claims in the stream context. Methods listed in publicMethods are passed through.
+func (v *Validator) StreamServerInterceptor(publicMethods ...string) grpc.StreamServerInterceptor {
+	public := methodSet(publicMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if public[info.FullMethod] {
+			return handler(srv, ss)
+		}
+
+		ctx, err := v.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate validates the token from the context metadata and returns a context carrying its claims.
+func (v *Validator) authenticate(ctx context.Context) (context.Context, error) {
+	claims, err := v.ClaimsFromContextMetadata(ctx, AuthorizationHeader)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err := claims.Validate(); err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return ContextWithClaims(ctx, claims), nil
+}
+
+// authenticatedStream overrides the context of a server stream.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the token claims.
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// methodSet converts a list of method names into a set.
+func methodSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		set[method] = true
+	}
+	return set
+}
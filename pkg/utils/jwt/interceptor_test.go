@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"OLO-backend/pkg/model"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	publicMethod  = "/proto.Auth/Login"
+	privateMethod = "/proto.Auth/Logout"
+)
+
+// newTestKeys returns an issuer and a validator sharing a freshly generated key pair.
+func newTestKeys(t *testing.T) (*Issuer, *Validator) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return &Issuer{key: priv}, &Validator{key: pub, revocations: NewMemoryRevocationStore()}
+}
+
+// withToken returns a context carrying the token in the incoming metadata.
+func withToken(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationHeader, "Bearer "+token))
+}
+
+// testStream is a server stream with a fixed context.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	issuer, validator := newTestKeys(t)
+	user := model.TokenUser{ID: 7, Email: "user@example.com", Role: model.RoleUser}
+
+	token, err := issuer.NewToken(user, 1, time.Hour)
+	require.NoError(t, err)
+	revoked, err := issuer.NewToken(user, 1, time.Hour)
+	require.NoError(t, err)
+	claims, err := validator.GetClaims(revoked)
+	require.NoError(t, err)
+	require.NoError(t, validator.Revoke(claims))
+	expired, err := issuer.NewToken(user, 1, -time.Minute)
+	require.NoError(t, err)
+	otherIssuer, _ := newTestKeys(t)
+	foreign, err := otherIssuer.NewToken(user, 1, time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantUser bool
+		wantCode codes.Code
+	}{
+		{name: "public method without token", ctx: context.Background(), method: publicMethod},
+		{name: "public method with token", ctx: withToken("malformed"), method: publicMethod},
+		{name: "valid token", ctx: withToken(token), method: privateMethod, wantUser: true},
+		{name: "no metadata", ctx: context.Background(), method: privateMethod, wantCode: codes.Unauthenticated},
+		{name: "missing token", ctx: metadata.NewIncomingContext(context.Background(), metadata.MD{}), method: privateMethod, wantCode: codes.Unauthenticated},
+		{name: "malformed token", ctx: withToken("malformed"), method: privateMethod, wantCode: codes.Unauthenticated},
+		{name: "revoked token", ctx: withToken(revoked), method: privateMethod, wantCode: codes.Unauthenticated},
+		{name: "expired token", ctx: withToken(expired), method: privateMethod, wantCode: codes.Unauthenticated},
+		{name: "token signed by another key", ctx: withToken(foreign), method: privateMethod, wantCode: codes.Unauthenticated},
+	}
+
+	interceptor := validator.UnaryServerInterceptor(publicMethod)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				called = true
+				got, ok := UserFromContext(ctx)
+				assert.Equal(t, tt.wantUser, ok)
+				if tt.wantUser {
+					assert.Equal(t, user, got)
+				}
+				return "response", nil
+			}
+
+			resp, err := interceptor(tt.ctx, "request", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.False(t, called)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, called)
+			assert.Equal(t, "response", resp)
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	issuer, validator := newTestKeys(t)
+	user := model.TokenUser{ID: 7, Email: "user@example.com", Role: model.RoleAdmin}
+	token, err := issuer.NewToken(user, 1, time.Hour)
+	require.NoError(t, err)
+
+	interceptor := validator.StreamServerInterceptor(publicMethod)
+
+	var got model.TokenUser
+	handler := func(srv any, ss grpc.ServerStream) error {
+		var ok bool
+		got, ok = UserFromContext(ss.Context())
+		require.True(t, ok)
+		return nil
+	}
+	err = interceptor(nil, &testStream{ctx: withToken(token)}, &grpc.StreamServerInfo{FullMethod: privateMethod}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	err = interceptor(nil, &testStream{ctx: withToken("malformed")}, &grpc.StreamServerInfo{FullMethod: privateMethod}, handler)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	called := false
+	err = interceptor(nil, &testStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: publicMethod}, func(srv any, ss grpc.ServerStream) error {
+		called = true
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
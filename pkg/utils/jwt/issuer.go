@@ -6,12 +6,20 @@ package jwt
 import (
 	"OLO-backend/pkg/model"
 	"crypto"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"os"
 	"time"
 )
 
+func init() {
+	// Tokens are revoked by the moment they were issued at,
+	// so second precision is too coarse to tell apart tokens issued right before and after a revocation.
+	jwt.TimePrecision = time.Millisecond
+}
+
 type Issuer struct {
 	key crypto.PrivateKey
 }
@@ -32,14 +40,25 @@ func NewIssuer(privateKeyPath string) (*Issuer, error) {
 	}, nil
 }
 
-func (i *Issuer) NewToken(user model.TokenUser, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodEdDSA)
+// NewToken issues a signed token for the user of the application.
+func (i *Issuer) NewToken(user model.TokenUser, appID int, duration time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["uid"] = user.ID
-	claims["email"] = user.Email
-	claims["role"] = user.Role
-	claims["exp"] = time.Now().Add(duration).Unix()
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, &model.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		},
+		UID:   user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+		AppID: appID,
+	})
 
 	tokenString, err := token.SignedString(i.key)
 	if err != nil {
@@ -48,3 +67,12 @@ func (i *Issuer) NewToken(user model.TokenUser, duration time.Duration) (string,
 
 	return tokenString, nil
 }
+
+// newTokenID generates a random unique identifier of a token used as the jti claim.
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
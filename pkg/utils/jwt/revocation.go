@@ -0,0 +1,79 @@
+// Package jwt provides functionality for working with JSON Web Tokens (JWT).
+//
+// This package includes a RevocationStore used by the Validator to reject tokens
+// that were revoked before they expired.
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationStore keeps track of revoked tokens.
+//
+// A single token is revoked by its jti claim, while all tokens of a user
+// are revoked at once by remembering the moment of revocation:
+// every token of the user issued before it is rejected.
+type RevocationStore interface {
+	RevokeToken(jti string, expiresAt time.Time) error
+	RevokeUser(userID int64, issuedBefore time.Time) error
+	IsRevoked(jti string, userID int64, issuedAt time.Time) (bool, error)
+}
+
+// MemoryRevocationStore is an in-memory RevocationStore.
+// It is not shared between processes and is lost on restart.
+type MemoryRevocationStore struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time // jti -> token expiration
+	users  map[int64]time.Time  // user id -> tokens issued before are revoked
+}
+
+// NewMemoryRevocationStore creates a new instance of MemoryRevocationStore.
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		tokens: make(map[string]time.Time),
+		users:  make(map[int64]time.Time),
+	}
+}
+
+// RevokeToken revokes a single token until it expires.
+func (s *MemoryRevocationStore) RevokeToken(jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Expired tokens are rejected by the validator anyway, so there is no need to keep them.
+	now := time.Now()
+	for id, exp := range s.tokens {
+		if exp.Before(now) {
+			delete(s.tokens, id)
+		}
+	}
+
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+// RevokeUser revokes all tokens of the user issued before the given moment.
+func (s *MemoryRevocationStore) RevokeUser(userID int64, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if before, ok := s.users[userID]; !ok || before.Before(issuedBefore) {
+		s.users[userID] = issuedBefore
+	}
+	return nil
+}
+
+// IsRevoked reports whether the token was revoked.
+func (s *MemoryRevocationStore) IsRevoked(jti string, userID int64, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.tokens[jti]; ok {
+		return true, nil
+	}
+	if before, ok := s.users[userID]; ok && issuedAt.Before(before) {
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Table names of the MySQL revocation store.
+const (
+	tableNameRevokedTokens = "revoked_tokens"
+	tableNameRevokedUsers  = "revoked_users"
+)
+
+// MySQLRevocationStore is a RevocationStore kept in MySQL.
+// It lets several services share revocations through the same database.
+type MySQLRevocationStore struct {
+	db *sqlx.DB
+}
+
+// NewMySQLRevocationStore creates a new instance of MySQLRevocationStore
+// and creates its tables if they don't exist.
+func NewMySQLRevocationStore(db *sqlx.DB) (*MySQLRevocationStore, error) {
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + tableNameRevokedTokens + " (" +
+		"jti VARCHAR(64) NOT NULL PRIMARY KEY, " +
+		"expires_at BIGINT NOT NULL" +
+		")")
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s table: %w", tableNameRevokedTokens, err)
+	}
+
+	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + tableNameRevokedUsers + " (" +
+		"user_id BIGINT NOT NULL PRIMARY KEY, " +
+		"revoked_before BIGINT NOT NULL" +
+		")")
+	if err != nil {
+		return nil, fmt.Errorf("error creating %s table: %w", tableNameRevokedUsers, err)
+	}
+
+	return &MySQLRevocationStore{db: db}, nil
+}
+
+// RevokeToken revokes a single token until it expires.
+func (s *MySQLRevocationStore) RevokeToken(jti string, expiresAt time.Time) error {
+	// Expired tokens are rejected by the validator anyway, so there is no need to keep them.
+	_, err := s.db.Exec("DELETE FROM "+tableNameRevokedTokens+" WHERE expires_at < ?", time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec("INSERT IGNORE INTO "+tableNameRevokedTokens+" (`jti`, `expires_at`) VALUES (?, ?)",
+		jti, expiresAt.Unix())
+	return err
+}
+
+// RevokeUser revokes all tokens of the user issued before the given moment.
+func (s *MySQLRevocationStore) RevokeUser(userID int64, issuedBefore time.Time) error {
+	_, err := s.db.Exec("INSERT INTO "+tableNameRevokedUsers+" (`user_id`, `revoked_before`) VALUES (?, ?) "+
+		"ON DUPLICATE KEY UPDATE `revoked_before` = GREATEST(`revoked_before`, VALUES(`revoked_before`))",
+		userID, issuedBefore.UnixMilli())
+	return err
+}
+
+// IsRevoked reports whether the token was revoked.
+func (s *MySQLRevocationStore) IsRevoked(jti string, userID int64, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	err := s.db.Get(&revoked, "SELECT "+
+		"EXISTS(SELECT 1 FROM "+tableNameRevokedTokens+" WHERE jti = ?) OR "+
+		"EXISTS(SELECT 1 FROM "+tableNameRevokedUsers+" WHERE user_id = ? AND revoked_before > ?)",
+		jti, userID, issuedAt.UnixMilli())
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
@@ -4,6 +4,7 @@
 package jwt
 
 import (
+	"OLO-backend/pkg/model"
 	"context"
 	"crypto"
 	"errors"
@@ -11,13 +12,20 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/metadata"
 	"os"
+	"strings"
+	"time"
 )
 
+// ErrTokenRevoked indicates that the token was revoked before it expired.
+var ErrTokenRevoked = errors.New("token revoked")
+
 type Validator struct {
-	key crypto.PublicKey
+	key         crypto.PublicKey
+	revocations RevocationStore
 }
 
-// NewValidator returns a new validator by parsing the given file path as a ed25519 public key
+// NewValidator returns a new validator by parsing the given file path as a ed25519 public key.
+// Revoked tokens are kept in memory until another store is set with SetRevocationStore.
 func NewValidator(publicKeyPath string) (*Validator, error) {
 	keyBytes, err := os.ReadFile(publicKeyPath)
 	if err != nil {
@@ -30,16 +38,24 @@ func NewValidator(publicKeyPath string) (*Validator, error) {
 	}
 
 	return &Validator{
-		key: key,
+		key:         key,
+		revocations: NewMemoryRevocationStore(),
 	}, nil
 }
 
+// SetRevocationStore sets the store the validator consults for revoked tokens.
+func (v *Validator) SetRevocationStore(store RevocationStore) {
+	v.revocations = store
+}
+
 // GetToken attempts to get a token from the given string
-// it validates both the signature and claim and returns nil and an err if invalid
+// it validates both the signature and claim and returns nil and an err if invalid.
+// The claims of the returned token are always of type *model.Claims.
 func (v *Validator) GetToken(tokenString string) (*jwt.Token, error) {
-	// jwt.Parse also does signature verify and claim validation
-	token, err := jwt.Parse(
+	// jwt.ParseWithClaims also does signature verify and claim validation
+	token, err := jwt.ParseWithClaims(
 		tokenString,
+		&model.Claims{},
 		// the func below is to help figure out if the token came from a key we trust
 		// our implementation assumes a single trusted private key
 		//
@@ -57,9 +73,69 @@ func (v *Validator) GetToken(tokenString string) (*jwt.Token, error) {
 		return nil, fmt.Errorf("unable to parse token string: %w", err)
 	}
 
+	revoked, err := v.isRevoked(token.Claims.(*model.Claims))
+	if err != nil {
+		return nil, fmt.Errorf("unable to check token revocation: %w", err)
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
 	return token, nil
 }
 
+// GetClaims attempts to get a token from the given string and returns its claims.
+func (v *Validator) GetClaims(tokenString string) (*model.Claims, error) {
+	token, err := v.GetToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(*model.Claims), nil
+}
+
+// Revoke revokes the token with the given claims until it expires.
+func (v *Validator) Revoke(claims *model.Claims) error {
+	if claims.ID == "" {
+		return errors.New("token has no jti claim")
+	}
+	if claims.ExpiresAt == nil {
+		return errors.New("token has no exp claim")
+	}
+	return v.revocations.RevokeToken(claims.ID, claims.ExpiresAt.Time)
+}
+
+// RevokeUser revokes all tokens of the user issued up to now.
+func (v *Validator) RevokeUser(userID int64) error {
+	return v.revocations.RevokeUser(userID, time.Now())
+}
+
+// isRevoked checks the token claims against the revocation store.
+func (v *Validator) isRevoked(claims *model.Claims) (bool, error) {
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	return v.revocations.IsRevoked(claims.ID, claims.UID, issuedAt)
+}
+
+// ClaimsFromContextMetadata extracts the JWT token from the context metadata and returns its claims.
+func (v *Validator) ClaimsFromContextMetadata(ctx context.Context, headerKey string) (*model.Claims, error) {
+	token, err := v.TokenFromContextMetadata(ctx, headerKey)
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(*model.Claims), nil
+}
+
+// UserFromContextMetadata extracts the JWT token from the context metadata and returns the user it was issued for.
+func (v *Validator) UserFromContextMetadata(ctx context.Context, headerKey string) (model.TokenUser, error) {
+	claims, err := v.ClaimsFromContextMetadata(ctx, headerKey)
+	if err != nil {
+		return model.TokenUser{}, err
+	}
+	return claims.TokenUser()
+}
+
 // TokenFromContextMetadata extracts the JWT token from the context metadata.
 func (v *Validator) TokenFromContextMetadata(ctx context.Context, headerKey string) (*jwt.Token, error) {
 	headers, ok := metadata.FromIncomingContext(ctx)
@@ -70,7 +146,7 @@ func (v *Validator) TokenFromContextMetadata(ctx context.Context, headerKey stri
 	if len(tokens) < 1 {
 		return nil, errors.New("no token found in metadata")
 	}
-	tokenString := tokens[0]
+	tokenString := strings.TrimPrefix(tokens[0], "Bearer ")
 
 	token, err := v.GetToken(tokenString)
 	if err != nil {
@@ -0,0 +1,48 @@
+package policy
+
+import (
+	"OLO-backend/pkg/utils/jwt"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor returns a unary interceptor enforcing the policy.
+// It must be chained after the jwt interceptor, which stores the caller in the context.
+func (e *Enforcer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := e.authorizeContext(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns a stream interceptor enforcing the policy.
+// It must be chained after the jwt interceptor, which stores the caller in the context.
+func (e *Enforcer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := e.authorizeContext(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorizeContext checks the caller stored in the context against the rule of the method.
+func (e *Enforcer) authorizeContext(ctx context.Context, fullMethod string) error {
+	if !e.IsRestricted(fullMethod) {
+		return nil
+	}
+
+	user, ok := jwt.UserFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "request is not authenticated")
+	}
+	if err := e.Authorize(fullMethod, user.Role); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
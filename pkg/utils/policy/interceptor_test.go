@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"OLO-backend/pkg/model"
+	"OLO-backend/pkg/utils/jwt"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testStream is a server stream with a fixed context.
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+// withRole returns a context carrying the claims of a user with the role, as the jwt interceptor stores them.
+func withRole(role string) context.Context {
+	claims := &model.Claims{UID: 7, Email: "user@example.com", Role: role}
+	claims.ID = "f3a1"
+	return jwt.ContextWithClaims(context.Background(), claims)
+}
+
+func TestInterceptors(t *testing.T) {
+	e, err := NewEnforcer(testConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		method   string
+		wantCode codes.Code
+	}{
+		{name: "allowed by permission", ctx: withRole(model.RoleAdmin), method: createArticle},
+		{name: "allowed by role", ctx: withRole(model.RoleService), method: recordMetrics},
+		{name: "denied role", ctx: withRole(model.RoleUser), method: createArticle, wantCode: codes.PermissionDenied},
+		{name: "role not listed", ctx: withRole(model.RoleUser), method: recordMetrics, wantCode: codes.PermissionDenied},
+		{name: "method not in the policy", ctx: withRole(model.RoleUser), method: getArticles},
+		{name: "unauthenticated public method", ctx: context.Background(), method: getArticles},
+		{name: "unauthenticated restricted method", ctx: context.Background(), method: createArticle, wantCode: codes.Unauthenticated},
+	}
+
+	unary := e.UnaryServerInterceptor()
+	stream := e.StreamServerInterceptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := unary(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, req any) (any, error) {
+				called = true
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+
+			called = false
+			err = stream(nil, &testStream{ctx: tt.ctx}, &grpc.StreamServerInfo{FullMethod: tt.method}, func(srv any, ss grpc.ServerStream) error {
+				called = true
+				return nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+		})
+	}
+}
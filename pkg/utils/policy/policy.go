@@ -0,0 +1,98 @@
+// Package policy provides role-based authorization of gRPC methods.
+//
+// The policy is declared in the service config: it lists the known roles with the
+// permissions they grant and maps gRPC full method names to the roles or permissions
+// required to call them. Methods that are not listed are available to any authenticated user.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrPermissionDenied indicates that the role is not allowed to call the method.
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Config represents the access policy of a service.
+type Config struct {
+	Roles   map[string][]string `yaml:"roles"`   // role -> permissions granted by the role
+	Methods map[string]Rule     `yaml:"methods"` // full method name -> rule
+}
+
+// Rule represents the requirements to call a method.
+// The caller must have one of the Roles (if any are listed)
+// and its role must grant all of the Permissions.
+type Rule struct {
+	Roles       []string `yaml:"roles"`
+	Permissions []string `yaml:"permissions"`
+}
+
+// Enforcer checks calls against the policy.
+type Enforcer struct {
+	roles   map[string]map[string]bool
+	methods map[string]Rule
+}
+
+// NewEnforcer creates a new instance of Enforcer.
+// It returns an error if a rule refers to a role or permission the policy doesn't declare.
+func NewEnforcer(cfg Config) (*Enforcer, error) {
+	e := &Enforcer{
+		roles:   make(map[string]map[string]bool, len(cfg.Roles)),
+		methods: cfg.Methods,
+	}
+
+	granted := make(map[string]bool)
+	for role, permissions := range cfg.Roles {
+		e.roles[role] = make(map[string]bool, len(permissions))
+		for _, permission := range permissions {
+			e.roles[role][permission] = true
+			granted[permission] = true
+		}
+	}
+
+	for method, rule := range cfg.Methods {
+		for _, role := range rule.Roles {
+			if !e.HasRole(role) {
+				return nil, fmt.Errorf("method %s: unknown role %s", method, role)
+			}
+		}
+		for _, permission := range rule.Permissions {
+			if !granted[permission] {
+				return nil, fmt.Errorf("method %s: permission %s is not granted to any role", method, permission)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// HasRole reports whether the role is declared in the policy.
+func (e *Enforcer) HasRole(role string) bool {
+	_, ok := e.roles[role]
+	return ok
+}
+
+// IsRestricted reports whether the policy has a rule for the method.
+func (e *Enforcer) IsRestricted(fullMethod string) bool {
+	_, ok := e.methods[fullMethod]
+	return ok
+}
+
+// Authorize checks whether the role is allowed to call the method.
+func (e *Enforcer) Authorize(fullMethod string, role string) error {
+	rule, ok := e.methods[fullMethod]
+	if !ok {
+		return nil
+	}
+
+	if len(rule.Roles) > 0 && !slices.Contains(rule.Roles, role) {
+		return ErrPermissionDenied
+	}
+	for _, permission := range rule.Permissions {
+		if !e.roles[role][permission] {
+			return ErrPermissionDenied
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	createArticle = "/proto.OLO/CreateArticle"
+	recordMetrics = "/proto.OLO/RecordMetrics"
+	getArticles   = "/proto.OLO/GetArticles"
+)
+
+// testConfig is a policy with a method restricted by permission and one restricted by role.
+func testConfig() Config {
+	return Config{
+		Roles: map[string][]string{
+			"USER":    {},
+			"ADMIN":   {"articles.write"},
+			"SERVICE": {},
+		},
+		Methods: map[string]Rule{
+			createArticle: {Permissions: []string{"articles.write"}},
+			recordMetrics: {Roles: []string{"SERVICE", "ADMIN"}},
+		},
+	}
+}
+
+func TestNewEnforcer(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "valid", cfg: testConfig()},
+		{name: "empty", cfg: Config{}},
+		{
+			name: "unknown role",
+			cfg: Config{
+				Roles:   map[string][]string{"USER": {}},
+				Methods: map[string]Rule{recordMetrics: {Roles: []string{"SERVICE"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown permission",
+			cfg: Config{
+				Roles:   map[string][]string{"ADMIN": {"articles.write"}},
+				Methods: map[string]Rule{createArticle: {Permissions: []string{"articles.delete"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEnforcer(tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	e, err := NewEnforcer(testConfig())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		method  string
+		role    string
+		wantErr bool
+	}{
+		{name: "allowed by permission", method: createArticle, role: "ADMIN"},
+		{name: "permission not granted", method: createArticle, role: "USER", wantErr: true},
+		{name: "allowed by role", method: recordMetrics, role: "SERVICE"},
+		{name: "role not listed", method: recordMetrics, role: "USER", wantErr: true},
+		{name: "undeclared role", method: createArticle, role: "ROOT", wantErr: true},
+		{name: "method not in the policy", method: getArticles, role: "USER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := e.Authorize(tt.method, tt.role)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrPermissionDenied)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.True(t, e.IsRestricted(createArticle))
+	assert.False(t, e.IsRestricted(getArticles))
+}